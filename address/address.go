@@ -0,0 +1,107 @@
+// Package address 把特征数据库中逗号分隔的名字解析成地址的各个部分
+//
+//	a := address.Parse("101 New Jersey 10, Whippany, NJ 07981, USA")
+//	// a.Street == "101 New Jersey 10", a.City == "Whippany", a.State == "NJ", a.PostalCode == "07981"
+//
+// 名字从后往前解析：最后一段不像州和邮编时是国家，两个字母的国家代码也是国家，例如 "US"，
+// 和州的缩写相同的代码（例如 "CA"）只有在前一段是州或者邮编时才是国家。然后是 "州 邮编"、"州" 或者 "邮编"，
+// 再往前一段是城市，剩下的部分都属于 Street，它也可能是地标的名字，例如 "Mid Hudson Psychiatric Center"。
+// 只有一段的名字整个作为 Street
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Address 是解析出来的地址，没有解析出来的部分为空
+type Address struct {
+	Street     string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+var (
+	// stateZip 匹配 "NJ 07981"、"NJ" 或者 "07981"，邮编可以带 ZIP+4 后缀
+	stateZip = regexp.MustCompile(`^(?:([A-Z]{2})(?:\s+(\d{5}(?:-\d{4})?))?|(\d{5}(?:-\d{4})?))$`)
+	digits   = regexp.MustCompile(`\d`)
+
+	// countryCodes 是 ISO 3166-1 的两个字母的国家代码，加上常用的 UK
+	countryCodes = set(`AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+		BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET
+		FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO
+		IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH
+		MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM
+		PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG
+		TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UK UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+	// usStates 是美国的州和 DC 的缩写
+	usStates = set(`AL AK AZ AR CA CO CT DE DC FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS MO MT NE NV NH NJ NM NY
+		NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY`)
+)
+
+func set(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, f := range strings.Fields(s) {
+		m[f] = true
+	}
+	return m
+}
+
+// Parse 解析 name，空的段会被忽略
+func Parse(name string) Address {
+	var parts []string
+	for _, p := range strings.Split(name, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	var a Address
+	if len(parts) <= 1 {
+		a.Street = strings.Join(parts, "")
+		return a
+	}
+	if last := parts[len(parts)-1]; isCountry(last, parts[len(parts)-2]) {
+		a.Country = last
+		parts = parts[:len(parts)-1]
+	}
+	region := false
+	if len(parts) > 1 {
+		if m := stateZip.FindStringSubmatch(parts[len(parts)-1]); m != nil {
+			a.State, a.PostalCode = m[1], m[2]
+			if m[3] != "" {
+				a.PostalCode = m[3]
+			}
+			parts = parts[:len(parts)-1]
+			region = true
+		}
+	}
+	// 州和邮编前面的一段总是城市
+	if len(parts) > 1 || region && len(parts) == 1 {
+		a.City = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	a.Street = strings.Join(parts, ", ")
+	return a
+}
+
+// isCountry 判断最后一段 last 是不是国家，prev 是它前面的一段
+func isCountry(last, prev string) bool {
+	if countryCodes[last] {
+		return !usStates[last] || stateZip.MatchString(prev)
+	}
+	return !stateZip.MatchString(last) && !digits.MatchString(last)
+}
+
+// Completeness 返回解析出来的部分占全部 5 个部分的比例
+func (a Address) Completeness() float64 {
+	n := 0
+	for _, s := range []string{a.Street, a.City, a.State, a.PostalCode, a.Country} {
+		if s != "" {
+			n++
+		}
+	}
+	return float64(n) / 5
+}
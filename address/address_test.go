@@ -0,0 +1,63 @@
+package address
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Address{
+		"101 New Jersey 10, Whippany, NJ 07981, USA":                 {"101 New Jersey 10", "Whippany", "NJ", "07981", "USA"},
+		"U.S. 6, Shohola, PA 18458, USA":                             {"U.S. 6", "Shohola", "PA", "18458", "USA"},
+		"Berkshire Valley Management Area Trail, Jefferson, NJ, USA": {"Berkshire Valley Management Area Trail", "Jefferson", "NJ", "", "USA"},
+		"Suite 5, 1 Main Street, Springfield, IL 62701-1234":         {"Suite 5, 1 Main Street", "Springfield", "IL", "62701-1234", ""},
+		"Trenton, 08608, USA":                                        {"", "Trenton", "", "08608", "USA"},
+		"Whippany, USA":                                              {"Whippany", "", "", "", "USA"},
+		"Kingston, NY":                                               {"", "Kingston", "NY", "", ""},
+		"1 Main St, Trenton, NJ 08608, US":                           {"1 Main St", "Trenton", "NJ", "08608", "US"},
+		"Whippany, US":                                               {"Whippany", "", "", "", "US"},
+		"Springfield, IN":                                            {"", "Springfield", "IN", "", ""},
+		"Toronto, ON, CA":                                            {"", "Toronto", "ON", "", "CA"},
+		"Mid Hudson Psychiatric Center":                              {Street: "Mid Hudson Psychiatric Center"},
+		" Patriots Path ,, Mendham , NJ 07945 , USA ":                {"Patriots Path", "Mendham", "NJ", "07945", "USA"},
+		"": {},
+	}
+	for name, want := range tests {
+		if got := Parse(name); got != want {
+			t.Errorf("Parse(%q) = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestParseDB(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/route_guide_db.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var features []struct{ Name string }
+	if err := json.Unmarshal(data, &features); err != nil {
+		t.Fatal(err)
+	}
+	// 数据库中有名字的特征都能解析出城市、州和国家
+	for _, f := range features {
+		if f.Name == "" {
+			continue
+		}
+		if a := Parse(f.Name); a.City == "" || a.State == "" || a.Country != "USA" {
+			t.Errorf("Parse(%q) = %+v", f.Name, a)
+		}
+	}
+}
+
+func TestCompleteness(t *testing.T) {
+	if got := Parse("101 New Jersey 10, Whippany, NJ 07981, USA").Completeness(); got != 1 {
+		t.Errorf("Completeness() = %v, want 1", got)
+	}
+	if got := Parse("Berkshire Valley Management Area Trail, Jefferson, NJ, USA").Completeness(); got != 0.8 {
+		t.Errorf("Completeness() = %v, want 0.8", got)
+	}
+	if got := (Address{}).Completeness(); got != 0 {
+		t.Errorf("Completeness() = %v, want 0", got)
+	}
+}
@@ -0,0 +1,218 @@
+// Package binlog 把 gRPC 调用记录到二进制日志文件中，并且可以把记录的调用在另一个服务上重放
+//
+// 文件格式和 gRPC 的 binary logging 相同 (gRFC A16)：每条记录是一个 grpc.binarylog.v1.GrpcLogEntry，
+// 前面是 4 字节大端序的长度。和 gRPC 自带的 binary logging 不同，记录是由拦截器完成的，
+// 可以通过配置打开，不需要设置 GRPC_BINARY_LOG_FILTER 环境变量。
+//
+// 记录的内容包括方法名、metadata、超时时间、每条消息和最后的状态，不包括服务端发送的 header。
+// authorization 等敏感的 metadata 不会被记录，见 skipMetadata
+package binlog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	binlogpb "google.golang.org/grpc/binarylog/grpc_binarylog_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxEntrySize 是读取时允许的单条记录的最大长度
+const maxEntrySize = 64 << 20
+
+// Writer 把记录写入文件，可以被多个调用并发使用
+type Writer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer
+	nextID uint64
+}
+
+// Create 创建 path 并返回写入它的 Writer，path 已经存在时会被清空
+func Create(path string) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{out: f, closer: f}, nil
+}
+
+// NewWriter 返回写入 w 的 Writer，Close 不会关闭 w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{out: w}
+}
+
+// Write 写入一条记录
+func (w *Writer) Write(e *binlogpb.GrpcLogEntry) error {
+	b, err := proto.Marshal(e)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(buf, uint32(len(b)))
+	copy(buf[4:], b)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.out.Write(buf)
+	return err
+}
+
+// Close 关闭 Create 打开的文件
+func (w *Writer) Close() error {
+	if w.closer == nil {
+		return nil
+	}
+	return w.closer.Close()
+}
+
+// Reader 按顺序读取记录
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader 返回从 r 中读取记录的 Reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next 返回下一条记录，没有更多记录时返回 io.EOF
+func (r *Reader) Next() (*binlogpb.GrpcLogEntry, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n > maxEntrySize {
+		return nil, fmt.Errorf("binlog: entry of %d bytes is too large", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	e := new(binlogpb.GrpcLogEntry)
+	if err := proto.Unmarshal(b, e); err != nil {
+		return nil, fmt.Errorf("binlog: %v", err)
+	}
+	return e, nil
+}
+
+// skipMetadata 判断 key 是否不需要记录：传输层的 header 和认证信息
+func skipMetadata(key string) bool {
+	switch key {
+	case "content-type", "user-agent", "te", "authorization":
+		return true
+	}
+	return strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-")
+}
+
+func toProtoMetadata(md metadata.MD) *binlogpb.Metadata {
+	out := &binlogpb.Metadata{}
+	for k, vs := range md {
+		if skipMetadata(k) {
+			continue
+		}
+		for _, v := range vs {
+			out.Entry = append(out.Entry, &binlogpb.MetadataEntry{Key: k, Value: []byte(v)})
+		}
+	}
+	return out
+}
+
+func fromProtoMetadata(md *binlogpb.Metadata) metadata.MD {
+	out := metadata.MD{}
+	for _, e := range md.GetEntry() {
+		out.Append(e.GetKey(), string(e.GetValue()))
+	}
+	return out
+}
+
+// callLogger 记录一次调用的所有记录
+type callLogger struct {
+	w      *Writer
+	id     uint64
+	seq    uint64
+	logger binlogpb.GrpcLogEntry_Logger
+	// trailerOnce 保证结束的记录只写一次
+	trailerOnce sync.Once
+}
+
+func (w *Writer) newCall(logger binlogpb.GrpcLogEntry_Logger) *callLogger {
+	return &callLogger{w: w, id: atomic.AddUint64(&w.nextID, 1), logger: logger}
+}
+
+func (c *callLogger) write(e *binlogpb.GrpcLogEntry) {
+	e.Timestamp = timestamppb.Now()
+	e.CallId = c.id
+	e.SequenceIdWithinCall = atomic.AddUint64(&c.seq, 1)
+	e.Logger = c.logger
+	// 记录失败不应该影响调用本身
+	_ = c.w.Write(e)
+}
+
+func (c *callLogger) clientHeader(method, authority string, md metadata.MD, deadline time.Time, hasDeadline bool) {
+	h := &binlogpb.ClientHeader{
+		Metadata:   toProtoMetadata(md),
+		MethodName: method,
+		Authority:  authority,
+	}
+	if hasDeadline {
+		h.Timeout = durationpb.New(time.Until(deadline))
+	}
+	c.write(&binlogpb.GrpcLogEntry{
+		Type:    binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HEADER,
+		Payload: &binlogpb.GrpcLogEntry_ClientHeader{ClientHeader: h},
+	})
+}
+
+func (c *callLogger) message(typ binlogpb.GrpcLogEntry_EventType, m interface{}) {
+	var data []byte
+	switch m := m.(type) {
+	case *[]byte:
+		data = *m
+	case proto.Message:
+		data, _ = proto.Marshal(m)
+	default:
+		return
+	}
+	c.write(&binlogpb.GrpcLogEntry{
+		Type:    typ,
+		Payload: &binlogpb.GrpcLogEntry_Message{Message: &binlogpb.Message{Length: uint32(len(data)), Data: data}},
+	})
+}
+
+func (c *callLogger) halfClose() {
+	c.write(&binlogpb.GrpcLogEntry{Type: binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HALF_CLOSE})
+}
+
+func (c *callLogger) trailer(err error, md metadata.MD) {
+	c.trailerOnce.Do(func() {
+		st := status.Convert(err)
+		var details []byte
+		if p := st.Proto(); len(p.GetDetails()) > 0 {
+			details, _ = proto.Marshal(p)
+		}
+		c.write(&binlogpb.GrpcLogEntry{
+			Type: binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_TRAILER,
+			Payload: &binlogpb.GrpcLogEntry_Trailer{Trailer: &binlogpb.Trailer{
+				Metadata:      toProtoMetadata(md),
+				StatusCode:    uint32(st.Code()),
+				StatusMessage: st.Message(),
+				StatusDetails: details,
+			}},
+		})
+	})
+}
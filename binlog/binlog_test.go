@@ -0,0 +1,265 @@
+package binlog_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"gRPCDemo/binlog"
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"google.golang.org/grpc"
+	binlogpb "google.golang.org/grpc/binarylog/grpc_binarylog_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// record 对 env 发起一次 GetFeature、ListFeatures、RecordRoute 和 RouteChat 调用
+func record(t *testing.T, env *servicetest.Env) {
+	t.Helper()
+	ctx := metadata.AppendToOutgoingContext(testContext(t), "x-request-id", "abc", "authorization", "secret")
+	features := servicetest.Features()
+
+	if _, err := env.RouteGuide.GetFeature(ctx, features[0].Location); err != nil {
+		t.Fatalf("GetFeature() = %v", err)
+	}
+
+	list, err := env.RouteGuide.ListFeatures(ctx, &pb.Rectangle{
+		Lo: &pb.Point{Latitude: 400000000, Longitude: -750000000},
+		Hi: &pb.Point{Latitude: 420000000, Longitude: -730000000},
+	})
+	if err != nil {
+		t.Fatalf("ListFeatures() = %v", err)
+	}
+	for {
+		if _, err := list.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("ListFeatures().Recv() = %v", err)
+		}
+	}
+
+	rec, err := env.RouteGuide.RecordRoute(ctx)
+	if err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+	for _, f := range features[:3] {
+		if err := rec.Send(f.Location); err != nil {
+			t.Fatalf("RecordRoute().Send() = %v", err)
+		}
+	}
+	if _, err := rec.CloseAndRecv(); err != nil {
+		t.Fatalf("RecordRoute().CloseAndRecv() = %v", err)
+	}
+
+	// 校验失败的调用也会被记录
+	if _, err := env.RouteGuide.GetFeature(ctx, &pb.Point{Latitude: 1e9}); err == nil {
+		t.Fatal("GetFeature(invalid) = nil error")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := binlog.NewWriter(&buf)
+	entries := []*binlogpb.GrpcLogEntry{
+		{CallId: 1, Type: binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HEADER},
+		{CallId: 1, Type: binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE, Payload: &binlogpb.GrpcLogEntry_Message{
+			Message: &binlogpb.Message{Length: 3, Data: []byte("abc")},
+		}},
+		{CallId: 1, Type: binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_TRAILER},
+	}
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			t.Fatalf("Write() = %v", err)
+		}
+	}
+
+	r := binlog.NewReader(&buf)
+	for i, want := range entries {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next() #%d = %v", i, err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("Next() #%d = %v, want %v", i, got, want)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() at end = %v, want io.EOF", err)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w := binlog.NewWriter(&buf)
+	if err := w.Write(&binlogpb.GrpcLogEntry{CallId: 1, Type: binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HEADER}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()[:buf.Len()-1]
+	if _, err := binlog.NewReader(bytes.NewReader(data)).Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Next() = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestServerRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	w := binlog.NewWriter(&buf)
+	env := servicetest.Start(t, servicetest.WithServerOptions(
+		grpc.ChainUnaryInterceptor(binlog.UnaryServerInterceptor(w), validate.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(binlog.StreamServerInterceptor(w), validate.StreamServerInterceptor),
+	))
+	record(t, env)
+
+	calls, err := binlog.ReadCalls(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadCalls() = %v", err)
+	}
+	wantMethods := []string{
+		"/routeguide.RouteGuide/GetFeature",
+		"/routeguide.RouteGuide/ListFeatures",
+		"/routeguide.RouteGuide/RecordRoute",
+		"/routeguide.RouteGuide/GetFeature",
+	}
+	if len(calls) != len(wantMethods) {
+		t.Fatalf("ReadCalls() returned %d calls, want %d", len(calls), len(wantMethods))
+	}
+	for i, c := range calls {
+		if c.Method != wantMethods[i] {
+			t.Errorf("call %d method = %q, want %q", i, c.Method, wantMethods[i])
+		}
+		if got := c.Metadata.Get("x-request-id"); len(got) != 1 || got[0] != "abc" {
+			t.Errorf("call %d x-request-id = %v, want [abc]", i, got)
+		}
+		if got := c.Metadata.Get("authorization"); len(got) != 0 {
+			t.Errorf("call %d recorded authorization %v", i, got)
+		}
+		if c.Timeout <= 0 {
+			t.Errorf("call %d timeout = %v, want > 0", i, c.Timeout)
+		}
+	}
+	if n := len(calls[1].Responses); n == 0 {
+		t.Errorf("ListFeatures recorded no responses")
+	}
+	if n := len(calls[2].Requests); n != 3 || !calls[2].HalfClosed {
+		t.Errorf("RecordRoute recorded %d requests, half closed %v, want 3 and true", n, calls[2].HalfClosed)
+	}
+	if got := calls[3].Status.Code(); got != codes.InvalidArgument {
+		t.Errorf("invalid GetFeature status = %v, want InvalidArgument", got)
+	}
+
+	// 在另一个服务上重放，除了 RecordRoute 的耗时之外，响应应该完全相同，cli replay 的比较见 cmd/cli/replay.go
+	other := servicetest.Start(t, servicetest.WithServerOptions(
+		grpc.ChainUnaryInterceptor(validate.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(validate.StreamServerInterceptor),
+	))
+	for i, c := range calls {
+		got, err := binlog.Replay(testContext(t), other.Conn, c)
+		if err != nil {
+			t.Fatalf("Replay(call %d) = %v", i, err)
+		}
+		if got.Status.Code() != c.Status.Code() {
+			t.Errorf("Replay(call %d) status = %v, want %v", i, got.Status, c.Status)
+		}
+		if len(got.Responses) != len(c.Responses) {
+			t.Fatalf("Replay(call %d) got %d responses, want %d", i, len(got.Responses), len(c.Responses))
+		}
+		if c.Method == "/routeguide.RouteGuide/RecordRoute" {
+			// 每次调用的耗时不同，忽略 elapsed_time 之后比较
+			var want, summary pb.RouteSummary
+			if err := proto.Unmarshal(c.Responses[0].Data, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := proto.Unmarshal(got.Responses[0].Data, &summary); err != nil {
+				t.Fatal(err)
+			}
+			want.ElapsedTime, summary.ElapsedTime = 0, 0
+			if !proto.Equal(&summary, &want) || want.PointCount != 3 {
+				t.Errorf("Replay(call %d) summary = %v, want %v", i, &summary, &want)
+			}
+			continue
+		}
+		for j := range c.Responses {
+			if !bytes.Equal(got.Responses[j].Data, c.Responses[j].Data) {
+				t.Errorf("Replay(call %d) response %d differs", i, j)
+			}
+		}
+	}
+}
+
+func TestClientRecordAndReplayChat(t *testing.T) {
+	var buf bytes.Buffer
+	w := binlog.NewWriter(&buf)
+	env := servicetest.Start(t, servicetest.WithDialOptions(
+		grpc.WithChainUnaryInterceptor(binlog.UnaryClientInterceptor(w)),
+		grpc.WithChainStreamInterceptor(binlog.StreamClientInterceptor(w)),
+	))
+	ctx := testContext(t)
+
+	chat, err := env.RouteGuide.RouteChat(ctx)
+	if err != nil {
+		t.Fatalf("RouteChat() = %v", err)
+	}
+	for _, msg := range []string{"first", "second"} {
+		if err := chat.Send(&pb.RouteNode{Location: &pb.Point{Latitude: 1, Longitude: 1}, Message: msg}); err != nil {
+			t.Fatalf("RouteChat().Send() = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	chat.CloseSend()
+	for {
+		if _, err := chat.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("RouteChat().Recv() = %v", err)
+		}
+	}
+
+	calls, err := binlog.ReadCalls(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadCalls() = %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("ReadCalls() returned %d calls, want 1", len(calls))
+	}
+	c := calls[0]
+	if c.Logger != binlogpb.GrpcLogEntry_LOGGER_CLIENT || c.Status.Code() != codes.OK {
+		t.Fatalf("call logger = %v, status = %v", c.Logger, c.Status)
+	}
+	// 第一条消息返回 1 条记录，第二条返回 2 条
+	if len(c.Requests) != 2 || len(c.Responses) != 3 {
+		t.Fatalf("recorded %d requests and %d responses, want 2 and 3", len(c.Requests), len(c.Responses))
+	}
+	if c.Requests[1].Offset < 20*time.Millisecond {
+		t.Errorf("second request offset = %v, want >= 20ms", c.Requests[1].Offset)
+	}
+
+	other := servicetest.Start(t)
+	start := time.Now()
+	got, err := binlog.Replay(testContext(t), other.Conn, c, binlog.WithTiming())
+	if err != nil {
+		t.Fatalf("Replay() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < c.Requests[1].Offset {
+		t.Errorf("Replay(WithTiming) took %v, want >= %v", elapsed, c.Requests[1].Offset)
+	}
+	if len(got.Responses) != 3 {
+		t.Fatalf("Replay() got %d responses, want 3", len(got.Responses))
+	}
+	for i := range got.Responses {
+		if !bytes.Equal(got.Responses[i].Data, c.Responses[i].Data) {
+			t.Errorf("Replay() response %d differs", i)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package binlog
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	binlogpb "google.golang.org/grpc/binarylog/grpc_binarylog_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor 把服务端收到的一元调用记录到 w 中
+func UnaryServerInterceptor(w *Writer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c := w.newCall(binlogpb.GrpcLogEntry_LOGGER_SERVER)
+		serverHeader(ctx, c, info.FullMethod)
+		c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE, req)
+		c.halfClose()
+		resp, err := handler(ctx, req)
+		if err == nil {
+			c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE, resp)
+		}
+		c.trailer(err, nil)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 把服务端收到的流式调用记录到 w 中
+func StreamServerInterceptor(w *Writer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c := w.newCall(binlogpb.GrpcLogEntry_LOGGER_SERVER)
+		serverHeader(ss.Context(), c, info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, c: c, clientStream: info.IsClientStream})
+		c.trailer(err, nil)
+		return err
+	}
+}
+
+func serverHeader(ctx context.Context, c *callLogger, method string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var authority string
+	if v := md.Get(":authority"); len(v) > 0 {
+		authority = v[0]
+	}
+	deadline, ok := ctx.Deadline()
+	c.clientHeader(method, authority, md, deadline, ok)
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	c *callLogger
+	// clientStream 为 false 时客户端只发送一个请求，handler 不会再读到 io.EOF
+	clientStream bool
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE, m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	switch err {
+	case nil:
+		s.c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE, m)
+		if !s.clientStream {
+			s.c.halfClose()
+		}
+	case io.EOF:
+		s.c.halfClose()
+	}
+	return err
+}
+
+// UnaryClientInterceptor 把客户端发起的一元调用记录到 w 中
+func UnaryClientInterceptor(w *Writer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		c := w.newCall(binlogpb.GrpcLogEntry_LOGGER_CLIENT)
+		clientHeader(ctx, c, method, cc)
+		c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE, req)
+		c.halfClose()
+		var trailer metadata.MD
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+		if err == nil {
+			c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE, reply)
+		}
+		c.trailer(err, trailer)
+		return err
+	}
+}
+
+// StreamClientInterceptor 把客户端发起的流式调用记录到 w 中，
+// 调用的结束状态在 RecvMsg 返回错误（包括 io.EOF）时记录
+func StreamClientInterceptor(w *Writer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		c := w.newCall(binlogpb.GrpcLogEntry_LOGGER_CLIENT)
+		clientHeader(ctx, c, method, cc)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			c.trailer(err, nil)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, c: c}, nil
+	}
+}
+
+func clientHeader(ctx context.Context, c *callLogger, method string, cc *grpc.ClientConn) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	deadline, ok := ctx.Deadline()
+	c.clientHeader(method, cc.Target(), md, deadline, ok)
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	c *callLogger
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE, m)
+	}
+	return err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	s.c.halfClose()
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		s.c.message(binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE, m)
+		return nil
+	}
+	if err == io.EOF {
+		s.c.trailer(nil, s.ClientStream.Trailer())
+	} else {
+		s.c.trailer(err, s.ClientStream.Trailer())
+	}
+	return err
+}
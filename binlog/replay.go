@@ -0,0 +1,230 @@
+package binlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	binlogpb "google.golang.org/grpc/binarylog/grpc_binarylog_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Message 是调用中的一条消息，Offset 是相对于调用开始的时间
+type Message struct {
+	Offset time.Duration
+	Data   []byte
+}
+
+// Call 是从日志中还原出来的一次调用
+type Call struct {
+	ID       uint64
+	Logger   binlogpb.GrpcLogEntry_Logger
+	Method   string
+	Metadata metadata.MD
+	// Timeout 是调用开始时剩余的超时时间，为 0 表示没有 deadline
+	Timeout   time.Duration
+	Start     time.Time
+	Requests  []Message
+	Responses []Message
+	// HalfClosed 表示客户端发送完了所有的请求
+	HalfClosed bool
+	// Status 是调用结束的状态，调用没有结束时为 nil
+	Status *status.Status
+}
+
+// ReadCalls 读取 r 中的所有记录，按照调用开始的顺序返回。没有 CLIENT_HEADER 的调用会被忽略
+func ReadCalls(r io.Reader) ([]*Call, error) {
+	type key struct {
+		logger binlogpb.GrpcLogEntry_Logger
+		id     uint64
+	}
+	var calls []*Call
+	byKey := make(map[key]*Call)
+	rd := NewReader(r)
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			return calls, nil
+		}
+		if err != nil {
+			return calls, err
+		}
+		k := key{e.GetLogger(), e.GetCallId()}
+		ts := e.GetTimestamp().AsTime()
+		if h := e.GetClientHeader(); h != nil {
+			c := &Call{
+				ID:       e.GetCallId(),
+				Logger:   e.GetLogger(),
+				Method:   h.GetMethodName(),
+				Metadata: fromProtoMetadata(h.GetMetadata()),
+				Start:    ts,
+			}
+			if h.GetTimeout() != nil {
+				c.Timeout = h.GetTimeout().AsDuration()
+			}
+			byKey[k] = c
+			calls = append(calls, c)
+			continue
+		}
+		c := byKey[k]
+		if c == nil {
+			continue
+		}
+		switch e.GetType() {
+		case binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE:
+			c.Requests = append(c.Requests, Message{Offset: ts.Sub(c.Start), Data: e.GetMessage().GetData()})
+		case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE:
+			c.Responses = append(c.Responses, Message{Offset: ts.Sub(c.Start), Data: e.GetMessage().GetData()})
+		case binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HALF_CLOSE:
+			c.HalfClosed = true
+		case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_TRAILER:
+			c.Status = trailerStatus(e.GetTrailer())
+		case binlogpb.GrpcLogEntry_EVENT_TYPE_CANCEL:
+			c.Status = status.New(codes.Canceled, "canceled")
+		}
+	}
+}
+
+func trailerStatus(t *binlogpb.Trailer) *status.Status {
+	if len(t.GetStatusDetails()) > 0 {
+		p := new(spb.Status)
+		if err := proto.Unmarshal(t.GetStatusDetails(), p); err == nil {
+			return status.FromProto(p)
+		}
+	}
+	return status.New(codes.Code(t.GetStatusCode()), t.GetStatusMessage())
+}
+
+// ReplayOption 配置 Replay
+type ReplayOption func(*replayOptions)
+
+type replayOptions struct {
+	timing bool
+}
+
+// WithTiming 让 Replay 按照记录中的时间间隔发送请求，默认一次性发送所有请求
+func WithTiming() ReplayOption {
+	return func(o *replayOptions) {
+		o.timing = true
+	}
+}
+
+// Replay 在 conn 上重新发起 call，返回的 Call 中包含新的响应和状态。
+// 请求和响应都以字节的形式转发，不需要知道方法的消息类型
+func Replay(ctx context.Context, conn grpc.ClientConnInterface, call *Call, opts ...ReplayOption) (*Call, error) {
+	var o replayOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if call.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, call.Timeout)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, call.Metadata.Copy()))
+	defer cancel()
+
+	got := &Call{
+		Method:   call.Method,
+		Metadata: call.Metadata,
+		Timeout:  call.Timeout,
+		Start:    time.Now(),
+		Requests: call.Requests,
+	}
+	// 一元调用也可以当成双向流来发送，服务端只会读取一个请求
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	stream, err := conn.NewStream(ctx, desc, call.Method, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		got.Status = status.Convert(err)
+		return got, nil
+	}
+
+	// 请求在单独的 goroutine 中发送，RouteChat 这样的双向流需要同时读取响应
+	sendDone := make(chan error, 1)
+	go func() {
+		sendDone <- sendRequests(ctx, stream, call.Requests, got.Start, o.timing, call.HalfClosed)
+	}()
+
+	// 记录中被客户端取消或者没有结束的调用（例如客户端在流中途退出），在收到同样多的响应之后取消，
+	// 这样重放得到的状态和记录中的相同
+	abandon := call.Status == nil || call.Status.Code() == codes.Canceled
+	var sendErr error
+	sent := false
+	for {
+		if !sent && abandon && len(got.Responses) >= len(call.Responses) {
+			sendErr, sent = <-sendDone, true
+			cancel()
+		}
+		var data []byte
+		err := stream.RecvMsg(&data)
+		if err == io.EOF {
+			got.Status = status.New(codes.OK, "")
+			break
+		}
+		if err != nil {
+			got.Status = status.Convert(err)
+			break
+		}
+		got.Responses = append(got.Responses, Message{Offset: time.Since(got.Start), Data: data})
+	}
+	got.HalfClosed = call.HalfClosed
+	cancel()
+	if !sent {
+		sendErr = <-sendDone
+	}
+	if sendErr != nil && sendErr != io.EOF && got.Status.Code() == codes.OK {
+		return got, fmt.Errorf("binlog: failed to send requests: %v", sendErr)
+	}
+	return got, nil
+}
+
+func sendRequests(ctx context.Context, stream grpc.ClientStream, requests []Message, start time.Time, timing, closeSend bool) error {
+	for _, m := range requests {
+		if timing {
+			select {
+			case <-time.After(time.Until(start.Add(m.Offset))):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		data := m.Data
+		if err := stream.SendMsg(&data); err != nil {
+			// 服务端已经结束调用，错误由 RecvMsg 返回
+			return err
+		}
+	}
+	if !closeSend {
+		return nil
+	}
+	return stream.CloseSend()
+}
+
+// rawCodec 不做任何编解码，直接转发消息的字节
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unexpected type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: unexpected type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -1,14 +1,29 @@
 // Package main implements a client for Greeter service.
+//
+// Besides the built-in demo calls, it can talk to any service that has server
+// reflection enabled:
+//
+//	cli describe [symbol]
+//	cli invoke <pkg.Service/Method> [json|-]
+//	cli replay <binary log file>
+//	cli room <room id>
+//	cli geofence < positions
 package main
 
 import (
 	"context"
 	"flag"
 	"fmt"
+	"gRPCDemo/binlog"
+	"gRPCDemo/config"
 	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/client"
+	_ "gRPCDemo/routeguide/resolver"
 	"io"
+	"io/ioutil"
 	"log"
 	"math/rand"
+	"os"
 	"time"
 
 	"google.golang.org/grpc"
@@ -16,46 +31,45 @@ import (
 )
 
 var (
-	tls                = flag.Bool("tls", false, "Connection use TLS")
-	caFile             = flag.String("ca_file", "", "the file containing the ca root cert file")
-	serverAddr         = flag.String("server_addr", "localhost:10000", "Server Address")
-	serverHostOverride = flag.String("server_host_override", "dev.bwangel.abc", "The server name used to verify the hostname returned by the TLS handshake")
+	configFile  = flag.String("config", "", "A YAML or TOML config file, flags and ROUTEGUIDE_* environment variables override it")
+	printConfig = flag.Bool("print_config", false, "Print the effective config and exit")
+
+	cfg = config.DefaultClient()
 )
 
-func printFeature(client pb.RouteGuideClient, point *pb.Point) {
-	log.Printf("Getting feature for point(%d, %d)", point.Latitude, point.Longitude)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func init() {
+	flag.BoolVar(&cfg.TLS.Enabled, "tls", cfg.TLS.Enabled, "Connection use TLS")
+	flag.StringVar(&cfg.TLS.CAFile, "ca_file", cfg.TLS.CAFile, "the file containing the ca root cert file")
+	flag.StringVar(&cfg.ServerAddr, "server_addr", cfg.ServerAddr, "Server Address, unix:///path for a Unix domain socket, routeguide:///host1:port,host2:port or routeguide:///file/<path> for several replicas")
+	flag.StringVar(&cfg.TLS.ServerHostOverride, "server_host_override", cfg.TLS.ServerHostOverride, "The server name used to verify the hostname returned by the TLS handshake")
+	flag.StringVar(&cfg.LBPolicy, "lb_policy", cfg.LBPolicy, "Load balancing policy across replicas, round_robin or least_request, pick_first is used if empty")
+	flag.StringVar(&cfg.ServiceConfigFile, "service_config", cfg.ServiceConfigFile, "A json file containing the service config, the built-in retry and hedging config is used if empty")
+	flag.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "Timeout of calls without a deadline")
+	flag.StringVar(&cfg.BinaryLog, "binary_log", cfg.BinaryLog, "Record every RPC to this binary log file")
+	config.StringsVar(flag.CommandLine, &cfg.Metadata, "metadata", "Comma separated key=value metadata sent with every call, e.g. x-echo-responder=reverse")
+}
 
-	feature, err := client.GetFeature(ctx, point)
+func printFeature(c *client.Client, point *pb.Point) {
+	log.Printf("Getting feature for point(%d, %d)", point.Latitude, point.Longitude)
+	feature, err := c.GetFeature(context.Background(), point)
 	if err != nil {
-		log.Fatalf("%v.GetFeatures(_) = _, %v: ", client, err)
+		log.Fatalf("GetFeature(_) = _, %v: ", err)
 	}
 	log.Println(feature)
 }
 
-func printFeatures(client pb.RouteGuideClient, rect *pb.Rectangle) {
+func printFeatures(c *client.Client, rect *pb.Rectangle) {
 	log.Printf("Looking for features within %v", rect)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	stream, err := client.ListFeatures(ctx, rect)
-	if err != nil {
-		log.Fatalf("%v.ListFeatures(_) = _, %v", client, err)
-	}
-
-	for {
-		feature, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatalf("%v.ListFeatures(_) = _, %v", client, err)
-		}
+	err := c.ListFeatures(context.Background(), rect, func(feature *pb.Feature) error {
 		log.Printf("Feature: name: %q, point: (%v, %v)\n",
 			feature.GetName(),
 			feature.GetLocation().GetLatitude(),
 			feature.GetLocation().GetLongitude(),
 		)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("ListFeatures(_) = _, %v", err)
 	}
 }
 
@@ -65,7 +79,7 @@ func randomPoint(r *rand.Rand) *pb.Point {
 	return &pb.Point{Latitude: lat, Longitude: long}
 }
 
-func runRecordRoute(client pb.RouteGuideClient) {
+func runRecordRoute(c *client.Client) {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	pointCount := int(r.Int31n(100)) + 2
 	var points []*pb.Point
@@ -77,27 +91,16 @@ func runRecordRoute(client pb.RouteGuideClient) {
 		Longitude: -743999179,
 	})
 	log.Printf("Traversing %d points", len(points))
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	stream, err := client.RecordRoute(ctx)
+	reply, err := c.RecordRoute(context.Background(), points)
 	if err != nil {
-		log.Fatalf("%v.RecordRoute(_) = _, %v", client, err)
-	}
-	for _, point := range points {
-		if err := stream.Send(point); err != nil {
-			log.Fatalf("%v.Send(%v) = %v", stream, point, err)
-		}
-	}
-	reply, err := stream.CloseAndRecv()
-	if err != nil {
-		log.Fatalf("%v.CloseAndRecv() got error %v, want %v", stream, err, nil)
+		log.Fatalf("RecordRoute(_) = _, %v", err)
 	}
 	log.Printf("Route summary: %v", reply)
 	log.Printf("Route summary time: %v Milliseconds", reply.ElapsedTime)
 }
 
-func runRouteChat(client pb.RouteGuideClient) {
+func runRouteChat(c *client.Client) {
 	notes := []*pb.RouteNode{
 		{Location: &pb.Point{Latitude: 0, Longitude: 1}, Message: "1st message"},
 		{Location: &pb.Point{Latitude: 0, Longitude: 2}, Message: "2nd message"},
@@ -106,76 +109,103 @@ func runRouteChat(client pb.RouteGuideClient) {
 		{Location: &pb.Point{Latitude: 0, Longitude: 2}, Message: "5th message"},
 		{Location: &pb.Point{Latitude: 0, Longitude: 3}, Message: "6th message"},
 	}
-	ctx, cancal := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancal()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
 
-	stream, err := client.RouteChat(ctx)
+	session, err := c.RouteChat(ctx)
 	if err != nil {
-		log.Fatalf("%v.RouteChat(_) = _, %v", client, err)
+		log.Fatalf("RouteChat(_) = _, %v", err)
 	}
-	watic := make(chan struct{})
-	go func() {
-		for {
-			in, err := stream.Recv()
-			if err == io.EOF {
-				close(watic)
-				return
-			}
-			if err != nil {
-				log.Fatalf("failed to receive a note: %v", err)
-			}
-			log.Printf("Got message %s at point(%d,%d)", in.Message, in.Location.Latitude, in.Location.Longitude)
-		}
-	}()
-
+	defer session.Close()
 	for _, note := range notes {
-		if err := stream.Send(note); err != nil {
+		if err := session.Send(note); err != nil {
 			log.Fatalf("Failed to send note %v", err)
 		}
 	}
-	stream.CloseSend()
-	<-watic
+	session.CloseSend()
+
+	for in := range session.Notes() {
+		log.Printf("Got message %s at point(%d,%d)", in.Message, in.Location.Latitude, in.Location.Longitude)
+	}
+	if err := session.Err(); err != nil {
+		log.Fatalf("failed to receive a note: %v", err)
+	}
 }
 
+// conversations 在不同的 goroutine 中发送问题和接收回答，不假设每个问题正好有一个回答。
+// 服务端的异步模式可以用 -metadata x-echo-async=true,x-echo-answers=3,x-echo-heartbeat=1s 打开
 func conversations(client pb.EchoClient) {
 	stream, err := client.Conversations(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for i := 0; i < 5; i++ {
-		err := stream.Send(&pb.StreamRequest{
-			Question: fmt.Sprintf("Stream client rpc %d", i),
-		})
-		if err != nil {
+	go func() {
+		for i := 0; i < 5; i++ {
+			err := stream.Send(&pb.StreamRequest{
+				Question: fmt.Sprintf("Stream client rpc %d", i),
+			})
+			if err != nil {
+				// 错误由 Recv 返回
+				return
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
 			log.Fatalln(err)
 		}
+	}()
+
+	for {
 		res, err := stream.Recv()
 		if err == io.EOF {
-			break
+			return
 		}
-
 		if err != nil {
 			log.Fatalln(err)
 		}
-
-		log.Println(res.Answer)
-	}
-
-	err = stream.CloseSend()
-	if err != nil {
-		log.Fatalln(err)
+		switch {
+		case res.Heartbeat != nil:
+			log.Printf("heartbeat %s", res.Heartbeat.AsTime().Format(time.RFC3339Nano))
+		case res.ReplyTo > 0:
+			log.Printf("#%d.%d %s", res.ReplyTo, res.Part, res.Answer)
+		default:
+			log.Println(res.Answer)
+		}
 	}
 }
 
 func main() {
 	flag.Parse()
+	if err := config.Load(flag.CommandLine, *configFile, cfg); err != nil {
+		log.Fatalln(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalln(err)
+	}
+	if *printConfig {
+		fmt.Print(config.Dump(cfg))
+		return
+	}
+
 	var opts []grpc.DialOption
-	if *tls {
-		if *caFile == "" {
-			*caFile = "/home/xuyundong/Certs/cacert.pem"
+	// -metadata 在最外层，这样二进制日志中也会记录这些 metadata
+	if len(cfg.Metadata) > 0 {
+		opts = append(opts, metadataOptions(cfg.Metadata)...)
+	}
+	// 二进制日志在 -metadata 之后，记录的是调用方看到的请求和响应，而不是每一次重试
+	if cfg.BinaryLog != "" {
+		w, err := binlog.Create(cfg.BinaryLog)
+		if err != nil {
+			log.Fatalf("failed to create binary log: %v", err)
 		}
-		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverHostOverride)
+		defer w.Close()
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(binlog.UnaryClientInterceptor(w)),
+			grpc.WithChainStreamInterceptor(binlog.StreamClientInterceptor(w)),
+		)
+	}
+	if cfg.TLS.Enabled {
+		creds, err := credentials.NewClientTLSFromFile(cfg.TLS.CAFile, cfg.TLS.ServerHostOverride)
 		if err != nil {
 			log.Fatalf("Failed to create TLC credentials %v", err)
 		}
@@ -184,27 +214,62 @@ func main() {
 		opts = append(opts, grpc.WithInsecure())
 	}
 
-	opts = append(opts, grpc.WithBlock())
-	log.Printf("Start to dial with %v\n", *serverAddr)
-	conn, err := grpc.Dial(*serverAddr, opts...)
+	var serviceConfig string
+	if cfg.ServiceConfigFile != "" {
+		data, err := ioutil.ReadFile(cfg.ServiceConfigFile)
+		if err != nil {
+			log.Fatalf("failed to read service config: %v", err)
+		}
+		serviceConfig = string(data)
+	}
+	// 重试日志的拦截器在 hedging 的外层，一次调用的所有尝试（包括 hedging 发出的调用）共用一个计数器
+	opts = append(opts, retryLoggingOptions()...)
+	serviceConfig, err := client.WithLoadBalancing(serviceConfig, cfg.LBPolicy)
+	if err != nil {
+		log.Fatalf("failed to configure load balancing: %v", err)
+	}
+	scOpts, err := client.DialOptions(serviceConfig)
+	if err != nil {
+		log.Fatalf("failed to load service config: %v", err)
+	}
+	opts = append(opts, scOpts...)
+
+	log.Printf("Start to dial with %v\n", cfg.ServerAddr)
+	conn, err := grpc.Dial(cfg.ServerAddr, opts...)
 	if err != nil {
 		log.Fatalf("failed to connect: %v", err)
 	}
 	defer conn.Close()
 
-	//client := pb.NewRouteGuideClient(conn)
-	//printFeature(client, &pb.Point{Latitude: 407838351, Longitude: -746143763})
+	//c := client.New(conn, client.WithTimeout(cfg.Timeout))
+	//printFeature(c, &pb.Point{Latitude: 407838351, Longitude: -746143763})
 	//// Looking for features missing
-	//printFeature(client, &pb.Point{Latitude: 1, Longitude: 1})
+	//printFeature(c, &pb.Point{Latitude: 1, Longitude: 1})
 	//
-	//printFeatures(client, &pb.Rectangle{
+	//printFeatures(c, &pb.Rectangle{
 	//	Lo: &pb.Point{Latitude: 400000000, Longitude: -750000000},
 	//	Hi: &pb.Point{Latitude: 420000000, Longitude: -730000000},
 	//})
 
-	//runRecordRoute(client)
-	//runRouteChat(client)
+	//runRecordRoute(c)
+	//runRouteChat(c)
 
-	echoClient := pb.NewEchoClient(conn)
-	conversations(echoClient)
+	switch flag.Arg(0) {
+	case "describe":
+		runDescribe(conn, flag.Arg(1))
+	case "invoke":
+		runInvoke(conn, flag.Arg(1), flag.Arg(2))
+	case "room":
+		runRoom(pb.NewEchoClient(conn), flag.Arg(1))
+	case "geofence":
+		runGeofence(client.New(conn))
+	case "replay":
+		if !runReplay(conn, flag.Arg(1)) {
+			conn.Close()
+			os.Exit(1)
+		}
+	default:
+		echoClient := pb.NewEchoClient(conn)
+		conversations(echoClient)
+	}
 }
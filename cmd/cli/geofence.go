@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/client"
+)
+
+var geofenceRadius = flag.Float64("geofence_radius", 0, "Radius in meters used by cli geofence, 0 uses the server default")
+
+// runGeofence 把标准输入中每行一个的 lat,lng 位置（E7 表示）发送给 MonitorGeofences，并打印收到的事件
+func runGeofence(c *client.Client) {
+	session, err := c.MonitorGeofences(context.Background())
+	if err != nil {
+		log.Fatalf("MonitorGeofences(_) = _, %v", err)
+	}
+	defer session.Close()
+
+	// 一个位置可能触发任意多个事件，发送和接收在不同的 goroutine 中进行
+	go func() {
+		options := &pb.GeofenceOptions{RadiusMeters: *geofenceRadius}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var lat, lng int32
+			if _, err := fmt.Sscanf(line, "%d,%d", &lat, &lng); err != nil {
+				log.Printf("invalid position %q, want lat,lng", line)
+				continue
+			}
+			pos := &pb.GeofencePosition{Location: &pb.Point{Latitude: lat, Longitude: lng}, Options: options}
+			options = nil
+			if err := session.Send(pos); err != nil {
+				// 错误由 Err 返回
+				return
+			}
+		}
+		session.CloseSend()
+	}()
+
+	for ev := range session.Events() {
+		log.Printf("%s %q %dm dwell %v", ev.Type, ev.Feature.GetName(), ev.DistanceMeters, ev.Dwell.AsDuration())
+	}
+	if err := session.Err(); err != nil {
+		log.Fatalf("failed to receive a geofence event: %v", err)
+	}
+}
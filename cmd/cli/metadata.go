@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataOptions 返回给每个调用加上 pairs 中的 metadata 的 DialOption，pairs 的格式是 key=value
+func metadataOptions(pairs []string) []grpc.DialOption {
+	var kv []string
+	for _, p := range pairs {
+		k, v, _ := strings.Cut(p, "=")
+		kv = append(kv, k, v)
+	}
+	withMetadata := func(ctx context.Context) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, kv...)
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withMetadata(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withMetadata(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
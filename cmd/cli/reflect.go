@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// reflectClient 通过服务端的 reflection 服务获取 proto 描述信息，
+// 这样不需要编译好的 stub 就可以调用任意方法
+type reflectClient struct {
+	stream rpb.ServerReflection_ServerReflectionInfoClient
+	files  *protoregistry.Files
+	// protos 缓存了服务端返回的，还没有注册到 files 中的文件描述
+	protos map[string]*descriptorpb.FileDescriptorProto
+}
+
+func newReflectClient(ctx context.Context, conn *grpc.ClientConn) (*reflectClient, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &reflectClient{
+		stream: stream,
+		files:  new(protoregistry.Files),
+		protos: make(map[string]*descriptorpb.FileDescriptorProto),
+	}, nil
+}
+
+func (c *reflectClient) close() {
+	_ = c.stream.CloseSend()
+}
+
+func (c *reflectClient) request(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := c.stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := c.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("reflection error %d: %s", e.ErrorCode, e.ErrorMessage)
+	}
+	return resp, nil
+}
+
+func (c *reflectClient) listServices() ([]string, error) {
+	resp, err := c.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *reflectClient) cacheFiles(resp *rpb.ServerReflectionResponse) error {
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := protov2.Unmarshal(b, fdp); err != nil {
+			return err
+		}
+		c.protos[fdp.GetName()] = fdp
+	}
+	return nil
+}
+
+// register 将文件及其依赖注册到 files 中，缺失的依赖会再向服务端请求
+func (c *reflectClient) register(name string) error {
+	if _, err := c.files.FindFileByPath(name); err == nil {
+		return nil
+	}
+	fdp, ok := c.protos[name]
+	if !ok {
+		resp, err := c.request(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+		})
+		if err != nil {
+			return err
+		}
+		if err := c.cacheFiles(resp); err != nil {
+			return err
+		}
+		if fdp, ok = c.protos[name]; !ok {
+			return fmt.Errorf("server did not return file %q", name)
+		}
+	}
+	for _, dep := range fdp.GetDependency() {
+		if err := c.register(dep); err != nil {
+			return err
+		}
+	}
+	fd, err := protodesc.NewFile(fdp, c.files)
+	if err != nil {
+		return err
+	}
+	return c.files.RegisterFile(fd)
+}
+
+// resolve 查找 symbol 对应的描述信息，symbol 可以是服务、方法或者消息的全名
+func (c *reflectClient) resolve(symbol string) (protoreflect.Descriptor, error) {
+	name := protoreflect.FullName(symbol)
+	if d, err := c.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	resp, err := c.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cacheFiles(resp); err != nil {
+		return nil, err
+	}
+	for fileName := range c.protos {
+		if err := c.register(fileName); err != nil {
+			return nil, err
+		}
+	}
+	return c.files.FindDescriptorByName(name)
+}
+
+// resolveMethod 解析 pkg.Service/Method 或者 pkg.Service.Method 形式的方法名
+func (c *reflectClient) resolveMethod(method string) (protoreflect.MethodDescriptor, error) {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		method = method[:i] + "." + method[i+1:]
+	}
+	d, err := c.resolve(method)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a method", method)
+	}
+	return md, nil
+}
+
+func describeService(sd protoreflect.ServiceDescriptor) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "service %s {\n", sd.FullName())
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		fmt.Fprintf(&b, "  %s\n", describeMethod(methods.Get(i)))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func describeMethod(md protoreflect.MethodDescriptor) string {
+	in, out := string(md.Input().FullName()), string(md.Output().FullName())
+	if md.IsStreamingClient() {
+		in = "stream " + in
+	}
+	if md.IsStreamingServer() {
+		out = "stream " + out
+	}
+	return fmt.Sprintf("rpc %s(%s) returns (%s);", md.Name(), in, out)
+}
+
+func describeMessage(md protoreflect.MessageDescriptor) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "message %s {\n", md.FullName())
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		typ := f.Kind().String()
+		switch f.Kind() {
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			typ = string(f.Message().FullName())
+		case protoreflect.EnumKind:
+			typ = string(f.Enum().FullName())
+		}
+		if f.Cardinality() == protoreflect.Repeated && !f.IsMap() {
+			typ = "repeated " + typ
+		}
+		fmt.Fprintf(&b, "  %s %s = %d;\n", typ, f.Name(), f.Number())
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// runDescribe 打印服务端的服务列表，或者 symbol 对应的服务、方法、消息的定义
+func runDescribe(conn *grpc.ClientConn, symbol string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	if err := describe(ctx, conn, symbol, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func describe(ctx context.Context, conn *grpc.ClientConn, symbol string, w io.Writer) error {
+	c, err := newReflectClient(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create reflection client: %v", err)
+	}
+	defer c.close()
+
+	if symbol == "" {
+		services, err := c.listServices()
+		if err != nil {
+			return fmt.Errorf("failed to list services: %v", err)
+		}
+		for _, s := range services {
+			fmt.Fprintln(w, s)
+		}
+		return nil
+	}
+
+	d, err := c.resolve(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %v", symbol, err)
+	}
+	switch d := d.(type) {
+	case protoreflect.ServiceDescriptor:
+		fmt.Fprintln(w, describeService(d))
+	case protoreflect.MethodDescriptor:
+		fmt.Fprintln(w, describeMethod(d))
+	case protoreflect.MessageDescriptor:
+		fmt.Fprintln(w, describeMessage(d))
+	default:
+		fmt.Fprintf(w, "%s: %v\n", d.FullName(), d)
+	}
+	return nil
+}
+
+// decodeRequests 将输入解析成请求消息，输入可以包含多个连续的 JSON 对象
+func decodeRequests(input io.Reader, md protoreflect.MessageDescriptor) ([]protoreflect.ProtoMessage, error) {
+	var msgs []protoreflect.ProtoMessage
+	dec := json.NewDecoder(input)
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		msg := dynamicpb.NewMessage(md)
+		if err := protojson.Unmarshal(raw, msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// runInvoke 使用 reflection 获取的描述信息调用 method，请求使用 JSON 格式，
+// 每个响应以一行 JSON 输出。data 为空或者为 "-" 时从标准输入中读取请求
+func runInvoke(conn *grpc.ClientConn, method, data string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	var input io.Reader = strings.NewReader(data)
+	if data == "" || data == "-" {
+		input = os.Stdin
+	}
+	if err := invoke(ctx, conn, method, input, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func invoke(ctx context.Context, conn *grpc.ClientConn, method string, input io.Reader, w io.Writer) error {
+	c, err := newReflectClient(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create reflection client: %v", err)
+	}
+	defer c.close()
+	md, err := c.resolveMethod(method)
+	if err != nil {
+		return fmt.Errorf("failed to resolve method %s: %v", method, err)
+	}
+
+	reqs, err := decodeRequests(input, md.Input())
+	if err != nil {
+		return fmt.Errorf("failed to parse request: %v", err)
+	}
+	if !md.IsStreamingClient() {
+		if len(reqs) > 1 {
+			return fmt.Errorf("%s takes exactly one request, got %d", md.FullName(), len(reqs))
+		}
+		if len(reqs) == 0 {
+			reqs = append(reqs, dynamicpb.NewMessage(md.Input()))
+		}
+	}
+
+	// 提前返回时取消流，发送的 goroutine 也会随之退出
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fullMethod := fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(md.Name()),
+		ClientStreams: md.IsStreamingClient(),
+		ServerStreams: md.IsStreamingServer(),
+	}, fullMethod)
+	if err != nil {
+		return fmt.Errorf("%s = _, %v", fullMethod, err)
+	}
+
+	// 发送和接收分别在两个 goroutine 中进行，这样双向流也不需要和服务端保持一问一答
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, req := range reqs {
+			if err := stream.SendMsg(proto.MessageV1(req)); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	for {
+		resp := dynamicpb.NewMessage(md.Output())
+		err := stream.RecvMsg(proto.MessageV1(resp))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", fullMethod, err)
+		}
+		out, err := protojson.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("failed to format response: %v", err)
+		}
+		fmt.Fprintln(w, string(out))
+		if !md.IsStreamingServer() {
+			break
+		}
+	}
+	if err := <-sendErr; err != nil && err != io.EOF {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// startReflection 启动一个注册了 reflection 的 RouteGuide 服务
+func startReflection(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	rg := service.NewRouteGuide()
+	rg.SetFeatures(servicetest.Features())
+	pb.RegisterRouteGuideServer(s, rg)
+	reflection.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDescribe(t *testing.T) {
+	conn := startReflection(t)
+	tests := []struct {
+		symbol string
+		want   []string
+	}{
+		{"", []string{"grpc.reflection.v1alpha.ServerReflection", "routeguide.RouteGuide"}},
+		{"routeguide.RouteGuide", []string{
+			"service routeguide.RouteGuide {",
+			"  rpc GetFeature(routeguide.Point) returns (routeguide.Feature);",
+			"  rpc ListFeatures(routeguide.Rectangle) returns (stream routeguide.Feature);",
+			"  rpc RecordRoute(stream routeguide.Point) returns (routeguide.RouteSummary);",
+		}},
+		{"routeguide.RouteGuide.RouteChat", []string{
+			"rpc RouteChat(stream routeguide.RouteNode) returns (stream routeguide.RouteNode);",
+		}},
+		{"routeguide.Feature", []string{
+			"message routeguide.Feature {",
+			"  string name = 1;",
+			"  routeguide.Point location = 2;",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := describe(testContext(t), conn, tt.symbol, &out); err != nil {
+				t.Fatalf("describe(%q) = %v", tt.symbol, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want+"\n") {
+					t.Errorf("describe(%q) = %q, want it to contain %q", tt.symbol, out.String(), want)
+				}
+			}
+		})
+	}
+
+	if err := describe(testContext(t), conn, "routeguide.Missing", new(bytes.Buffer)); err == nil {
+		t.Error("describe(routeguide.Missing) = nil, want error")
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	conn := startReflection(t)
+	features := servicetest.Features()
+
+	t.Run("unary", func(t *testing.T) {
+		var out bytes.Buffer
+		in := `{"latitude": 407838351, "longitude": -746143763}`
+		if err := invoke(testContext(t), conn, "routeguide.RouteGuide/GetFeature", strings.NewReader(in), &out); err != nil {
+			t.Fatalf("invoke(GetFeature) = %v", err)
+		}
+		got := decodeFeatures(t, out.String())
+		if len(got) != 1 || !proto.Equal(got[0], features[0]) {
+			t.Errorf("invoke(GetFeature) = %v, want %v", got, features[0])
+		}
+	})
+
+	t.Run("server streaming", func(t *testing.T) {
+		var out bytes.Buffer
+		in := `{"lo": {"latitude": 400000000, "longitude": -750000000}, "hi": {"latitude": 420000000, "longitude": -730000000}}`
+		if err := invoke(testContext(t), conn, "/routeguide.RouteGuide/ListFeatures", strings.NewReader(in), &out); err != nil {
+			t.Fatalf("invoke(ListFeatures) = %v", err)
+		}
+		got := decodeFeatures(t, out.String())
+		if len(got) == 0 {
+			t.Fatalf("invoke(ListFeatures) returned no features")
+		}
+		for _, f := range got {
+			if f.Location.Latitude < 400000000 || f.Location.Latitude > 420000000 {
+				t.Errorf("invoke(ListFeatures) returned %v outside the rectangle", f)
+			}
+		}
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		in := `{"latitude": 1} {"latitude": 2}`
+		if err := invoke(testContext(t), conn, "routeguide.RouteGuide.GetFeature", strings.NewReader(in), new(bytes.Buffer)); err == nil {
+			t.Error("invoke(GetFeature) with two requests = nil, want error")
+		}
+	})
+}
+
+// decodeFeatures 解析 invoke 输出的每一行 JSON
+func decodeFeatures(t *testing.T, out string) []*pb.Feature {
+	t.Helper()
+	var features []*pb.Feature
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := new(pb.Feature)
+		if err := protojson.Unmarshal([]byte(line), f); err != nil {
+			t.Fatalf("failed to parse %q: %v", line, err)
+		}
+		features = append(features, f)
+	}
+	return features
+}
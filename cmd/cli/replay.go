@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gRPCDemo/binlog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// maxResponseDiffs 是每个调用最多输出的不同响应的数量
+const maxResponseDiffs = 5
+
+var replayTiming = flag.Bool("replay_timing", false, "Keep the recorded gaps between requests when running cli replay")
+
+// runReplay 把二进制日志中记录的每个调用在 conn 上重新执行一遍，并比较响应和状态，
+// 所有调用的结果都和记录相同时返回 true
+func runReplay(conn *grpc.ClientConn, path string) bool {
+	if path == "" {
+		log.Fatalln("usage: cli replay <binary log file>")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("failed to open binary log: %v", err)
+	}
+	defer f.Close()
+	calls, err := binlog.ReadCalls(f)
+	if err != nil {
+		log.Fatalf("failed to read binary log: %v", err)
+	}
+
+	var opts []binlog.ReplayOption
+	if *replayTiming {
+		opts = append(opts, binlog.WithTiming())
+	}
+	var differ int
+	for _, call := range calls {
+		got, err := binlog.Replay(context.Background(), conn, call, opts...)
+		if err != nil {
+			log.Fatalf("failed to replay call %d %s: %v", call.ID, call.Method, err)
+		}
+		diffs := diffCall(call, got)
+		if len(diffs) == 0 {
+			log.Printf("call %d %s: ok", call.ID, call.Method)
+			continue
+		}
+		differ++
+		log.Printf("call %d %s: differs", call.ID, call.Method)
+		for _, d := range diffs {
+			log.Printf("    %s", d)
+		}
+	}
+	log.Printf("replayed %d calls, %d differ", len(calls), differ)
+	return differ == 0
+}
+
+// diffCall 返回 want 和 got 之间的差异，响应只是顺序不同时单独报告。
+// 响应使用方法的响应类型解码，比较之前忽略每次调用都会变化的字段和心跳，见 volatileFields
+func diffCall(want, got *binlog.Call) []string {
+	var diffs []string
+	// 客户端发送完请求之后马上退出时，服务端可能先读到结束也可能先被取消，两种结果都是正常的
+	raced := want.HalfClosed && want.Status.Code() == codes.Canceled && got.Status.Code() == codes.OK
+	if want.Status != nil && !raced {
+		if want.Status.Code() != got.Status.Code() || want.Status.Message() != got.Status.Message() {
+			diffs = append(diffs, fmt.Sprintf("status: want %s, got %s", formatStatus(want), formatStatus(got)))
+		}
+	}
+
+	wantMsgs, gotMsgs := responses(want.Method, want.Responses), responses(want.Method, got.Responses)
+	if equalMessages(wantMsgs, gotMsgs) {
+		return diffs
+	}
+	if len(wantMsgs) != len(gotMsgs) {
+		diffs = append(diffs, fmt.Sprintf("responses: want %d messages, got %d", len(wantMsgs), len(gotMsgs)))
+	} else if equalMessages(sortedMessages(wantMsgs), sortedMessages(gotMsgs)) {
+		return append(diffs, "responses: same messages in a different order")
+	}
+	shown := 0
+	for i := 0; i < len(wantMsgs) || i < len(gotMsgs); i++ {
+		var w, g protov2.Message
+		if i < len(wantMsgs) {
+			w = wantMsgs[i]
+		}
+		if i < len(gotMsgs) {
+			g = gotMsgs[i]
+		}
+		if w != nil && g != nil && protov2.Equal(w, g) {
+			continue
+		}
+		if shown++; shown > maxResponseDiffs {
+			diffs = append(diffs, "...")
+			break
+		}
+		diffs = append(diffs, fmt.Sprintf("response %d: want %s, got %s", i, formatMessage(w), formatMessage(g)))
+	}
+	return diffs
+}
+
+// volatileFields 是每次调用都会变化的字段，比较响应之前被清除
+var volatileFields = map[protoreflect.FullName]bool{
+	"routeguide.RouteSummary.elapsed_time": true,
+	"routeguide.RoomEvent.time":            true,
+}
+
+// heartbeatField 不为空的响应是 async 模式的心跳，数量取决于调用的耗时，比较时被跳过
+const heartbeatField protoreflect.FullName = "routeguide.StreamResponse.heartbeat"
+
+// responses 使用 method 的响应类型解码 msgs，清除 volatileFields 并跳过心跳。
+// 找不到响应类型或者解码失败的消息保留原始数据，按字节比较
+func responses(method string, msgs []binlog.Message) []protov2.Message {
+	desc := outputType(method)
+	var out []protov2.Message
+	for _, msg := range msgs {
+		if desc == nil {
+			out = append(out, rawMessage(msg.Data))
+			continue
+		}
+		m := dynamicpb.NewMessage(desc)
+		if err := protov2.Unmarshal(msg.Data, m); err != nil {
+			out = append(out, rawMessage(msg.Data))
+			continue
+		}
+		if fd := desc.Fields().ByName(heartbeatField.Name()); fd != nil && fd.FullName() == heartbeatField && m.Has(fd) {
+			continue
+		}
+		clearVolatile(m)
+		out = append(out, m)
+	}
+	return out
+}
+
+// rawMessage 把 data 保存为未知字段，proto.Equal 按字节比较未知字段
+func rawMessage(data []byte) protov2.Message {
+	m := &emptypb.Empty{}
+	m.ProtoReflect().SetUnknown(data)
+	return m
+}
+
+func clearVolatile(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case volatileFields[fd.FullName()]:
+			m.Clear(fd)
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				clearVolatile(v.List().Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				clearVolatile(v.Message())
+				return true
+			})
+		case !fd.IsMap() && fd.Message() != nil:
+			clearVolatile(v.Message())
+		}
+		return true
+	})
+}
+
+func formatStatus(c *binlog.Call) string {
+	if c.Status == nil {
+		return "<unfinished>"
+	}
+	if c.Status.Message() == "" {
+		return c.Status.Code().String()
+	}
+	return fmt.Sprintf("%s %q", c.Status.Code(), c.Status.Message())
+}
+
+// formatMessage 把 responses 解码的消息格式化成 JSON，没有解码的消息输出 base64
+func formatMessage(m protov2.Message) string {
+	if m == nil {
+		return "<none>"
+	}
+	if raw, ok := m.(*emptypb.Empty); ok {
+		return base64.StdEncoding.EncodeToString(raw.ProtoReflect().GetUnknown())
+	}
+	if b, err := (protojson.MarshalOptions{}).Marshal(m); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(m)
+}
+
+// outputType 在编译进 cli 的 proto 描述中查找 /pkg.Service/Method 的响应类型
+func outputType(method string) protoreflect.MessageDescriptor {
+	parts := strings.Split(strings.TrimPrefix(method, "/"), "/")
+	if len(parts) != 2 {
+		return nil
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(parts[0]))
+	if err != nil {
+		return nil
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	md := sd.Methods().ByName(protoreflect.Name(parts[1]))
+	if md == nil {
+		return nil
+	}
+	return md.Output()
+}
+
+// sortedMessages 按照序列化的结果排序，用来判断两组消息是否只是顺序不同
+func sortedMessages(msgs []protov2.Message) []protov2.Message {
+	keys := make(map[protov2.Message][]byte, len(msgs))
+	for _, m := range msgs {
+		keys[m], _ = protov2.MarshalOptions{Deterministic: true}.Marshal(m)
+	}
+	sorted := append([]protov2.Message(nil), msgs...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(keys[sorted[i]], keys[sorted[j]]) < 0 })
+	return sorted
+}
+
+func equalMessages(a, b []protov2.Message) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !protov2.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
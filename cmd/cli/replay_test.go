@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"gRPCDemo/binlog"
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestReplayRecordRouteHasNoDiffs(t *testing.T) {
+	var buf bytes.Buffer
+	w := binlog.NewWriter(&buf)
+	env := servicetest.Start(t, servicetest.WithDialOptions(
+		grpc.WithChainStreamInterceptor(binlog.StreamClientInterceptor(w)),
+	))
+	stream, err := env.RouteGuide.RecordRoute(testContext(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range servicetest.Features()[:3] {
+		if err := stream.Send(f.Location); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+
+	calls, err := binlog.ReadCalls(&buf)
+	if err != nil || len(calls) != 1 {
+		t.Fatalf("ReadCalls() = %d calls, %v, want 1 call", len(calls), err)
+	}
+	// 在另一个服务上重放，elapsed_time 不同也不算差异
+	other := servicetest.Start(t)
+	got, err := binlog.Replay(testContext(t), other.Conn, calls[0])
+	if err != nil {
+		t.Fatalf("Replay() = %v", err)
+	}
+	if diffs := diffCall(calls[0], got); len(diffs) != 0 {
+		t.Errorf("diffCall() = %q, want no diffs", diffs)
+	}
+}
+
+func TestDiffCallIgnoresVolatileFields(t *testing.T) {
+	call := func(method string, responses ...proto.Message) *binlog.Call {
+		c := &binlog.Call{Method: method, Status: status.New(codes.OK, "")}
+		for _, r := range responses {
+			data, err := proto.Marshal(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Responses = append(c.Responses, binlog.Message{Data: data})
+		}
+		return c
+	}
+	const recordRoute, conversations = "/routeguide.RouteGuide/RecordRoute", "/routeguide.Echo/Conversations"
+	event := func(seq int64, t time.Time) *pb.StreamResponse {
+		return &pb.StreamResponse{Answer: "a: hi", Event: &pb.RoomEvent{Sequence: seq, Participant: "a", Time: timestamppb.New(t)}}
+	}
+	heartbeat := &pb.StreamResponse{Heartbeat: timestamppb.Now()}
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		want, got *binlog.Call
+		diffs     int
+	}{
+		{"elapsed time", call(recordRoute, &pb.RouteSummary{PointCount: 3, ElapsedTime: 30}), call(recordRoute, &pb.RouteSummary{PointCount: 3, ElapsedTime: 41}), 0},
+		{"point count", call(recordRoute, &pb.RouteSummary{PointCount: 3}), call(recordRoute, &pb.RouteSummary{PointCount: 4}), 1},
+		{"room event time", call(conversations, event(1, now)), call(conversations, event(1, now.Add(time.Second))), 0},
+		{"room event sequence", call(conversations, event(1, now)), call(conversations, event(2, now)), 1},
+		{"heartbeats", call(conversations, heartbeat, event(1, now), heartbeat), call(conversations, event(1, now)), 0},
+		{"unknown method", call("/x.Y/Z", &pb.Point{Latitude: 1}), call("/x.Y/Z", &pb.Point{Latitude: 2}), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diffs := diffCall(tt.want, tt.got); len(diffs) != tt.diffs {
+				t.Errorf("diffCall() = %q, want %d diffs", diffs, tt.diffs)
+			}
+		})
+	}
+}
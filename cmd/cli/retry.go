@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// attemptsKey 用来在 ctx 中保存一次调用的尝试次数
+type attemptsKey struct{}
+
+type attempts struct {
+	method string
+	n      int32
+	// lastErr 是上一次失败的尝试返回的错误
+	lastErr atomic.Value
+}
+
+// retryLogger 在 grpc 重试或者 hedging 时打印日志
+//
+// 重试发生在拦截器之下，拦截器只能看到一次调用，所以由拦截器在 ctx 中放入计数器，
+// 再由 stats.Handler 在每次尝试开始和结束时更新计数并打印日志
+type retryLogger struct{}
+
+func (retryLogger) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (retryLogger) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	a, ok := ctx.Value(attemptsKey{}).(*attempts)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.Begin:
+		if n := atomic.AddInt32(&a.n, 1); n > 1 {
+			log.Printf("%s: attempt %d (transparent: %v), last error: %v", a.method, n, s.IsTransparentRetryAttempt, a.lastErr.Load())
+		}
+	case *stats.End:
+		if s.Error != nil {
+			a.lastErr.Store(s.Error.Error())
+		}
+	}
+}
+
+func (retryLogger) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (retryLogger) HandleConn(context.Context, stats.ConnStats) {}
+
+func withAttempts(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, &attempts{method: method})
+}
+
+func countAttemptsUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withAttempts(ctx, method), method, req, reply, cc, opts...)
+}
+
+func countAttemptsStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withAttempts(ctx, method), desc, cc, method, opts...)
+}
+
+// retryLoggingOptions 返回打印重试日志所需的 DialOption
+func retryLoggingOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithStatsHandler(retryLogger{}),
+		grpc.WithChainUnaryInterceptor(countAttemptsUnary),
+		grpc.WithChainStreamInterceptor(countAttemptsStream),
+	}
+}
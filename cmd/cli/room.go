@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// runRoom 加入 Echo 服务的房间 id，把标准输入的每一行发送到房间中，并打印房间中的事件。
+// 参与者的名字可以用 -metadata x-echo-participant=name 设置
+func runRoom(client pb.EchoClient, id string) {
+	if id == "" {
+		log.Fatalln("usage: cli room <room id>")
+	}
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), service.RoomKey, id))
+	defer cancel()
+	stream, err := client.Conversations(ctx)
+	if err != nil {
+		log.Fatalf("Conversations(_) = _, %v", err)
+	}
+
+	// 房间中的事件和自己的输入没有对应关系，发送和接收在不同的 goroutine 中进行
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if scanner.Text() == "" {
+				continue
+			}
+			if err := stream.Send(&pb.StreamRequest{Question: scanner.Text()}); err != nil {
+				// 错误由 Recv 返回
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatalf("failed to receive a room event: %v", err)
+		}
+		ev := resp.GetEvent()
+		prefix := ""
+		if ev.GetHistory() {
+			prefix = "(history) "
+		}
+		log.Printf("#%d %s%s", ev.GetSequence(), prefix, resp.Answer)
+	}
+}
@@ -0,0 +1,48 @@
+// Package main runs the HTTP/JSON gateway as a standalone process in front of
+// a RouteGuide server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"gRPCDemo/gateway"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	tls                = flag.Bool("tls", false, "Connection use TLS")
+	caFile             = flag.String("ca_file", "", "the file containing the ca root cert file")
+	serverAddr         = flag.String("server_addr", "localhost:10000", "Server Address")
+	serverHostOverride = flag.String("server_host_override", "dev.bwangel.abc", "The server name used to verify the hostname returned by the TLS handshake")
+	httpAddr           = flag.String("http_addr", ":8080", "The address the gateway listens on")
+)
+
+func main() {
+	flag.Parse()
+	var opts []grpc.DialOption
+	if *tls {
+		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverHostOverride)
+		if err != nil {
+			log.Fatalf("Failed to create TLC credentials %v", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(*serverAddr, opts...)
+	if err != nil {
+		log.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	log.Printf("Gateway listening on %v, forwarding to %v\n", *httpAddr, *serverAddr)
+	if err := http.ListenAndServe(*httpAddr, gateway.New(pb.NewRouteGuideClient(conn))); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gRPCDemo/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withMaxDeadline 在客户端没有设置 deadline 或者 deadline 晚于 max 之后时，把 deadline 缩短为 max
+func withMaxDeadline(ctx context.Context, max time.Duration) (context.Context, context.CancelFunc) {
+	if max <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= max {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, max)
+}
+
+// contextError 在 ctx 已经结束时把处理函数返回的错误转换成对应的状态码，
+// 否则处理函数直接返回的 ctx.Err() 会被当作 codes.Unknown
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if cause := context.Cause(ctx); cause != nil {
+		if _, ok := status.FromError(cause); ok {
+			return cause
+		}
+	}
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+// deadlineUnaryInterceptor 限制一元调用的最长时间，见 config.Limits.Deadline
+func deadlineUnaryInterceptor(limits config.Limits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := withMaxDeadline(ctx, limits.Deadline(info.FullMethod))
+		defer cancel()
+		resp, err := handler(ctx, req)
+		return resp, contextError(ctx, err)
+	}
+}
+
+// deadlineStreamInterceptor 限制流式调用的最长时间，
+// 客户端流在 limits.StreamIdleTimeout 内没有收发消息时也会被结束
+func deadlineStreamInterceptor(limits config.Limits) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := withMaxDeadline(ss.Context(), limits.Deadline(info.FullMethod))
+		defer cancel()
+		ctx, cancelCause := context.WithCancelCause(ctx)
+		defer cancelCause(nil)
+
+		gs := &guardedStream{ServerStream: ss, ctx: ctx}
+		if info.IsClientStream && limits.StreamIdleTimeout > 0 {
+			idle := limits.StreamIdleTimeout
+			gs.idle = time.AfterFunc(idle, func() {
+				cancelCause(status.Errorf(codes.DeadlineExceeded, "stream idle for %v", idle))
+			})
+			gs.timeout = idle
+			defer gs.idle.Stop()
+		}
+		return contextError(ctx, handler(srv, gs))
+	}
+}
+
+// guardedStream 让阻塞在 RecvMsg 中的处理函数在 ctx 结束时返回，并在每次收发消息时重置空闲计时器
+type guardedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mu      sync.Mutex
+	idle    *time.Timer
+	timeout time.Duration
+
+	// 第一次调用 RecvMsg 时启动 receive，之后所有的 RecvMsg 都交给它执行
+	once    sync.Once
+	recvs   chan interface{}
+	results chan error
+}
+
+func (s *guardedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *guardedStream) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.idle != nil && s.ctx.Err() == nil {
+		s.idle.Reset(s.timeout)
+	}
+}
+
+func (s *guardedStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.touch()
+	return nil
+}
+
+// receive 依次执行 RecvMsg 交给它的接收，ctx 结束之后退出。
+// 这时它可能还阻塞在 ServerStream.RecvMsg 中，处理函数返回之后 gRPC 结束这个流，RecvMsg 也就返回了
+func (s *guardedStream) receive() {
+	for {
+		select {
+		case m := <-s.recvs:
+			// results 有一个缓冲，RecvMsg 已经因为 ctx 结束返回时这里也不会阻塞
+			s.results <- s.ServerStream.RecvMsg(m)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// RecvMsg 由每个流唯一的 receive goroutine 接收消息，ctx 结束时立即返回
+func (s *guardedStream) RecvMsg(m interface{}) error {
+	// 上一次 RecvMsg 可能还没有返回，不能并发调用 ServerStream.RecvMsg
+	if err := s.ctx.Err(); err != nil {
+		return contextError(s.ctx, err)
+	}
+	s.once.Do(func() {
+		s.recvs = make(chan interface{})
+		s.results = make(chan error, 1)
+		go s.receive()
+	})
+	select {
+	case s.recvs <- m:
+	case <-s.ctx.Done():
+		return contextError(s.ctx, s.ctx.Err())
+	}
+	select {
+	case err := <-s.results:
+		if err == nil {
+			s.touch()
+		}
+		return err
+	case <-s.ctx.Done():
+		return contextError(s.ctx, s.ctx.Err())
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gRPCDemo/config"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeadlineUnaryInterceptorClampsDeadline(t *testing.T) {
+	limits := config.Limits{
+		MaxDeadline:     time.Second,
+		MethodDeadlines: map[string]time.Duration{"/test.Deadline/Short": 10 * time.Millisecond},
+	}
+	interceptor := deadlineUnaryInterceptor(limits)
+	remaining := func(ctx context.Context, method string) time.Duration {
+		var got time.Duration
+		interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatalf("%s: handler has no deadline", method)
+			}
+			got = time.Until(deadline)
+			return nil, nil
+		})
+		return got
+	}
+
+	// 没有 deadline 或者 deadline 太晚时缩短到 MaxDeadline
+	if got := remaining(context.Background(), "/test.Deadline/Any"); got > time.Second || got < 900*time.Millisecond {
+		t.Errorf("deadline without a client deadline = %v, want about 1s", got)
+	}
+	late, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if got := remaining(late, "/test.Deadline/Any"); got > time.Second {
+		t.Errorf("deadline with a late client deadline = %v, want about 1s", got)
+	}
+	// 更早的 deadline 保持不变
+	early, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if got := remaining(early, "/test.Deadline/Any"); got > 100*time.Millisecond {
+		t.Errorf("deadline with an early client deadline = %v, want at most 100ms", got)
+	}
+	// MethodDeadlines 覆盖 MaxDeadline
+	if got := remaining(context.Background(), "/test.Deadline/Short"); got > 10*time.Millisecond {
+		t.Errorf("deadline of a method override = %v, want at most 10ms", got)
+	}
+
+	// 处理函数返回的 ctx.Err() 被转换成 DeadlineExceeded
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Deadline/Short"}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("handler returning ctx.Err() = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestDeadlineStreamInterceptorIdleTimeout(t *testing.T) {
+	limits := config.Limits{StreamIdleTimeout: 100 * time.Millisecond}
+	_, echo := startEcho(t, grpc.StreamInterceptor(deadlineStreamInterceptor(limits)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 一直有消息的流不会被结束
+	stream, err := echo.Conversations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := stream.Send(&pb.StreamRequest{Question: "q"}); err != nil {
+			t.Fatalf("Send() = %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Recv() after %d messages = %v", i, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv() after CloseSend = %v, want io.EOF", err)
+	}
+
+	// 空闲的流被结束，处理函数阻塞在 RecvMsg 中也会返回
+	stream, err = echo.Conversations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = stream.Recv()
+	if status.Code(err) != codes.DeadlineExceeded || !strings.Contains(status.Convert(err).Message(), "idle") {
+		t.Errorf("Recv() on an idle stream = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("idle stream ended after %v, want about 100ms", elapsed)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"gRPCDemo/config"
+	"gRPCDemo/fault"
+)
+
+// newFaultInjector 使用配置中的规则创建 fault.Injector
+func newFaultInjector(cfg config.Fault) (*fault.Injector, error) {
+	rules := make([]fault.Rule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		code, err := fault.ParseCode(r.Code)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fault.Rule{
+			Method:         r.Method,
+			Percentage:     r.Percentage,
+			Delay:          r.Delay,
+			MessageDelay:   r.MessageDelay,
+			Code:           code,
+			Message:        r.Message,
+			AfterMessages:  r.AfterMessages,
+			DropPercentage: r.DropPercentage,
+		})
+	}
+	return fault.New(rules...)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"gRPCDemo/config"
+	"gRPCDemo/gateway"
+	"gRPCDemo/grpcweb"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialInProcess 返回一个通过内存中的 bufconn 连接到 server 的客户端连接
+//
+// HTTP 网关和 gRPC-Web 都通过这个连接访问 server，这样请求同样会经过服务端的拦截器，
+// 也不需要关心 server 是否启用了 TLS
+func dialInProcess(server *grpc.Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			log.Fatalf("failed to serve in-process listener: %v", err)
+		}
+	}()
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		log.Fatalf("failed to dial in-process listener: %v", err)
+	}
+	return conn
+}
+
+// newGatewayServer 创建进程内的 HTTP/JSON 网关
+func newGatewayServer(conn *grpc.ClientConn, port int) *http.Server {
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: gateway.New(pb.NewRouteGuideClient(conn)),
+	}
+}
+
+// webServices 是浏览器可以调用的服务，FaultInjection、reflection 和健康检查不对浏览器开放
+var webServices = []string{routeGuideService, echoService}
+
+// newWebServer 创建供浏览器使用的 gRPC-Web 和 WebSocket 桥接服务
+func newWebServer(server *grpc.Server, conn *grpc.ClientConn, cfg *config.Server) *http.Server {
+	all := server.GetServiceInfo()
+	services := make(map[string]grpc.ServiceInfo)
+	for _, name := range webServices {
+		services[name] = all[name]
+	}
+	opts := []grpcweb.Option{
+		grpcweb.WithAllowedOrigins(cfg.WebAllowedOrigins...),
+		grpcweb.WithMaxMsgSize(cfg.Limits.MaxRecvMsgSize),
+	}
+	web := grpcweb.NewHandler(conn, services, opts...)
+	mux := http.NewServeMux()
+	mux.Handle(grpcweb.PathPrefix+"/", grpcweb.NewWebSocketHandler(conn, services, opts...))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if grpcweb.IsGRPCWebRequest(r) {
+			web.ServeHTTP(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	})
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.WebPort),
+		Handler: mux,
+	}
+}
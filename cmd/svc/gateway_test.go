@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gRPCDemo/config"
+	"gRPCDemo/fault"
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestWebServerExposesOnlyPublicServices(t *testing.T) {
+	server := grpc.NewServer()
+	pb.RegisterRouteGuideServer(server, service.NewRouteGuide())
+	pb.RegisterEchoServer(server, service.NewEcho())
+	faults, err := fault.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb.RegisterFaultInjectionServer(server, fault.NewAdminServer(faults))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	conn := dialInProcess(server)
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	cfg := config.DefaultServer()
+	srv := httptest.NewServer(newWebServer(server, conn, cfg).Handler)
+	defer srv.Close()
+
+	// 空的请求消息，一元调用的 gRPC-Web 帧
+	empty := []byte{0, 0, 0, 0, 0}
+	tests := map[string]string{
+		"/routeguide.RouteGuide/GetFeature":                              "grpc-status: 0",
+		"/routeguide.FaultInjection/SetFaults":                           "unknown method",
+		"/grpc.health.v1.Health/Check":                                   "unknown method",
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": "unknown method",
+	}
+	for method, want := range tests {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+method, bytes.NewReader(empty))
+		req.Header.Set("Content-Type", "application/grpc-web+proto")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), want) {
+			t.Errorf("POST %s = %q, want it to contain %q", method, body, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"gRPCDemo/config"
+)
+
+// listenFDsStart 是 systemd 传入的第一个文件描述符，见 sd_listen_fds(3)
+const listenFDsStart = 3
+
+// listenAll 监听 addrs 中的所有地址，地址的格式见 config.Server.Listen。
+// 任何一个地址监听失败时关闭已经打开的 listener 并返回错误
+func listenAll(addrs []string) ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, addr := range addrs {
+		lis, err := listen(addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("listen %s: %v", addr, err)
+		}
+		listeners = append(listeners, lis...)
+	}
+	return listeners, nil
+}
+
+func listen(addr string) ([]net.Listener, error) {
+	switch {
+	case addr == config.Systemd:
+		return systemdListeners()
+	case strings.HasPrefix(addr, config.FDPrefix):
+		fd, err := strconv.Atoi(strings.TrimPrefix(addr, config.FDPrefix))
+		if err != nil {
+			return nil, err
+		}
+		lis, err := fileListener(fd, addr)
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{lis}, nil
+	case strings.HasPrefix(addr, config.UnixPrefix):
+		path := strings.TrimPrefix(strings.TrimPrefix(addr, config.UnixPrefix), "//")
+		if err := removeStaleSocket(path); err != nil {
+			return nil, err
+		}
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{lis}, nil
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return []net.Listener{lis}, nil
+}
+
+// removeStaleSocket 删除上次异常退出时留下的 socket 文件，path 存在但不是 socket 时返回错误
+func removeStaleSocket(path string) error {
+	if strings.HasPrefix(path, "@") {
+		// abstract socket 没有对应的文件
+		return nil
+	}
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// systemdListeners 返回 systemd socket activation 传入的 listener
+func systemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("not started by systemd socket activation, LISTEN_PID is not set for this process")
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, errors.New("no file descriptors passed in LISTEN_FDS")
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	// 子进程不应该再继承这些环境变量
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	var listeners []net.Listener
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("LISTEN_FD_%d", listenFDsStart+i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		lis, err := fileListener(listenFDsStart+i, name)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, lis)
+	}
+	return listeners, nil
+}
+
+// fileListener 把继承的文件描述符 fd 转换成 net.Listener
+func fileListener(fd int, name string) (net.Listener, error) {
+	syscall.CloseOnExec(fd)
+	f := os.NewFile(uintptr(fd), name)
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	// net.FileListener 会复制一份文件描述符，原来的可以关闭
+	defer f.Close()
+	return net.FileListener(f)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gRPCDemo/config"
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey 是客户端透传请求 ID 时使用的 metadata key
+const requestIDKey = "x-request-id"
+
+// newLogger 根据配置创建 logger，默认输出 JSON 格式的日志
+func newLogger(cfg config.Log) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %v", cfg.Level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// requestContext 从 metadata 中获取请求 ID，没有的话就生成一个，
+// 并将带有请求字段的 logger 放入 ctx 中
+func requestContext(ctx context.Context, logger *slog.Logger, method string) (context.Context, *slog.Logger) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 && strings.TrimSpace(ids[0]) != "" {
+			requestID = ids[0]
+		}
+	}
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	// 把请求 ID 回写给客户端，方便客户端对照日志
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	l := logger.With("request_id", requestID, "peer", addr, "method", method)
+	return service.WithLogger(ctx, l), l
+}
+
+func logAccess(l *slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []interface{}{"code", code.String(), "duration", time.Since(start)}
+	if err != nil {
+		l.Warn("finished call", append(attrs, "error", err)...)
+		return
+	}
+	l.Info("finished call", attrs...)
+}
+
+// accessLogUnaryInterceptor 记录每个一元调用的访问日志
+func accessLogUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, l := requestContext(ctx, logger, info.FullMethod)
+		resp, err := handler(ctx, req)
+		logAccess(l, start, err)
+		return resp, err
+	}
+}
+
+// accessLogStreamInterceptor 记录每个流式调用的访问日志，duration 是整个流的持续时间
+func accessLogStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, l := requestContext(ss.Context(), logger, info.FullMethod)
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		logAccess(l, start, err)
+		return err
+	}
+}
+
+// wrappedStream 用来替换 grpc.ServerStream 的 Context
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"runtime/debug"
+
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// panicsTotal 按方法统计处理请求时发生 panic 的次数，通过 /debug/vars 查看
+var panicsTotal = expvar.NewMap("grpc_server_panics_total")
+
+// recoverPanic 记录 panic 的堆栈和次数，把 panic 转换成 codes.Internal 错误，需要在 defer 中调用
+func recoverPanic(ctx context.Context, method string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicsTotal.Add(method, 1)
+	service.LoggerFrom(ctx).Error("recovered from panic", "panic", r, "stack", string(debug.Stack()))
+	*err = status.Error(codes.Internal, "internal error")
+}
+
+// recoveryUnaryInterceptor 防止一元调用中的 panic 导致整个进程退出
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+// recoveryStreamInterceptor 防止流式调用中的 panic 导致整个进程退出，
+// 只能恢复处理函数所在 goroutine 中的 panic
+func recoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(ss.Context(), info.FullMethod, &err)
+	return handler(srv, ss)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream 是只有 Context 的 grpc.ServerStream
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func panics(method string) int64 {
+	if v, ok := panicsTotal.Get(method).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	const method = "/test.Recovery/Unary"
+	before := panics(method)
+	_, err := recoveryUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("recoveryUnaryInterceptor() = %v, want Internal", err)
+	}
+	if got := panics(method) - before; got != 1 {
+		t.Errorf("panicsTotal[%s] increased by %d, want 1", method, got)
+	}
+
+	// 没有 panic 时结果保持不变
+	resp, err := recoveryUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, interface{}) (interface{}, error) {
+			return "ok", status.Error(codes.NotFound, "nope")
+		})
+	if resp != "ok" || status.Code(err) != codes.NotFound {
+		t.Errorf("recoveryUnaryInterceptor() = %v, %v, want the handler's result", resp, err)
+	}
+}
+
+func TestRecoveryStreamInterceptor(t *testing.T) {
+	const method = "/test.Recovery/Stream"
+	before := panics(method)
+	err := recoveryStreamInterceptor(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: method},
+		func(interface{}, grpc.ServerStream) error {
+			var m map[string]int
+			m["x"]++
+			return nil
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("recoveryStreamInterceptor() = %v, want Internal", err)
+	}
+	if got := panics(method) - before; got != 1 {
+		t.Errorf("panicsTotal[%s] increased by %d, want 1", method, got)
+	}
+}
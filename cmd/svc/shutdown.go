@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// 健康检查中使用的服务名
+const (
+	routeGuideService = "routeguide.RouteGuide"
+	echoService       = "routeguide.Echo"
+)
+
+// handleSignals 在收到 SIGTERM 或 SIGINT 之后调用 shutdown 优雅地关闭服务
+func handleSignals(server *grpc.Server, healthServer *health.Server, timeout time.Duration, onShutdown ...func(context.Context)) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	sig := <-sigCh
+	slog.Info("received signal, shutting down", "signal", sig.String(), "timeout", timeout)
+	shutdown(server, healthServer, timeout, onShutdown...)
+}
+
+// shutdown 首先将所有服务标记为 NOT_SERVING，让负载均衡器不再转发新的请求，
+// 然后同时调用 onShutdown 关闭 HTTP 网关等附属的服务，它们结束之后调用 GracefulStop 等待进行中的 RPC 结束。
+// 所有步骤共用一个 timeout，超过之后强制关闭
+func shutdown(server *grpc.Server, healthServer *health.Server, timeout time.Duration, onShutdown ...func(context.Context)) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	healthServer.Shutdown()
+	var wg sync.WaitGroup
+	for _, fn := range onShutdown {
+		wg.Add(1)
+		go func(fn func(context.Context)) {
+			defer wg.Done()
+			fn(ctx)
+		}(fn)
+	}
+	wg.Wait()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("server stopped gracefully")
+	case <-ctx.Done():
+		slog.Warn("graceful stop timed out, forcing stop")
+		server.Stop()
+	}
+}
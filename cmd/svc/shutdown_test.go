@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startEcho 启动一个只有 Echo 服务的 server，返回连接到它的客户端
+func startEcho(t *testing.T, opts ...grpc.ServerOption) (*grpc.Server, pb.EchoClient) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	pb.RegisterEchoServer(server, service.NewEcho())
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, pb.NewEchoClient(conn)
+}
+
+func TestShutdownSharesOneDeadline(t *testing.T) {
+	server, echo := startEcho(t)
+	// 一直没有结束的流让 GracefulStop 只能等到超时
+	stream, err := echo.Conversations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pb.StreamRequest{Question: "q"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	const timeout = 200 * time.Millisecond
+	deadlines := make(chan time.Time, 2)
+	hang := func(ctx context.Context) {
+		d, _ := ctx.Deadline()
+		deadlines <- d
+		<-ctx.Done()
+	}
+
+	start := time.Now()
+	shutdown(server, health.NewServer(), timeout, hang, hang)
+	if elapsed := time.Since(start); elapsed > 2*timeout {
+		t.Errorf("shutdown() took %v, want about %v", elapsed, timeout)
+	}
+	if d1, d2 := <-deadlines, <-deadlines; !d1.Equal(d2) {
+		t.Errorf("onShutdown deadlines = %v and %v, want the same", d1, d2)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("stream is still open after shutdown()")
+	}
+}
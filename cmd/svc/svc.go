@@ -1,248 +1,208 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"log"
-	"math"
-	"net"
-	"sync"
-	"time"
+	"log/slog"
+	"net/http"
 
+	"gRPCDemo/binlog"
+	"gRPCDemo/config"
+	"gRPCDemo/fault"
 	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/validate"
 
-	"context"
-
-	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 var (
-	tls        = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
-	certFile   = flag.String("cert_file", "", "The TLS Cert file")
-	keyFile    = flag.String("key_file", "", "The TLS Key file")
-	jsonDBFile = flag.String("json_db_file", "", "A json file containing a list of features")
-	port       = flag.Int("port", 10000, "The Server port")
+	configFile  = flag.String("config", "", "A YAML or TOML config file, flags and ROUTEGUIDE_* environment variables override it")
+	printConfig = flag.Bool("print_config", false, "Print the effective config and exit")
 )
 
-type echoServer struct {
-	pb.UnimplementedEchoServer
+// bindFlags 将命令行参数绑定到 cfg 上，参数的默认值就是 cfg 中当前的值
+func bindFlags(fs *flag.FlagSet, cfg *config.Server) {
+	fs.BoolVar(&cfg.TLS.Enabled, "tls", cfg.TLS.Enabled, "Connection uses TLS if true, else plain TCP")
+	fs.StringVar(&cfg.TLS.CertFile, "cert_file", cfg.TLS.CertFile, "The TLS Cert file")
+	fs.StringVar(&cfg.TLS.KeyFile, "key_file", cfg.TLS.KeyFile, "The TLS Key file")
+	fs.StringVar(&cfg.Store.JSONDBFile, "json_db_file", cfg.Store.JSONDBFile, "A json file containing a list of features")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "The Server port, used when -listen is empty")
+	config.StringsVar(fs, &cfg.Listen, "listen", "Comma separated addresses to serve gRPC on: host:port, [::]:port, unix:///path, fd://N or systemd")
+	fs.StringVar(&cfg.Log.Level, "log_level", cfg.Log.Level, "The log level, one of debug, info, warn, error")
+	fs.StringVar(&cfg.Log.Format, "log_format", cfg.Log.Format, "The log format, json or text")
+
+	fs.IntVar(&cfg.HTTPPort, "http_port", cfg.HTTPPort, "Serve the HTTP/JSON gateway on this port, 0 disables it")
+	fs.IntVar(&cfg.WebPort, "web_port", cfg.WebPort, "Serve gRPC-Web and the WebSocket bridge on this port, 0 disables it")
+	config.StringsVar(fs, &cfg.WebAllowedOrigins, "web_allowed_origins", "Comma separated origins allowed to call gRPC-Web and the WebSocket bridge cross-origin, * allows any origin without credentials")
+	fs.IntVar(&cfg.MetricsPort, "metrics_port", cfg.MetricsPort, "Serve expvar metrics on /debug/vars on this port, 0 disables it")
+	fs.BoolVar(&cfg.Reflection, "reflection", cfg.Reflection, "Register the server reflection service for tools like grpcurl")
+	fs.StringVar(&cfg.BinaryLog, "binary_log", cfg.BinaryLog, "Record every RPC to this binary log file, replay it with cli replay")
+	fs.StringVar(&cfg.Echo.ScriptFile, "echo_script_file", cfg.Echo.ScriptFile, "A YAML file containing the scripts of the Echo script responder")
+	fs.BoolVar(&cfg.Fault.Enabled, "fault_injection", cfg.Fault.Enabled, "Inject faults from the fault config and serve the FaultInjection admin service, for chaos testing only")
+	fs.DurationVar(&cfg.Limits.ShutdownTimeout, "shutdown_timeout", cfg.Limits.ShutdownTimeout, "How long to wait for in-flight RPCs before force-stopping")
 }
 
-func (s *echoServer) Conversations(stream pb.Echo_ConversationsServer) error {
-	n := 1
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
-
-		err = stream.Send(&pb.StreamResponse{
-			Answer: fmt.Sprintf("Answer: %d, Question: %s", n, req.Question),
-		})
-		if err != nil {
-			return err
-		}
-		n++
-		log.Printf("from stream client question: %s", req.Question)
+func main() {
+	cfg := config.DefaultServer()
+	bindFlags(flag.CommandLine, cfg)
+	flag.Parse()
+	if err := config.Load(flag.CommandLine, *configFile, cfg); err != nil {
+		log.Fatalln(err)
 	}
-
-}
-
-type routeGuideServer struct {
-	pb.UnimplementedRouteGuideServer
-
-	savedFeatures []*pb.Feature
-	mu            sync.Mutex
-	routeNodes    map[string][]*pb.RouteNode
-}
-
-func (s *routeGuideServer) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
-	for _, feature := range s.savedFeatures {
-		if proto.Equal(feature.Location, point) {
-			return feature, nil
-		}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalln(err)
 	}
-
-	return &pb.Feature{Location: point}, nil
-}
-func (s *routeGuideServer) ListFeatures(rect *pb.Rectangle, stream pb.RouteGuide_ListFeaturesServer) error {
-	for _, feature := range s.savedFeatures {
-		if inRange(feature.Location, rect) {
-			if err := stream.Send(feature); err != nil {
-				return err
-			}
-		}
+	if *printConfig {
+		fmt.Print(config.Dump(cfg))
+		return
 	}
 
-	return nil
-}
+	logger, err := newLogger(cfg.Log)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	slog.SetDefault(logger)
+	slog.Info("effective config", "config", config.AsMap(cfg))
 
-func (s *routeGuideServer) RecordRoute(stream pb.RouteGuide_RecordRouteServer) error {
-	var pointCount, featureCount, distance int32
-	var lastPoint *pb.Point
-
-	startTime := time.Now()
-	for {
-		point, err := stream.Recv()
-		if err == io.EOF {
-			endTime := time.Now()
-			return stream.SendAndClose(&pb.RouteSummary{
-				PointCount:   pointCount,
-				FeatureCount: featureCount,
-				Distance:     distance,
-				ElapsedTime:  int32(endTime.Sub(startTime).Milliseconds()),
-			})
-		}
+	listeners, err := listenAll(cfg.ListenAddrs())
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	// 访问日志在最外层，这样 panic 和超时转换成的状态码也会被记录
+	unary := []grpc.UnaryServerInterceptor{accessLogUnaryInterceptor(logger)}
+	stream := []grpc.StreamServerInterceptor{accessLogStreamInterceptor(logger)}
+	var binaryLog *binlog.Writer
+	if cfg.BinaryLog != "" {
+		binaryLog, err = binlog.Create(cfg.BinaryLog)
 		if err != nil {
-			return err
+			log.Fatalf("failed to create binary log: %v", err)
 		}
-		pointCount++
-		for _, feature := range s.savedFeatures {
-			if proto.Equal(feature.Location, point) {
-				featureCount++
-			}
-		}
-		if lastPoint != nil {
-			distance += calcDistance(lastPoint, point)
-		}
-		time.Sleep(time.Millisecond * 10)
-		lastPoint = point
+		defer binaryLog.Close()
+		unary = append(unary, binlog.UnaryServerInterceptor(binaryLog))
+		stream = append(stream, binlog.StreamServerInterceptor(binaryLog))
 	}
-}
-
-// RouteChat
-// echo 服务，将客户端输入的节点返回回去
-func (s *routeGuideServer) RouteChat(stream pb.RouteGuide_RouteChatServer) error {
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
+	unary = append(unary, recoveryUnaryInterceptor, validate.UnaryServerInterceptor, deadlineUnaryInterceptor(cfg.Limits))
+	stream = append(stream, recoveryStreamInterceptor, validate.StreamServerInterceptor, deadlineStreamInterceptor(cfg.Limits))
+	// 故障注入在最内层，注入的延迟受 deadline 的限制，注入的错误会出现在访问日志和二进制日志中
+	var faults *fault.Injector
+	if cfg.Fault.Enabled {
+		faults, err = newFaultInjector(cfg.Fault)
 		if err != nil {
-			return err
-		}
-		key := serialize(in.Location)
-
-		s.mu.Lock()
-		s.routeNodes[key] = append(s.routeNodes[key], in)
-		// 这里复制一遍是为了防止当服务端写一个客户端的流时，另一个客户端修改了 routeNodes
-		// 这里不需要执行深拷贝的原因是，routeNodes 只会增加，不会修改
-		rn := make([]*pb.RouteNode, len(s.routeNodes[key]))
-		copy(rn, s.routeNodes[key])
-		s.mu.Unlock()
-
-		for _, note := range rn {
-			if err := stream.Send(note); err != nil {
-				return err
-			}
+			log.Fatalf("failed to create fault injector: %v", err)
 		}
+		unary = append(unary, faults.UnaryServerInterceptor())
+		stream = append(stream, faults.StreamServerInterceptor())
+		slog.Warn("fault injection enabled", "rules", len(cfg.Fault.Rules))
 	}
-}
-
-func toRadians(num float64) float64 {
-	return num * math.Pi / float64(180)
-}
-
-// calcDistance 计算两个节点之间的距离
-func calcDistance(p1 *pb.Point, p2 *pb.Point) int32 {
-	const CordFactor float64 = 1e7
-	const R = float64(6371000) // 地球半径
-
-	lat1 := toRadians(float64(p1.Latitude) / CordFactor)
-	lat2 := toRadians(float64(p2.Latitude) / CordFactor)
-	lng1 := toRadians(float64(p1.Longitude) / CordFactor)
-	lng2 := toRadians(float64(p2.Longitude) / CordFactor)
-	dlat := lat2 - lat1
-	dlng := lng2 - lng1
-
-	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlng/2)*math.Sin(dlng/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	distance := R * c
-	return int32(distance)
-}
-
-// inRange 判断 point 是否在 rect 所划定的范围内
-func inRange(point *pb.Point, rect *pb.Rectangle) bool {
-	left := math.Min(float64(rect.Lo.Longitude), float64(rect.Hi.Longitude))
-	right := math.Max(float64(rect.Lo.Longitude), float64(rect.Hi.Longitude))
-	top := math.Max(float64(rect.Lo.Latitude), float64(rect.Hi.Latitude))
-	bottom := math.Min(float64(rect.Lo.Latitude), float64(rect.Hi.Latitude))
-
-	if float64(point.Longitude) >= left &&
-		float64(point.Longitude) <= right &&
-		float64(point.Latitude) <= top &&
-		float64(point.Latitude) >= bottom {
-		return true
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	}
-	return true
-}
-
-func serialize(point *pb.Point) string {
-	return fmt.Sprintf("%d %d", point.Latitude, point.Longitude)
-}
-
-func (s *routeGuideServer) loadFeatures(filename string) {
-	data, err := ioutil.ReadFile(filename)
-	if err != nil {
-		log.Fatalf("Read file %v failed", filename)
+	if cfg.Limits.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.Limits.MaxRecvMsgSize))
 	}
-
-	if err := json.Unmarshal(data, &s.savedFeatures); err != nil {
-		log.Fatalf("Failed to load default features: %v", err)
+	if cfg.Limits.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(cfg.Limits.MaxConcurrentStreams)))
 	}
-
-	log.Printf("Load %d features from json db\n", len(s.savedFeatures))
-}
-
-func newServer() *routeGuideServer {
-	s := &routeGuideServer{
-		routeNodes: make(map[string][]*pb.RouteNode),
+	if cfg.TLS.Enabled {
+		cerds, err := credentials.NewServerTLSFromFile(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			log.Fatalln("Failed to generate crendentials", err)
+		}
+		opts = append(opts, grpc.Creds(cerds))
 	}
-	s.loadFeatures(*jsonDBFile)
-	return s
-}
 
-func main() {
-	flag.Parse()
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
-	}
-	var opts []grpc.ServerOption
-	if *tls {
-		if *certFile == "" {
-			*certFile = "/home/xuyundong/Certs/dev.bwangel.abc.pem"
+	server := grpc.NewServer(opts...)
+	routeGuide := service.NewRouteGuide()
+	pb.RegisterRouteGuideServer(server, routeGuide)
+	echo := service.NewEcho()
+	echo.SetRoomLimits(cfg.Echo.MaxRoomParticipants, cfg.Echo.RoomHistory)
+	echo.SetConversationLimits(cfg.Echo.MaxConversations, cfg.Echo.ConversationTTL)
+	if cfg.Echo.ScriptFile != "" {
+		scripts, err := service.LoadScripts(cfg.Echo.ScriptFile)
+		if err != nil {
+			log.Fatalf("failed to load echo scripts: %v", err)
 		}
+		echo.SetScripts(scripts)
+		slog.Info("load echo scripts", "count", len(scripts), "file", cfg.Echo.ScriptFile)
+	}
+	pb.RegisterEchoServer(server, echo)
+	if faults != nil {
+		pb.RegisterFaultInjectionServer(server, fault.NewAdminServer(faults))
+	}
 
-		if *keyFile == "" {
-			*keyFile = "/home/xuyundong/Certs/dev.bwangel.abc.key"
-		}
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
 
-		cerds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	// 特征数据库加载完成之前，RouteGuide 服务处于 NOT_SERVING 状态
+	healthServer.SetServingStatus(routeGuideService, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(echoService, healthpb.HealthCheckResponse_SERVING)
+	go func() {
+		features, err := service.LoadFeatures(cfg.Store.JSONDBFile)
 		if err != nil {
-			log.Fatalln("Failed to generate crendentials", err)
+			log.Fatalf("failed to load features: %v", err)
 		}
-		opts = []grpc.ServerOption{grpc.Creds(cerds)}
+		routeGuide.SetFeatures(features)
+		slog.Info("load features from json db", "count", len(features), "file", cfg.Store.JSONDBFile)
+		healthServer.SetServingStatus(routeGuideService, healthpb.HealthCheckResponse_SERVING)
+	}()
+
+	var httpServers []*http.Server
+	var inProcess *grpc.ClientConn
+	if cfg.HTTPPort != 0 || cfg.WebPort != 0 {
+		inProcess = dialInProcess(server)
+	}
+	if cfg.HTTPPort != 0 {
+		httpServers = append(httpServers, newGatewayServer(inProcess, cfg.HTTPPort))
+	}
+	if cfg.WebPort != 0 {
+		httpServers = append(httpServers, newWebServer(server, inProcess, cfg))
+	}
+	if cfg.MetricsPort != 0 {
+		// expvar 把 /debug/vars 注册在 http.DefaultServeMux 上
+		httpServers = append(httpServers, &http.Server{Addr: fmt.Sprintf(":%d", cfg.MetricsPort)})
 	}
 
-	if *jsonDBFile == "" {
-		*jsonDBFile = "./testdata/route_guide_db.json"
+	var onShutdown []func(context.Context)
+	for _, hs := range httpServers {
+		hs := hs
+		go func() {
+			slog.Info("http listening", "addr", hs.Addr)
+			if err := hs.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("failed to serve http: %v", err)
+			}
+		}()
+		onShutdown = append(onShutdown, func(ctx context.Context) {
+			hs.Shutdown(ctx)
+		})
 	}
 
-	server := grpc.NewServer(opts...)
-	log.Printf("Listening on the %v\n", *port)
-	pb.RegisterRouteGuideServer(server, newServer())
-	pb.RegisterEchoServer(server, &echoServer{})
-	if err := server.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	// 每个 listener 都由单独的 goroutine 调用 Serve，Serve 在 GracefulStop 开始之后就会返回，
+	// 需要等待 handleSignals 执行完成再退出
+	stopped := make(chan struct{})
+	go func() {
+		handleSignals(server, healthServer, cfg.Limits.ShutdownTimeout, onShutdown...)
+		close(stopped)
+	}()
+
+	for _, lis := range listeners {
+		lis := lis
+		slog.Info("listening", "network", lis.Addr().Network(), "addr", lis.Addr().String())
+		go func() {
+			if err := server.Serve(lis); err != nil {
+				log.Fatalf("failed to serve %s: %v", lis.Addr(), err)
+			}
+		}()
 	}
+	<-stopped
 }
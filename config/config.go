@@ -0,0 +1,384 @@
+// Package config 是 svc 和 cli 的配置
+//
+// 配置按照 默认值 < 配置文件 < 环境变量 < 命令行参数 的优先级叠加，见 Load。
+// 配置文件支持 YAML (.yaml, .yml) 和 TOML (.toml)，字段名和下面结构体中的 yaml 标签一致；
+// 环境变量的名字是 ROUTEGUIDE_ 加上大写的字段路径，例如 tls.cert_file 对应 ROUTEGUIDE_TLS_CERT_FILE
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// Server 是 svc 的配置
+type Server struct {
+	// Listen 是 gRPC 服务监听的地址，所有地址由同一个 grpc.Server 提供服务，支持
+	//
+	//	host:port, [::]:port   TCP 地址
+	//	unix:///path/to/sock   Unix domain socket，unix:@name 表示 abstract socket
+	//	fd://3                 继承的文件描述符
+	//	systemd                systemd socket activation 传入的所有文件描述符
+	//
+	// 为空时监听 localhost:Port
+	Listen []string `yaml:"listen" toml:"listen"`
+	// Port 是 gRPC 服务监听的端口，设置了 Listen 时不使用
+	Port int `yaml:"port" toml:"port"`
+	// HTTPPort 是 HTTP/JSON 网关监听的端口，0 表示不启用
+	HTTPPort int `yaml:"http_port" toml:"http_port"`
+	// WebPort 是 gRPC-Web 和 WebSocket 桥接监听的端口，0 表示不启用
+	WebPort int `yaml:"web_port" toml:"web_port"`
+	// WebAllowedOrigins 是允许跨域访问 gRPC-Web 和 WebSocket 桥接的 Origin，例如 https://example.com，
+	// "*" 表示允许所有的 Origin 但是不允许带上凭据。为空时只允许同源的请求
+	WebAllowedOrigins []string `yaml:"web_allowed_origins" toml:"web_allowed_origins"`
+	// MetricsPort 是 /debug/vars 监听的端口，0 表示不启用
+	MetricsPort int  `yaml:"metrics_port" toml:"metrics_port"`
+	Reflection  bool `yaml:"reflection" toml:"reflection"`
+	// BinaryLog 是记录所有调用的二进制日志文件，为空表示不记录，见 binlog 包
+	BinaryLog string `yaml:"binary_log" toml:"binary_log"`
+
+	TLS    ServerTLS `yaml:"tls" toml:"tls"`
+	Store  Store     `yaml:"store" toml:"store"`
+	Limits Limits    `yaml:"limits" toml:"limits"`
+	Log    Log       `yaml:"log" toml:"log"`
+	Fault  Fault     `yaml:"fault" toml:"fault"`
+	Echo   Echo      `yaml:"echo" toml:"echo"`
+}
+
+// ServerTLS 是服务端的 TLS 配置
+type ServerTLS struct {
+	Enabled  bool   `yaml:"enabled" toml:"enabled"`
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+}
+
+// 支持的特征数据库
+const (
+	StoreJSON = "json"
+)
+
+// Store 是特征数据库的配置
+type Store struct {
+	Backend    string `yaml:"backend" toml:"backend"`
+	JSONDBFile string `yaml:"json_db_file" toml:"json_db_file"`
+}
+
+// Limits 是服务端的各种限制
+type Limits struct {
+	// MaxRecvMsgSize 是单个请求消息的最大字节数，0 表示使用 gRPC 的默认值
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size" toml:"max_recv_msg_size"`
+	// MaxConcurrentStreams 是每个连接上并发流的最大数量，0 表示不限制
+	MaxConcurrentStreams int `yaml:"max_concurrent_streams" toml:"max_concurrent_streams"`
+	// ShutdownTimeout 是优雅关闭时等待进行中的 RPC 的最长时间
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// MaxDeadline 是一次调用允许的最长时间，客户端没有设置 deadline 或者 deadline 更晚时使用这个值，0 表示不限制
+	MaxDeadline time.Duration `yaml:"max_deadline" toml:"max_deadline"`
+	// MethodDeadlines 按完整的方法名（例如 /routeguide.RouteGuide/GetFeature）覆盖 MaxDeadline
+	MethodDeadlines map[string]time.Duration `yaml:"method_deadlines" toml:"method_deadlines"`
+	// StreamIdleTimeout 是客户端流在没有收发消息时保持的最长时间，0 表示不限制
+	StreamIdleTimeout time.Duration `yaml:"stream_idle_timeout" toml:"stream_idle_timeout"`
+}
+
+// Deadline 返回方法 fullMethod 允许的最长时间，0 表示不限制
+func (l *Limits) Deadline(fullMethod string) time.Duration {
+	if d, ok := l.MethodDeadlines[fullMethod]; ok {
+		return d
+	}
+	return l.MaxDeadline
+}
+
+// Fault 是故障注入的配置，用来测试客户端对失败的处理，见 fault 包
+type Fault struct {
+	// Enabled 为 true 时安装故障注入拦截器并注册 FaultInjection 服务，默认不启用
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Rules 是启动时使用的规则，可以通过 FaultInjection 服务修改
+	Rules []FaultRule `yaml:"rules" toml:"rules"`
+}
+
+// FaultRule 是一条故障注入规则，字段的含义见 pb/fault.proto 中的 FaultRule
+type FaultRule struct {
+	Method         string        `yaml:"method" toml:"method"`
+	Percentage     float64       `yaml:"percentage" toml:"percentage"`
+	Delay          time.Duration `yaml:"delay" toml:"delay"`
+	MessageDelay   time.Duration `yaml:"message_delay" toml:"message_delay"`
+	Code           string        `yaml:"code" toml:"code"`
+	Message        string        `yaml:"message" toml:"message"`
+	AfterMessages  int           `yaml:"after_messages" toml:"after_messages"`
+	DropPercentage float64       `yaml:"drop_percentage" toml:"drop_percentage"`
+}
+
+// Echo 是 Echo 服务的配置
+type Echo struct {
+	// ScriptFile 是 script 应答器使用的脚本文件，为空时没有脚本，格式见 service.Script
+	ScriptFile string `yaml:"script_file" toml:"script_file"`
+	// MaxRoomParticipants 是每个房间的参与者数量上限
+	MaxRoomParticipants int `yaml:"max_room_participants" toml:"max_room_participants"`
+	// RoomHistory 是加入房间时重放的历史消息数量，0 表示不重放
+	RoomHistory int `yaml:"room_history" toml:"room_history"`
+	// MaxConversations 是保存的会话数量上限，超过时删除最久没有使用的会话
+	MaxConversations int `yaml:"max_conversations" toml:"max_conversations"`
+	// ConversationTTL 是会话的空闲时间上限，0 表示会话不会过期
+	ConversationTTL time.Duration `yaml:"conversation_ttl" toml:"conversation_ttl"`
+}
+
+// Log 是日志的配置
+type Log struct {
+	// Level 是 debug, info, warn, error 之一
+	Level string `yaml:"level" toml:"level"`
+	// Format 是 json 或者 text
+	Format string `yaml:"format" toml:"format"`
+}
+
+// DefaultServer 返回 svc 的默认配置
+func DefaultServer() *Server {
+	return &Server{
+		Port: 10000,
+		Store: Store{
+			Backend:    StoreJSON,
+			JSONDBFile: "./testdata/route_guide_db.json",
+		},
+		Limits: Limits{
+			ShutdownTimeout: 30 * time.Second,
+			MaxDeadline:     30 * time.Second,
+			// 双向流可以一直保持，由 StreamIdleTimeout 回收没有人使用的流
+			MethodDeadlines: map[string]time.Duration{
+				"/routeguide.RouteGuide/RouteChat": 0,
+				"/routeguide.Echo/Conversations":   0,
+			},
+			StreamIdleTimeout: 5 * time.Minute,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Echo: Echo{
+			MaxRoomParticipants: 16,
+			RoomHistory:         100,
+			MaxConversations:    10000,
+			ConversationTTL:     time.Hour,
+		},
+	}
+}
+
+// Validate 检查配置是否合法，返回的错误包含所有不合法的字段
+func (c *Server) Validate() error {
+	var errs []string
+	checkPort := func(name string, port int, optional bool) {
+		if (port == 0 && optional) || (port > 0 && port < 65536) {
+			return
+		}
+		errs = append(errs, fmt.Sprintf("%s: invalid port %d", name, port))
+	}
+	if len(c.Listen) == 0 {
+		checkPort("port", c.Port, false)
+	}
+	for _, addr := range c.Listen {
+		if err := checkListenAddr(addr); err != nil {
+			errs = append(errs, fmt.Sprintf("listen: %v", err))
+		}
+	}
+	checkPort("http_port", c.HTTPPort, true)
+	checkPort("web_port", c.WebPort, true)
+	checkPort("metrics_port", c.MetricsPort, true)
+	for _, origin := range c.WebAllowedOrigins {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Scheme == "" || u.Host == "" || u.Path != "") {
+			errs = append(errs, fmt.Sprintf("web_allowed_origins: invalid origin %q, want scheme://host[:port] or *", origin))
+		}
+	}
+
+	if c.TLS.Enabled && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+		errs = append(errs, "tls: cert_file and key_file are required when tls is enabled")
+	}
+	switch c.Store.Backend {
+	case StoreJSON:
+		if c.Store.JSONDBFile == "" {
+			errs = append(errs, "store.json_db_file: required for the json backend")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("store.backend: unknown backend %q", c.Store.Backend))
+	}
+	if c.Limits.MaxRecvMsgSize < 0 {
+		errs = append(errs, "limits.max_recv_msg_size: must not be negative")
+	}
+	if c.Limits.MaxConcurrentStreams < 0 {
+		errs = append(errs, "limits.max_concurrent_streams: must not be negative")
+	}
+	if c.Limits.ShutdownTimeout <= 0 {
+		errs = append(errs, "limits.shutdown_timeout: must be positive")
+	}
+	if c.Limits.MaxDeadline < 0 {
+		errs = append(errs, "limits.max_deadline: must not be negative")
+	}
+	for method, d := range c.Limits.MethodDeadlines {
+		if !strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2 {
+			errs = append(errs, fmt.Sprintf("limits.method_deadlines: %q is not a full method name like /pkg.Service/Method", method))
+		}
+		if d < 0 {
+			errs = append(errs, fmt.Sprintf("limits.method_deadlines: %s must not be negative", method))
+		}
+	}
+	if c.Limits.StreamIdleTimeout < 0 {
+		errs = append(errs, "limits.stream_idle_timeout: must not be negative")
+	}
+	errs = append(errs, c.Log.validate()...)
+	if c.Echo.MaxRoomParticipants <= 0 {
+		errs = append(errs, "echo.max_room_participants: must be positive")
+	}
+	if c.Echo.RoomHistory < 0 {
+		errs = append(errs, "echo.room_history: must not be negative")
+	}
+	if c.Echo.MaxConversations <= 0 {
+		errs = append(errs, "echo.max_conversations: must be positive")
+	}
+	if c.Echo.ConversationTTL < 0 {
+		errs = append(errs, "echo.conversation_ttl: must not be negative")
+	}
+	for i, r := range c.Fault.Rules {
+		errs = append(errs, r.validate(fmt.Sprintf("fault.rules[%d]", i))...)
+	}
+	return joinErrors(errs)
+}
+
+// ListenAddrs 返回 gRPC 服务需要监听的地址
+func (c *Server) ListenAddrs() []string {
+	if len(c.Listen) == 0 {
+		return []string{fmt.Sprintf("localhost:%d", c.Port)}
+	}
+	return c.Listen
+}
+
+// 监听地址的前缀，见 Server.Listen
+const (
+	UnixPrefix = "unix:"
+	FDPrefix   = "fd://"
+	Systemd    = "systemd"
+)
+
+func checkListenAddr(addr string) error {
+	switch {
+	case addr == Systemd:
+		return nil
+	case strings.HasPrefix(addr, UnixPrefix):
+		if path := strings.TrimPrefix(strings.TrimPrefix(addr, UnixPrefix), "//"); path == "" {
+			return fmt.Errorf("%q: empty socket path", addr)
+		}
+		return nil
+	case strings.HasPrefix(addr, FDPrefix):
+		if fd, err := strconv.Atoi(strings.TrimPrefix(addr, FDPrefix)); err != nil || fd < 3 {
+			return fmt.Errorf("%q: want fd://N with N >= 3", addr)
+		}
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("%q: %v", addr, err)
+	}
+	return nil
+}
+
+func (r *FaultRule) validate(name string) []string {
+	var errs []string
+	if r.Method == "" {
+		errs = append(errs, name+".method: required")
+	}
+	if r.Percentage <= 0 || r.Percentage > 100 {
+		errs = append(errs, fmt.Sprintf("%s.percentage: want (0, 100], got %v", name, r.Percentage))
+	}
+	if r.DropPercentage < 0 || r.DropPercentage > 100 {
+		errs = append(errs, fmt.Sprintf("%s.drop_percentage: want [0, 100], got %v", name, r.DropPercentage))
+	}
+	if r.Delay < 0 || r.MessageDelay < 0 {
+		errs = append(errs, name+": delay and message_delay must not be negative")
+	}
+	if r.AfterMessages < 0 {
+		errs = append(errs, name+".after_messages: must not be negative")
+	}
+	if _, ok := code.Code_value[r.Code]; r.Code != "" && !ok {
+		errs = append(errs, fmt.Sprintf("%s.code: unknown code %q, want a name like UNAVAILABLE", name, r.Code))
+	}
+	return errs
+}
+
+func (l *Log) validate() []string {
+	var errs []string
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		errs = append(errs, fmt.Sprintf("log.level: invalid level %q", l.Level))
+	}
+	if l.Format != "json" && l.Format != "text" {
+		errs = append(errs, fmt.Sprintf("log.format: want json or text, got %q", l.Format))
+	}
+	return errs
+}
+
+// Client 是 cli 的配置
+type Client struct {
+	ServerAddr string `yaml:"server_addr" toml:"server_addr"`
+	// Timeout 是没有设置 deadline 的调用使用的超时时间
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+	// ServiceConfigFile 是 gRPC service config 文件，为空时使用内置的重试和 hedging 配置
+	ServiceConfigFile string `yaml:"service_config_file" toml:"service_config_file"`
+	// LBPolicy 是 round_robin 或者 least_request，为空时使用 pick_first
+	LBPolicy string `yaml:"lb_policy" toml:"lb_policy"`
+	// BinaryLog 是记录所有调用的二进制日志文件，为空表示不记录
+	BinaryLog string `yaml:"binary_log" toml:"binary_log"`
+	// Metadata 是每个调用都会带上的 metadata，格式是 key=value，例如 x-echo-responder=reverse
+	Metadata []string `yaml:"metadata" toml:"metadata"`
+
+	TLS ClientTLS `yaml:"tls" toml:"tls"`
+}
+
+// ClientTLS 是客户端的 TLS 配置
+type ClientTLS struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	CAFile  string `yaml:"ca_file" toml:"ca_file"`
+	// ServerHostOverride 是校验服务端证书时使用的主机名
+	ServerHostOverride string `yaml:"server_host_override" toml:"server_host_override"`
+}
+
+// DefaultClient 返回 cli 的默认配置
+func DefaultClient() *Client {
+	return &Client{
+		ServerAddr: "localhost:10000",
+		Timeout:    10 * time.Second,
+		TLS:        ClientTLS{ServerHostOverride: "dev.bwangel.abc"},
+	}
+}
+
+// Validate 检查配置是否合法，返回的错误包含所有不合法的字段
+func (c *Client) Validate() error {
+	var errs []string
+	if c.ServerAddr == "" {
+		errs = append(errs, "server_addr: required")
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, "timeout: must be positive")
+	}
+	if c.TLS.Enabled && c.TLS.CAFile == "" {
+		errs = append(errs, "tls.ca_file: required when tls is enabled")
+	}
+	switch c.LBPolicy {
+	case "", "round_robin", "least_request":
+	default:
+		errs = append(errs, fmt.Sprintf("lb_policy: want round_robin or least_request, got %q", c.LBPolicy))
+	}
+	for _, kv := range c.Metadata {
+		if k, _, ok := strings.Cut(kv, "="); !ok || k == "" {
+			errs = append(errs, fmt.Sprintf("metadata: want key=value, got %q", kv))
+		}
+	}
+	return joinErrors(errs)
+}
+
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New("invalid config: " + strings.Join(errs, "; "))
+}
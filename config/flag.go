@@ -0,0 +1,30 @@
+package config
+
+import (
+	"flag"
+	"strings"
+)
+
+// stringsValue 是逗号分隔的字符串列表参数，每次 Set 都会替换之前的值，
+// 这样 Load 重新设置命令行参数时不会重复追加
+type stringsValue []string
+
+func (s *stringsValue) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsValue) Set(v string) error {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*s = items
+	return nil
+}
+
+// StringsVar 定义逗号分隔的字符串列表参数，结果保存在 p 中
+func StringsVar(fs *flag.FlagSet, p *[]string, name, usage string) {
+	fs.Var((*stringsValue)(p), name, usage)
+}
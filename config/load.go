@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix 是环境变量的前缀
+const EnvPrefix = "ROUTEGUIDE_"
+
+// Load 按照 默认值 < 配置文件 < 环境变量 < 命令行参数 的优先级把配置加载到 cfg 中
+//
+// cfg 的字段需要事先用 fs 绑定到命令行参数上，并且 fs 已经 Parse 过，
+// 这样 cfg 中已经是默认值或者命令行中的值。Load 会记录命令行中显式设置的参数，
+// 读取配置文件和环境变量之后再把这些参数重新设置一遍。path 为空时不读取配置文件
+func Load(fs *flag.FlagSet, path string, cfg interface{}) error {
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return err
+		}
+	}
+	if err := applyEnv(os.LookupEnv, cfg); err != nil {
+		return err
+	}
+	for name, value := range set {
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("flag -%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func loadFile(path string, cfg interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %v", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && err != io.EOF {
+			return fmt.Errorf("parse %s: %v", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("parse %s: %v", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse %s: unknown fields %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s, want .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// applyEnv 使用环境变量覆盖 cfg 中的字段，lookup 通常是 os.LookupEnv
+func applyEnv(lookup func(string) (string, bool), cfg interface{}) error {
+	return walk(reflect.ValueOf(cfg).Elem(), EnvPrefix, func(name string, v reflect.Value) error {
+		s, ok := lookup(name)
+		if !ok {
+			return nil
+		}
+		if err := setValue(v, s); err != nil {
+			return fmt.Errorf("env %s: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// walk 遍历结构体 v 中的所有字段，name 是字段对应的环境变量名
+func walk(v reflect.Value, prefix string, fn func(name string, v reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(tag)
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			if err := walk(fv, name+"_", fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items stringsValue
+		items.Set(s)
+		v.Set(reflect.ValueOf([]string(items)))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Dump 返回 cfg 的 YAML 格式，用来打印生效的配置
+func Dump(cfg interface{}) string {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Sprintf("# failed to format config: %v\n", err)
+	}
+	return string(data)
+}
+
+// AsMap 把 cfg 转换成和配置文件中字段名一致的 map，用来在结构化日志中输出生效的配置
+func AsMap(cfg interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(Dump(cfg)), &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newFlagSet(cfg *Server) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "")
+	fs.IntVar(&cfg.HTTPPort, "http_port", cfg.HTTPPort, "")
+	fs.StringVar(&cfg.Log.Level, "log_level", cfg.Log.Level, "")
+	return fs
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "svc.yaml", `
+port: 1
+http_port: 2
+web_port: 3
+log:
+  level: debug
+limits:
+  shutdown_timeout: 5s
+`)
+	t.Setenv("ROUTEGUIDE_HTTP_PORT", "20")
+	t.Setenv("ROUTEGUIDE_WEB_PORT", "30")
+
+	cfg := DefaultServer()
+	fs := newFlagSet(cfg)
+	if err := fs.Parse([]string{"-port", "100", "-http_port", "200"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(fs, path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	// 命令行参数 > 环境变量 > 配置文件 > 默认值
+	if cfg.Port != 100 || cfg.HTTPPort != 200 || cfg.WebPort != 30 {
+		t.Errorf("ports = %d, %d, %d, want 100, 200, 30", cfg.Port, cfg.HTTPPort, cfg.WebPort)
+	}
+	if cfg.Log.Level != "debug" || cfg.Log.Format != "json" {
+		t.Errorf("log = %+v, want level from file and default format", cfg.Log)
+	}
+	if cfg.Limits.ShutdownTimeout != 5*time.Second {
+		t.Errorf("shutdown_timeout = %v, want 5s", cfg.Limits.ShutdownTimeout)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := writeFile(t, "cli.toml", `
+server_addr = "routeguide:///a:1,b:2"
+timeout = "3s"
+
+[tls]
+enabled = true
+ca_file = "ca.pem"
+`)
+	cfg := DefaultClient()
+	if err := Load(flag.NewFlagSet("test", flag.ContinueOnError), path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if cfg.ServerAddr != "routeguide:///a:1,b:2" || cfg.Timeout != 3*time.Second || !cfg.TLS.Enabled || cfg.TLS.CAFile != "ca.pem" {
+		t.Errorf("Load() = %+v", cfg)
+	}
+	if cfg.TLS.ServerHostOverride != "dev.bwangel.abc" {
+		t.Errorf("server_host_override = %q, want the default", cfg.TLS.ServerHostOverride)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, env string
+	}{
+		{"unknown yaml field", "svc.yaml", "prot: 1\n", ""},
+		{"unknown toml field", "svc.toml", "prot = 1\n", ""},
+		{"bad extension", "svc.json", "{}", ""},
+		{"bad env", "svc.yaml", "", "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("ROUTEGUIDE_PORT", tt.env)
+			}
+			path := writeFile(t, tt.file, tt.content)
+			if err := Load(flag.NewFlagSet("test", flag.ContinueOnError), path, DefaultServer()); err == nil {
+				t.Error("Load() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := DefaultServer()
+	cfg.Port = 0
+	cfg.TLS.Enabled = true
+	cfg.Store.Backend = "sqlite"
+	cfg.Log.Format = "xml"
+	cfg.Echo.MaxConversations = 0
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want error")
+	}
+	for _, field := range []string{"port", "tls", "store.backend", "log.format", "echo.max_conversations"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate() = %v, want it to mention %s", err, field)
+		}
+	}
+
+	client := DefaultClient()
+	client.LBPolicy = "random"
+	if err := client.Validate(); err == nil || !strings.Contains(err.Error(), "lb_policy") {
+		t.Errorf("Client.Validate() = %v, want lb_policy error", err)
+	}
+
+	client = DefaultClient()
+	client.Metadata = []string{"x-echo-responder=reverse", "x-empty=", "novalue", "=v"}
+	err = client.Validate()
+	if err == nil || !strings.Contains(err.Error(), `"novalue"`) || !strings.Contains(err.Error(), `"=v"`) {
+		t.Errorf("Client.Validate() = %v, want errors for novalue and =v", err)
+	}
+	if strings.Contains(err.Error(), "x-echo-responder") || strings.Contains(err.Error(), "x-empty") {
+		t.Errorf("Client.Validate() = %v, want valid pairs accepted", err)
+	}
+}
+
+func TestListenFlagIsReplacedNotAppended(t *testing.T) {
+	path := writeFile(t, "svc.toml", `listen = ["0.0.0.0:1"]`)
+	cfg := DefaultServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	StringsVar(fs, &cfg.Listen, "listen", "")
+	if err := fs.Parse([]string{"-listen", "[::]:2, unix:///tmp/rg.sock"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(fs, path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if got := strings.Join(cfg.ListenAddrs(), " "); got != "[::]:2 unix:///tmp/rg.sock" {
+		t.Errorf("ListenAddrs() = %s, want the flag value", got)
+	}
+
+	t.Setenv("ROUTEGUIDE_LISTEN", "fd://3,systemd")
+	cfg = DefaultServer()
+	if err := Load(flag.NewFlagSet("test", flag.ContinueOnError), path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if got := strings.Join(cfg.ListenAddrs(), " "); got != "fd://3 systemd" {
+		t.Errorf("ListenAddrs() = %s, want the env value", got)
+	}
+}
+
+func TestValidateListen(t *testing.T) {
+	cfg := DefaultServer()
+	if got := cfg.ListenAddrs(); len(got) != 1 || got[0] != "localhost:10000" {
+		t.Errorf("default ListenAddrs() = %v, want [localhost:10000]", got)
+	}
+	cfg.Listen = []string{":1", "[::1]:2", "unix:///run/rg.sock", "unix:@rg", "fd://3", "systemd"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	for _, addr := range []string{"localhost", "unix://", "fd://2", "fd://x"} {
+		cfg.Listen = []string{addr}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() with listen %q = nil, want error", addr)
+		}
+	}
+}
+
+func TestValidateWebAllowedOrigins(t *testing.T) {
+	cfg := DefaultServer()
+	cfg.WebAllowedOrigins = []string{"https://example.com", "http://localhost:8080", "*"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	for _, origin := range []string{"example.com", "https://example.com/app", "https://"} {
+		cfg.WebAllowedOrigins = []string{origin}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "web_allowed_origins") {
+			t.Errorf("Validate() with origin %q = %v, want web_allowed_origins error", origin, err)
+		}
+	}
+}
+
+func TestMethodDeadlines(t *testing.T) {
+	path := writeFile(t, "svc.yaml", `
+limits:
+  max_deadline: 10s
+  method_deadlines:
+    /routeguide.RouteGuide/GetFeature: 1s
+`)
+	cfg := DefaultServer()
+	if err := Load(flag.NewFlagSet("test", flag.ContinueOnError), path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	tests := map[string]time.Duration{
+		"/routeguide.RouteGuide/GetFeature":   time.Second,
+		"/routeguide.RouteGuide/ListFeatures": 10 * time.Second,
+		"/routeguide.RouteGuide/RouteChat":    0,
+		"/grpc.health.v1.Health/Check":        10 * time.Second,
+		"/routeguide.Echo/Conversations":      0,
+	}
+	for method, want := range tests {
+		if got := cfg.Limits.Deadline(method); got != want {
+			t.Errorf("Deadline(%s) = %v, want %v", method, got, want)
+		}
+	}
+
+	cfg.Limits.MethodDeadlines["GetFeature"] = time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with a short method name = nil, want error")
+	}
+}
+
+func TestFaultRules(t *testing.T) {
+	path := writeFile(t, "svc.toml", `
+[fault]
+enabled = true
+
+[[fault.rules]]
+method = "/routeguide.RouteGuide/RecordRoute"
+percentage = 10
+code = "UNAVAILABLE"
+after_messages = 3
+
+[[fault.rules]]
+method = "*"
+percentage = 100
+delay = "50ms"
+`)
+	cfg := DefaultServer()
+	if err := Load(flag.NewFlagSet("test", flag.ContinueOnError), path, cfg); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if !cfg.Fault.Enabled || len(cfg.Fault.Rules) != 2 {
+		t.Fatalf("Fault = %+v, want enabled with 2 rules", cfg.Fault)
+	}
+	if r := cfg.Fault.Rules[0]; r.Code != "UNAVAILABLE" || r.AfterMessages != 3 || r.Percentage != 10 {
+		t.Errorf("Rules[0] = %+v", r)
+	}
+	if r := cfg.Fault.Rules[1]; r.Delay != 50*time.Millisecond {
+		t.Errorf("Rules[1].Delay = %v, want 50ms", r.Delay)
+	}
+
+	cfg.Fault.Rules = []FaultRule{{Method: "*", Percentage: 0, Code: "Unavailable", DropPercentage: 101}}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() with invalid fault rules = nil, want error")
+	}
+	for _, want := range []string{"percentage", "drop_percentage", "code"} {
+		if !strings.Contains(err.Error(), "fault.rules[0]."+want) {
+			t.Errorf("Validate() = %v, want an error for %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package fault
+
+import (
+	"context"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type adminServer struct {
+	pb.UnimplementedFaultInjectionServer
+	injector *Injector
+}
+
+// NewAdminServer 返回修改 injector 规则的 FaultInjection 服务
+func NewAdminServer(injector *Injector) pb.FaultInjectionServer {
+	return &adminServer{injector: injector}
+}
+
+func (s *adminServer) GetFaults(ctx context.Context, _ *pb.GetFaultsRequest) (*pb.Faults, error) {
+	return toProto(s.injector.Rules()), nil
+}
+
+func (s *adminServer) SetFaults(ctx context.Context, faults *pb.Faults) (*pb.Faults, error) {
+	rules := make([]Rule, 0, len(faults.GetRules()))
+	for _, p := range faults.GetRules() {
+		r, err := FromProto(p)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		rules = append(rules, r)
+	}
+	if err := s.injector.SetRules(rules); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return toProto(s.injector.Rules()), nil
+}
+
+func toProto(rules []Rule) *pb.Faults {
+	faults := &pb.Faults{}
+	for _, r := range rules {
+		p := &pb.FaultRule{
+			Method:         r.Method,
+			Percentage:     r.Percentage,
+			Message:        r.Message,
+			AfterMessages:  int32(r.AfterMessages),
+			DropPercentage: r.DropPercentage,
+		}
+		if r.Delay > 0 {
+			p.Delay = durationpb.New(r.Delay)
+		}
+		if r.MessageDelay > 0 {
+			p.MessageDelay = durationpb.New(r.MessageDelay)
+		}
+		if r.Code != codes.OK {
+			p.Code = codeName(r.Code)
+		}
+		faults.Rules = append(faults.Rules, p)
+	}
+	return faults
+}
+
+// codeName 返回 ParseCode 可以解析的名字，例如 UNAVAILABLE
+func codeName(c codes.Code) string {
+	return code.Code(c).String()
+}
@@ -0,0 +1,333 @@
+// Package fault 在服务端注入故障，用来测试客户端对延迟、错误和流中途失败的处理
+//
+// 规则按方法和概率生效，可以增加延迟、返回指定的状态码、丢弃流中的消息，或者在流中收发了 N 条消息之后结束调用。
+// 规则由 Injector 保存，可以通过 FaultInjection 服务在运行时修改，见 NewAdminServer。
+// FaultInjection 服务自身的调用不会被注入故障
+package fault
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminPrefix 是 FaultInjection 服务的方法名前缀
+const adminPrefix = "/routeguide.FaultInjection/"
+
+// injected 按照方法和故障的类型统计注入的次数
+var injected = expvar.NewMap("grpc_server_faults_injected_total")
+
+// Rule 是一条故障注入规则
+type Rule struct {
+	// Method 是完整的方法名，/pkg.Service/* 匹配服务的所有方法，* 匹配所有方法
+	Method string
+	// Percentage 是规则对匹配的调用生效的概率，取值 (0, 100]
+	Percentage float64
+	// Delay 是调用处理之前增加的延迟
+	Delay time.Duration
+	// MessageDelay 是流中每条消息收发之前增加的延迟
+	MessageDelay time.Duration
+	// Code 不是 codes.OK 时调用以 Code 和 Message 结束
+	Code    codes.Code
+	Message string
+	// AfterMessages 是流中收发了多少条消息之后返回 Code，0 表示调用处理之前就返回。
+	// 只计算流式的一方的消息，例如 ListFeatures 只计算响应
+	AfterMessages int
+	// DropPercentage 是流中每条消息被丢弃的概率，取值 [0, 100]。
+	// 丢弃的请求不会交给处理函数，丢弃的响应不会发送给客户端
+	DropPercentage float64
+}
+
+func (r Rule) matches(fullMethod string) bool {
+	if r.Method == "*" {
+		return true
+	}
+	if prefix := strings.TrimSuffix(r.Method, "*"); prefix != r.Method {
+		return strings.HasPrefix(fullMethod, prefix)
+	}
+	return r.Method == fullMethod
+}
+
+func (r Rule) validate() error {
+	switch {
+	case r.Method == "":
+		return fmt.Errorf("method is required")
+	case r.Percentage <= 0 || r.Percentage > 100:
+		return fmt.Errorf("percentage: want (0, 100], got %v", r.Percentage)
+	case r.DropPercentage < 0 || r.DropPercentage > 100:
+		return fmt.Errorf("drop_percentage: want [0, 100], got %v", r.DropPercentage)
+	case r.Delay < 0 || r.MessageDelay < 0:
+		return fmt.Errorf("delay and message_delay must not be negative")
+	case r.AfterMessages < 0:
+		return fmt.Errorf("after_messages must not be negative")
+	}
+	return nil
+}
+
+func (r Rule) abort() error {
+	msg := r.Message
+	if msg == "" {
+		msg = "injected fault"
+	}
+	return status.Error(r.Code, msg)
+}
+
+// ParseCode 解析 UNAVAILABLE 这样的状态码名字，空字符串表示 codes.OK
+func ParseCode(s string) (codes.Code, error) {
+	if s == "" {
+		return codes.OK, nil
+	}
+	c, ok := code.Code_value[s]
+	if !ok {
+		return codes.OK, fmt.Errorf("unknown code %q", s)
+	}
+	return codes.Code(c), nil
+}
+
+// Injector 保存故障注入规则，并提供注入故障的拦截器
+type Injector struct {
+	mu    sync.RWMutex
+	rules []Rule
+	// rand 不是并发安全的，由 randMu 保护
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// New 返回使用 rules 的 Injector，规则为空时不注入任何故障
+func New(rules ...Rule) (*Injector, error) {
+	i := &Injector{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	if err := i.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// SetRules 替换所有规则
+func (i *Injector) SetRules(rules []Rule) error {
+	for n, r := range rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rule %d: %v", n, err)
+		}
+	}
+	i.mu.Lock()
+	i.rules = append([]Rule(nil), rules...)
+	i.mu.Unlock()
+	return nil
+}
+
+// Rules 返回当前的规则
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]Rule(nil), i.rules...)
+}
+
+// roll 以 percentage% 的概率返回 true
+func (i *Injector) roll(percentage float64) bool {
+	if percentage <= 0 {
+		return false
+	}
+	i.randMu.Lock()
+	defer i.randMu.Unlock()
+	return i.rand.Float64()*100 < percentage
+}
+
+// pick 返回对这次调用生效的规则
+func (i *Injector) pick(fullMethod string) (Rule, bool) {
+	if strings.HasPrefix(fullMethod, adminPrefix) {
+		return Rule{}, false
+	}
+	i.mu.RLock()
+	rules := i.rules
+	i.mu.RUnlock()
+	for _, r := range rules {
+		if r.matches(fullMethod) && i.roll(r.Percentage) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// sleep 等待 d，ctx 先结束时返回对应的状态
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// UnaryServerInterceptor 返回在一元调用中注入延迟和错误的拦截器，一元调用没有消息可以丢弃
+func (i *Injector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r, ok := i.pick(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if r.Delay > 0 {
+			injected.Add(info.FullMethod+" delay", 1)
+			if err := sleep(ctx, r.Delay); err != nil {
+				return nil, err
+			}
+		}
+		if r.Code != codes.OK {
+			injected.Add(info.FullMethod+" abort", 1)
+			return nil, r.abort()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 返回在流式调用中注入延迟、错误和丢弃消息的拦截器
+func (i *Injector) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r, ok := i.pick(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+		if r.Delay > 0 {
+			injected.Add(info.FullMethod+" delay", 1)
+			if err := sleep(ss.Context(), r.Delay); err != nil {
+				return err
+			}
+		}
+		if r.Code != codes.OK && r.AfterMessages == 0 {
+			injected.Add(info.FullMethod+" abort", 1)
+			return r.abort()
+		}
+		fs := &faultyStream{
+			ServerStream: ss,
+			injector:     i,
+			rule:         r,
+			method:       info.FullMethod,
+			clientStream: info.IsClientStream,
+			serverStream: info.IsServerStream,
+		}
+		err := handler(srv, fs)
+		// 处理函数可能忽略了 SendMsg 返回的错误，调用还是以注入的错误结束
+		if fs.aborted() {
+			return r.abort()
+		}
+		return err
+	}
+}
+
+// faultyStream 在收发消息时注入故障。只有流式的一方的消息会被计数、延迟和丢弃，
+// 例如 ListFeatures 的请求和 RecordRoute 的响应不受影响
+type faultyStream struct {
+	grpc.ServerStream
+	injector     *Injector
+	rule         Rule
+	method       string
+	clientStream bool
+	serverStream bool
+
+	mu       sync.Mutex
+	messages int
+	abort    bool
+}
+
+func (s *faultyStream) aborted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.abort
+}
+
+// before 在收发一条消息之前调用，返回非 nil 时调用应该以这个错误结束
+func (s *faultyStream) before() error {
+	if err := sleep(s.Context(), s.rule.MessageDelay); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.abort {
+		return s.rule.abort()
+	}
+	if s.rule.Code != codes.OK && s.messages >= s.rule.AfterMessages {
+		s.abort = true
+		injected.Add(s.method+" abort", 1)
+		return s.rule.abort()
+	}
+	return nil
+}
+
+func (s *faultyStream) count() {
+	s.mu.Lock()
+	s.messages++
+	s.mu.Unlock()
+}
+
+func (s *faultyStream) drop() bool {
+	if !s.injector.roll(s.rule.DropPercentage) {
+		return false
+	}
+	injected.Add(s.method+" drop", 1)
+	return true
+}
+
+func (s *faultyStream) SendMsg(m interface{}) error {
+	if !s.serverStream {
+		return s.ServerStream.SendMsg(m)
+	}
+	if err := s.before(); err != nil {
+		return err
+	}
+	s.count()
+	if s.drop() {
+		return nil
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *faultyStream) RecvMsg(m interface{}) error {
+	if !s.clientStream {
+		return s.ServerStream.RecvMsg(m)
+	}
+	for {
+		if err := s.before(); err != nil {
+			return err
+		}
+		if err := s.ServerStream.RecvMsg(m); err != nil {
+			return err
+		}
+		s.count()
+		if !s.drop() {
+			return nil
+		}
+	}
+}
+
+// FromProto 把 FaultInjection 服务中的规则转换成 Rule
+func FromProto(p *pb.FaultRule) (Rule, error) {
+	code, err := ParseCode(p.GetCode())
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{
+		Method:         p.GetMethod(),
+		Percentage:     p.GetPercentage(),
+		Delay:          p.GetDelay().AsDuration(),
+		MessageDelay:   p.GetMessageDelay().AsDuration(),
+		Code:           code,
+		Message:        p.GetMessage(),
+		AfterMessages:  int(p.GetAfterMessages()),
+		DropPercentage: p.GetDropPercentage(),
+	}, nil
+}
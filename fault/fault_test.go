@@ -0,0 +1,285 @@
+package fault_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"gRPCDemo/fault"
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// start 启动安装了 injector 拦截器和 FaultInjection 服务的测试服务
+func start(t *testing.T, rules ...fault.Rule) (*servicetest.Env, *fault.Injector) {
+	t.Helper()
+	injector, err := fault.New(rules...)
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	env := servicetest.Start(t,
+		servicetest.WithServerOptions(
+			grpc.UnaryInterceptor(injector.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(injector.StreamServerInterceptor()),
+		),
+		servicetest.WithService(&pb.FaultInjection_ServiceDesc, fault.NewAdminServer(injector)),
+	)
+	return env, injector
+}
+
+var (
+	inDB = servicetest.Features()[0].Location
+	// rect 包含所有测试特征
+	rect = &pb.Rectangle{
+		Lo: &pb.Point{Latitude: 400000000, Longitude: -750000000},
+		Hi: &pb.Point{Latitude: 420000000, Longitude: -730000000},
+	}
+)
+
+func listFeatures(ctx context.Context, client pb.RouteGuideClient) (int, error) {
+	stream, err := client.ListFeatures(ctx, rect)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+func recordRoute(ctx context.Context, client pb.RouteGuideClient, points int) (*pb.RouteSummary, error) {
+	stream, err := client.RecordRoute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < points; i++ {
+		// 服务端结束调用之后 Send 返回 io.EOF，错误由 CloseAndRecv 返回
+		if err := stream.Send(inDB); err != nil {
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestNoRules(t *testing.T) {
+	env, _ := start(t)
+	ctx := testContext(t)
+	if _, err := env.RouteGuide.GetFeature(ctx, inDB); err != nil {
+		t.Errorf("GetFeature() = %v", err)
+	}
+	if n, err := listFeatures(ctx, env.RouteGuide); err != nil || n == 0 {
+		t.Errorf("ListFeatures() = %d features, %v", n, err)
+	}
+}
+
+func TestUnaryAbort(t *testing.T) {
+	env, _ := start(t, fault.Rule{
+		Method:     "/routeguide.RouteGuide/GetFeature",
+		Percentage: 100,
+		Code:       codes.Unavailable,
+		Message:    "chaos",
+	})
+	ctx := testContext(t)
+
+	_, err := env.RouteGuide.GetFeature(ctx, inDB)
+	if s := status.Convert(err); s.Code() != codes.Unavailable || s.Message() != "chaos" {
+		t.Errorf("GetFeature() = %v, want Unavailable chaos", err)
+	}
+	// 其他方法不受影响
+	if n, err := listFeatures(ctx, env.RouteGuide); err != nil || n == 0 {
+		t.Errorf("ListFeatures() = %d features, %v", n, err)
+	}
+}
+
+func TestServiceWildcard(t *testing.T) {
+	env, _ := start(t, fault.Rule{Method: "/routeguide.RouteGuide/*", Percentage: 100, Code: codes.Internal})
+	ctx := testContext(t)
+
+	if _, err := env.RouteGuide.GetFeature(ctx, inDB); status.Code(err) != codes.Internal {
+		t.Errorf("GetFeature() = %v, want Internal", err)
+	}
+	if _, err := listFeatures(ctx, env.RouteGuide); status.Code(err) != codes.Internal {
+		t.Errorf("ListFeatures() = %v, want Internal", err)
+	}
+	stream, err := env.Echo.Conversations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pb.StreamRequest{Question: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Errorf("Conversations().Recv() = %v, want the Echo service unaffected", err)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	env, _ := start(t, fault.Rule{Method: "*", Percentage: 100, Delay: 100 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := env.RouteGuide.GetFeature(testContext(t), inDB); err != nil {
+		t.Fatalf("GetFeature() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("GetFeature() took %v, want >= 100ms", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := env.RouteGuide.GetFeature(ctx, inDB); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("GetFeature() with a short deadline = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestMessageDelay(t *testing.T) {
+	env, _ := start(t, fault.Rule{Method: "/routeguide.RouteGuide/RecordRoute", Percentage: 100, MessageDelay: 20 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := recordRoute(testContext(t), env.RouteGuide, 3); err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+	// 3 个请求和客户端结束发送，响应不是流式的，不会被延迟
+	if elapsed := time.Since(start); elapsed < 4*20*time.Millisecond {
+		t.Errorf("RecordRoute() took %v, want >= 80ms", elapsed)
+	}
+}
+
+func TestStreamAbortAfterMessages(t *testing.T) {
+	env, _ := start(t,
+		fault.Rule{Method: "/routeguide.RouteGuide/ListFeatures", Percentage: 100, Code: codes.Unavailable, AfterMessages: 2},
+		fault.Rule{Method: "/routeguide.RouteGuide/RecordRoute", Percentage: 100, Code: codes.Aborted, AfterMessages: 3},
+	)
+	ctx := testContext(t)
+
+	n, err := listFeatures(ctx, env.RouteGuide)
+	if n != 2 || status.Code(err) != codes.Unavailable {
+		t.Errorf("ListFeatures() = %d features, %v, want 2 and Unavailable", n, err)
+	}
+
+	// 少于 3 个点的路线正常结束
+	if summary, err := recordRoute(ctx, env.RouteGuide, 2); err != nil || summary.GetPointCount() != 2 {
+		t.Errorf("RecordRoute(2 points) = %v, %v, want 2 points", summary, err)
+	}
+	if _, err := recordRoute(ctx, env.RouteGuide, 10); status.Code(err) != codes.Aborted {
+		t.Errorf("RecordRoute(10 points) = %v, want Aborted", err)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	env, _ := start(t,
+		fault.Rule{Method: "/routeguide.RouteGuide/ListFeatures", Percentage: 100, DropPercentage: 100},
+		fault.Rule{Method: "/routeguide.RouteGuide/RecordRoute", Percentage: 100, DropPercentage: 100},
+	)
+	ctx := testContext(t)
+
+	if n, err := listFeatures(ctx, env.RouteGuide); n != 0 || err != nil {
+		t.Errorf("ListFeatures() = %d features, %v, want all dropped", n, err)
+	}
+	// 丢弃的请求不会交给处理函数
+	if summary, err := recordRoute(ctx, env.RouteGuide, 3); err != nil || summary.GetPointCount() != 0 {
+		t.Errorf("RecordRoute() = %v, %v, want all points dropped", summary, err)
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	env, _ := start(t, fault.Rule{Method: "*", Percentage: 50, Code: codes.Unavailable})
+	ctx := testContext(t)
+
+	failed := 0
+	const calls = 400
+	for i := 0; i < calls; i++ {
+		if _, err := env.RouteGuide.GetFeature(ctx, inDB); err != nil {
+			failed++
+		}
+	}
+	if failed < calls/4 || failed > calls*3/4 {
+		t.Errorf("%d of %d calls failed, want about half", failed, calls)
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	// "*" 不会作用在 FaultInjection 服务上，否则规则设置之后就没有办法再修改了
+	env, injector := start(t, fault.Rule{Method: "*", Percentage: 100, Code: codes.Unavailable})
+	ctx := testContext(t)
+	admin := pb.NewFaultInjectionClient(env.Conn)
+
+	got, err := admin.GetFaults(ctx, &pb.GetFaultsRequest{})
+	if err != nil {
+		t.Fatalf("GetFaults() = %v", err)
+	}
+	if len(got.Rules) != 1 || got.Rules[0].Code != "UNAVAILABLE" {
+		t.Errorf("GetFaults() = %v, want the UNAVAILABLE rule", got)
+	}
+
+	got, err = admin.SetFaults(ctx, &pb.Faults{Rules: []*pb.FaultRule{{
+		Method:     "/routeguide.RouteGuide/GetFeature",
+		Percentage: 100,
+		Delay:      durationpb.New(time.Millisecond),
+		Code:       "RESOURCE_EXHAUSTED",
+	}}})
+	if err != nil {
+		t.Fatalf("SetFaults() = %v", err)
+	}
+	if len(got.Rules) != 1 || got.Rules[0].Delay.AsDuration() != time.Millisecond {
+		t.Errorf("SetFaults() = %v", got)
+	}
+	if rules := injector.Rules(); len(rules) != 1 || rules[0].Code != codes.ResourceExhausted {
+		t.Errorf("Rules() = %v", rules)
+	}
+	if _, err := env.RouteGuide.GetFeature(ctx, inDB); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetFeature() = %v, want ResourceExhausted", err)
+	}
+
+	for _, bad := range []*pb.FaultRule{
+		{Method: "*", Percentage: 100, Code: "NOPE"},
+		{Method: "*", Percentage: 0},
+		{Percentage: 100},
+	} {
+		if _, err := admin.SetFaults(ctx, &pb.Faults{Rules: []*pb.FaultRule{bad}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SetFaults(%v) = %v, want InvalidArgument", bad, err)
+		}
+	}
+
+	// 清空规则之后恢复正常
+	if _, err := admin.SetFaults(ctx, &pb.Faults{}); err != nil {
+		t.Fatalf("SetFaults(empty) = %v", err)
+	}
+	if _, err := env.RouteGuide.GetFeature(ctx, inDB); err != nil {
+		t.Errorf("GetFeature() after clearing = %v", err)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	for s, want := range map[string]codes.Code{
+		"":                  codes.OK,
+		"UNAVAILABLE":       codes.Unavailable,
+		"DEADLINE_EXCEEDED": codes.DeadlineExceeded,
+	} {
+		if got, err := fault.ParseCode(s); err != nil || got != want {
+			t.Errorf("ParseCode(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := fault.ParseCode("Unavailable"); err == nil {
+		t.Errorf("ParseCode(Unavailable) = nil error")
+	}
+}
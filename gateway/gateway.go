@@ -0,0 +1,610 @@
+// Package gateway 把 RouteGuide 服务以 HTTP/JSON 的形式暴露出去，供不能直接使用 gRPC 的 Web 前端调用
+//
+// 路由和 pb/routeguide.proto 中的 google.api.http 注解保持一致:
+//
+//	GET  /v1/features?lat=&lng=          GetFeature
+//	GET  /v1/features:list?rect=...      ListFeatures，返回 NDJSON
+//	GET  /v1/features:page?circle=...    ListFeaturesPage，见 parseListRequest
+//	GET  /v1/features:search?q=...       SearchFeatures，见 parseSearchRequest
+//	GET  /v1/features:reverse?lat=&lng=  ReverseGeocode，可以用 radius 设置范围
+//	POST /v1/routes:record               RecordRoute，请求体为 Point 数组或者 NDJSON，大小和点数见 WithMaxBodySize 和 WithMaxRoutePoints
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// forwardedHeaders 中的 HTTP 头会作为 metadata 透传给 gRPC 服务
+var forwardedHeaders = []string{"X-Request-Id", "Authorization"}
+
+// POST /v1/routes:record 的默认限制
+const (
+	DefaultMaxBodySize    = 4 << 20
+	DefaultMaxRoutePoints = 10000
+)
+
+type gateway struct {
+	client         pb.RouteGuideClient
+	maxBodySize    int64
+	maxRoutePoints int
+}
+
+// Option 配置 New 返回的 http.Handler
+type Option func(*gateway)
+
+// WithMaxBodySize 设置请求体的最大字节数，超过时返回 413，n 为 0 时使用 DefaultMaxBodySize
+func WithMaxBodySize(n int64) Option {
+	return func(g *gateway) {
+		if n > 0 {
+			g.maxBodySize = n
+		}
+	}
+}
+
+// WithMaxRoutePoints 设置 RecordRoute 一次最多接收的点数，超过时返回 InvalidArgument，n 为 0 时使用 DefaultMaxRoutePoints
+func WithMaxRoutePoints(n int) Option {
+	return func(g *gateway) {
+		if n > 0 {
+			g.maxRoutePoints = n
+		}
+	}
+}
+
+// New 返回一个把 HTTP 请求转换成 client 上的 gRPC 调用的 http.Handler
+func New(client pb.RouteGuideClient, opts ...Option) http.Handler {
+	g := &gateway{client: client, maxBodySize: DefaultMaxBodySize, maxRoutePoints: DefaultMaxRoutePoints}
+	for _, opt := range opts {
+		opt(g)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/features", g.getFeature)
+	mux.HandleFunc("/v1/features:list", g.listFeatures)
+	mux.HandleFunc("/v1/features:page", g.listFeaturesPage)
+	mux.HandleFunc("/v1/features:search", g.searchFeatures)
+	mux.HandleFunc("/v1/features:reverse", g.reverseGeocode)
+	mux.HandleFunc("/v1/routes:record", g.recordRoute)
+	return mux
+}
+
+func (g *gateway) getFeature(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	point, err := parsePoint(r.URL.Query().Get("lat"), r.URL.Query().Get("lng"))
+	if err != nil {
+		writeStatus(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	feature, err := g.client.GetFeature(outgoingContext(r), point)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	writeMessage(w, http.StatusOK, feature)
+}
+
+func (g *gateway) listFeatures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	rect, err := parseRect(r.URL.Query().Get("rect"))
+	if err != nil {
+		writeStatus(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	stream, err := g.client.ListFeatures(outgoingContext(r), rect)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	wroteHeader := false
+	for {
+		feature, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 已经开始输出之后无法再修改状态码，只能在流的最后一行输出错误
+			if !wroteHeader {
+				writeStatus(w, status.Convert(err))
+				return
+			}
+			line, _ := json.Marshal(errorBody(status.Convert(err)))
+			w.Write(append(line, '\n'))
+			return
+		}
+		if !wroteHeader {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			wroteHeader = true
+		}
+		line, err := protojson.Marshal(feature)
+		if err != nil {
+			return
+		}
+		w.Write(append(line, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if !wroteHeader {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (g *gateway) listFeaturesPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	req, err := parseListRequest(r.URL.Query())
+	if err != nil {
+		writeStatus(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	resp, err := g.client.ListFeaturesPage(outgoingContext(r), req)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	writeMessage(w, http.StatusOK, resp)
+}
+
+func (g *gateway) searchFeatures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	req, err := parseSearchRequest(r.URL.Query())
+	if err != nil {
+		writeStatus(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	resp, err := g.client.SearchFeatures(outgoingContext(r), req)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	writeMessage(w, http.StatusOK, resp)
+}
+
+func (g *gateway) reverseGeocode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	q := r.URL.Query()
+	point, err := parsePoint(q.Get("lat"), q.Get("lng"))
+	if err != nil {
+		writeStatus(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	req := &pb.ReverseGeocodeRequest{Location: point}
+	if v := q.Get("radius"); v != "" {
+		if req.RadiusMeters, err = strconv.ParseFloat(v, 64); err != nil {
+			writeStatus(w, status.New(codes.InvalidArgument, fmt.Sprintf("invalid radius %q", v)))
+			return
+		}
+	}
+
+	resp, err := g.client.ReverseGeocode(outgoingContext(r), req)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	writeMessage(w, http.StatusOK, resp)
+}
+
+func (g *gateway) recordRoute(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+
+	// 出错时取消 RecordRoute，服务端不会把已经发送的点当成一条完整的路线
+	ctx, cancel := context.WithCancel(outgoingContext(r))
+	defer cancel()
+	stream, err := g.client.RecordRoute(ctx)
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	n := 0
+	err = decodePoints(http.MaxBytesReader(w, r.Body, g.maxBodySize), func(point *pb.Point) error {
+		if n++; n > g.maxRoutePoints {
+			return status.Errorf(codes.InvalidArgument, "too many points, at most %d", g.maxRoutePoints)
+		}
+		return stream.Send(point)
+	})
+	if err != nil && err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge,
+				status.Newf(codes.ResourceExhausted, "request body larger than max (%d bytes)", g.maxBodySize))
+			return
+		}
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.InvalidArgument, err.Error())
+		}
+		writeStatus(w, status.Convert(err))
+		return
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	writeMessage(w, http.StatusOK, summary)
+}
+
+// decodePoints 解析 JSON 数组或者 NDJSON 格式的请求体，每解析出一个 Point 就调用一次 fn
+func decodePoints(body io.Reader, fn func(*pb.Point) error) error {
+	br := bufio.NewReader(body)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(br)
+	isArray := first == '['
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		point := new(pb.Point)
+		if err := protojson.Unmarshal(raw, point); err != nil {
+			return err
+		}
+		if err := fn(point); err != nil {
+			return err
+		}
+	}
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsRune([]byte(" \t\r\n"), rune(b)) {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func parsePoint(lat, lng string) (*pb.Point, error) {
+	latitude, err := strconv.ParseInt(lat, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lat %q", lat)
+	}
+	longitude, err := strconv.ParseInt(lng, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lng %q", lng)
+	}
+	return &pb.Point{Latitude: int32(latitude), Longitude: int32(longitude)}, nil
+}
+
+// parseRect 解析 lo_lat,lo_lng,hi_lat,hi_lng 格式的矩形
+func parseRect(rect string) (*pb.Rectangle, error) {
+	parts := strings.Split(rect, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid rect %q, want lo_lat,lo_lng,hi_lat,hi_lng", rect)
+	}
+	lo, err := parsePoint(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	hi, err := parsePoint(parts[2], parts[3])
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Rectangle{Lo: lo, Hi: hi}, nil
+}
+
+// parseListRequest 解析 ListFeaturesPage 的查询参数，所有参数都是可选的，范围最多只能有一个:
+//
+//	rect=lo_lat,lo_lng,hi_lat,hi_lng        page_size=10  page_token=...
+//	circle=lat,lng,radius_meters            order_by=name|distance
+//	corridor=lat,lng,lat,lng,...&buffer=100 origin=lat,lng
+//	polygon={"type":"Polygon","coordinates":[[[lng,lat],...]]}
+//
+// polygon 是 GeoJSON 格式，坐标是度数，其他参数中的坐标都使用 E7 表示
+func parseListRequest(q url.Values) (*pb.ListFeaturesRequest, error) {
+	req := &pb.ListFeaturesRequest{PageToken: q.Get("page_token")}
+	areas := 0
+	for _, name := range []string{"rect", "circle", "corridor", "polygon"} {
+		if q.Get(name) != "" {
+			areas++
+		}
+	}
+	if areas > 1 {
+		return nil, fmt.Errorf("only one of rect, circle, corridor and polygon can be set")
+	}
+	switch {
+	case q.Get("rect") != "":
+		rect, err := parseRect(q.Get("rect"))
+		if err != nil {
+			return nil, err
+		}
+		req.Area = &pb.ListFeaturesRequest_Rect{Rect: rect}
+	case q.Get("circle") != "":
+		circle, err := parseCircle(q.Get("circle"))
+		if err != nil {
+			return nil, err
+		}
+		req.Area = &pb.ListFeaturesRequest_Circle{Circle: circle}
+	case q.Get("corridor") != "":
+		corridor, err := parseCorridor(q.Get("corridor"), q.Get("buffer"))
+		if err != nil {
+			return nil, err
+		}
+		req.Area = &pb.ListFeaturesRequest_Corridor{Corridor: corridor}
+	case q.Get("polygon") != "":
+		polygon, err := parseGeoJSONPolygon(q.Get("polygon"))
+		if err != nil {
+			return nil, err
+		}
+		req.Area = &pb.ListFeaturesRequest_Polygon{Polygon: polygon}
+	}
+	if v := q.Get("page_size"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page_size %q", v)
+		}
+		req.PageSize = int32(n)
+	}
+	if v := q.Get("order_by"); v != "" {
+		order, ok := pb.ListFeaturesRequest_Order_value[strings.ToUpper(v)]
+		if !ok {
+			return nil, fmt.Errorf("invalid order_by %q, want name or distance", v)
+		}
+		req.OrderBy = pb.ListFeaturesRequest_Order(order)
+	}
+	if v := q.Get("origin"); v != "" {
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid origin %q, want lat,lng", v)
+		}
+		origin, err := parsePoint(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		req.Origin = origin
+	}
+	return req, nil
+}
+
+// parseSearchRequest 解析 SearchFeatures 的查询参数，只有 q 是必须的:
+//
+//	q=new+jer  bias=lat,lng  bias_scale=10000  limit=10
+func parseSearchRequest(q url.Values) (*pb.SearchFeaturesRequest, error) {
+	req := &pb.SearchFeaturesRequest{Query: q.Get("q")}
+	if v := q.Get("bias"); v != "" {
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid bias %q, want lat,lng", v)
+		}
+		bias, err := parsePoint(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		req.Bias = bias
+	}
+	if v := q.Get("bias_scale"); v != "" {
+		scale, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bias_scale %q", v)
+		}
+		req.BiasScaleMeters = scale
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q", v)
+		}
+		req.Limit = int32(n)
+	}
+	return req, nil
+}
+
+// parseCircle 解析 lat,lng,radius_meters 格式的圆
+func parseCircle(circle string) (*pb.Circle, error) {
+	parts := strings.Split(circle, ",")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid circle %q, want lat,lng,radius_meters", circle)
+	}
+	center, err := parsePoint(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	radius, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid radius %q", parts[2])
+	}
+	return &pb.Circle{Center: center, RadiusMeters: radius}, nil
+}
+
+// parseCorridor 解析 lat,lng,lat,lng,... 格式的折线和以米为单位的 buffer。
+// 查询参数中不能使用分号，所以点之间也用逗号分隔
+func parseCorridor(path, buffer string) (*pb.Corridor, error) {
+	parts := strings.Split(path, ",")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("invalid corridor %q, want lat,lng,lat,lng,...", path)
+	}
+	corridor := &pb.Corridor{}
+	for i := 0; i < len(parts); i += 2 {
+		point, err := parsePoint(parts[i], parts[i+1])
+		if err != nil {
+			return nil, err
+		}
+		corridor.Path = append(corridor.Path, point)
+	}
+	b, err := strconv.ParseFloat(buffer, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid buffer %q", buffer)
+	}
+	corridor.BufferMeters = b
+	return corridor, nil
+}
+
+// parseGeoJSONPolygon 解析 GeoJSON 的 Polygon，第一个环是外环，其他的环是洞
+func parseGeoJSONPolygon(geojson string) (*pb.Polygon, error) {
+	var g struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(geojson), &g); err != nil {
+		return nil, fmt.Errorf("invalid polygon: %v", err)
+	}
+	if g.Type != "Polygon" || len(g.Coordinates) == 0 {
+		return nil, fmt.Errorf("invalid polygon: want a GeoJSON Polygon with at least one ring")
+	}
+	polygon := &pb.Polygon{}
+	for i, coords := range g.Coordinates {
+		ring := &pb.LinearRing{}
+		for _, c := range coords {
+			if math.Abs(c[0]) > 180 || math.Abs(c[1]) > 90 {
+				return nil, fmt.Errorf("invalid polygon: coordinate %v out of range", c)
+			}
+			// GeoJSON 的坐标顺序是经度、纬度
+			ring.Points = append(ring.Points, &pb.Point{
+				Latitude:  int32(math.Round(c[1] * 1e7)),
+				Longitude: int32(math.Round(c[0] * 1e7)),
+			})
+		}
+		if i == 0 {
+			polygon.Exterior = ring
+		} else {
+			polygon.Holes = append(polygon.Holes, ring)
+		}
+	}
+	return polygon, nil
+}
+
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, h := range forwardedHeaders {
+		if v := r.Header.Get(h); v != "" {
+			md.Set(h, v)
+		}
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+func writeMessage(w http.ResponseWriter, code int, m proto.Message) {
+	body, err := protojson.Marshal(m)
+	if err != nil {
+		writeStatus(w, status.New(codes.Internal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+func errorBody(s *status.Status) map[string]interface{} {
+	e := map[string]interface{}{
+		"code":    s.Code().String(),
+		"message": s.Message(),
+	}
+	// 请求校验失败时带上每个字段的错误，见 validate 包
+	var violations []map[string]string
+	for _, d := range s.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				violations = append(violations, map[string]string{"field": v.GetField(), "description": v.GetDescription()})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		e["field_violations"] = violations
+	}
+	return map[string]interface{}{"error": e}
+}
+
+func writeStatus(w http.ResponseWriter, s *status.Status) {
+	writeError(w, HTTPStatusFromCode(s.Code()), s)
+}
+
+func writeError(w http.ResponseWriter, httpCode int, s *status.Status) {
+	body, _ := json.Marshal(errorBody(s))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	w.Write(body)
+}
+
+// HTTPStatusFromCode 将 gRPC 状态码转换成对应的 HTTP 状态码
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
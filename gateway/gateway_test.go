@@ -0,0 +1,329 @@
+package gateway_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gRPCDemo/gateway"
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/routeguidetest"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startGateway 在 servicetest 启动的服务前面启动网关
+func startGateway(t *testing.T, opts ...gateway.Option) *httptest.Server {
+	t.Helper()
+	env := servicetest.Start(t, servicetest.WithServerOptions(
+		grpc.ChainUnaryInterceptor(validate.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(validate.StreamServerInterceptor),
+	))
+	srv := httptest.NewServer(gateway.New(env.RouteGuide, opts...))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type errorBody struct {
+	Error struct {
+		Code            string              `json:"code"`
+		Message         string              `json:"message"`
+		FieldViolations []map[string]string `json:"field_violations"`
+	} `json:"error"`
+}
+
+// get 发送 GET 请求，返回状态码和响应体
+func get(t *testing.T, srv *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func decode(t *testing.T, body string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(body), v); err != nil {
+		t.Fatalf("json.Unmarshal(%q) = %v", body, err)
+	}
+}
+
+func TestGetFeature(t *testing.T) {
+	srv := startGateway(t)
+
+	code, body := get(t, srv, "/v1/features?lat=407838351&lng=-746143763")
+	var feature struct{ Name string }
+	decode(t, body, &feature)
+	if code != http.StatusOK || feature.Name != "Patriots Path, Mendham, NJ 07945, USA" {
+		t.Errorf("GET /v1/features = %d %s, want Mendham", code, body)
+	}
+
+	resp, err := http.Post(srv.URL+"/v1/features?lat=1&lng=1", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /v1/features = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestBadQueryParameters(t *testing.T) {
+	srv := startGateway(t)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/features?lat=x&lng=1", `invalid lat "x"`},
+		{"/v1/features?lat=1", `invalid lng ""`},
+		{"/v1/features:list?rect=1,2,3", "want lo_lat,lo_lng,hi_lat,hi_lng"},
+		{"/v1/features:page?rect=0,0,1,1&circle=0,0,10", "only one of"},
+		{"/v1/features:page?circle=0,0", "want lat,lng,radius_meters"},
+		{"/v1/features:page?corridor=0,0,1&buffer=10", "want lat,lng,lat,lng"},
+		{"/v1/features:page?corridor=0,0,1,1", `invalid buffer ""`},
+		{"/v1/features:page?polygon=" + url.QueryEscape(`{"type":"Point"}`), "want a GeoJSON Polygon"},
+		{"/v1/features:page?polygon=" + url.QueryEscape(`{"type":"Polygon","coordinates":[[[200,0],[0,1],[1,1]]]}`), "out of range"},
+		{"/v1/features:page?page_size=big", "invalid page_size"},
+		{"/v1/features:page?order_by=size", "invalid order_by"},
+		{"/v1/features:page?origin=1", "invalid origin"},
+		{"/v1/features:search?q=a&bias=1", "invalid bias"},
+		{"/v1/features:search?q=a&bias_scale=far", "invalid bias_scale"},
+		{"/v1/features:search?q=a&limit=ten", "invalid limit"},
+		{"/v1/features:reverse?lat=1&lng=1&radius=near", "invalid radius"},
+	}
+	for _, tt := range tests {
+		code, body := get(t, srv, tt.path)
+		var e errorBody
+		decode(t, body, &e)
+		if code != http.StatusBadRequest || e.Error.Code != "InvalidArgument" || !strings.Contains(e.Error.Message, tt.want) {
+			t.Errorf("GET %s = %d %s, want 400 containing %q", tt.path, code, body, tt.want)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	srv := startGateway(t)
+
+	code, body := get(t, srv, "/v1/features?lat=1000000000&lng=0")
+	var e errorBody
+	decode(t, body, &e)
+	if code != http.StatusBadRequest || len(e.Error.FieldViolations) != 1 || e.Error.FieldViolations[0]["field"] != "latitude" {
+		t.Errorf("GET /v1/features with an invalid latitude = %d %s, want a latitude field violation", code, body)
+	}
+
+	if code, body := get(t, srv, "/v1/features:reverse?lat=0&lng=0&radius=10"); code != http.StatusNotFound {
+		t.Errorf("GET /v1/features:reverse far away = %d %s, want 404", code, body)
+	}
+}
+
+func TestListFeatures(t *testing.T) {
+	srv := startGateway(t)
+
+	resp, err := http.Get(srv.URL + "/v1/features:list?rect=400000000,-750000000,420000000,-730000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "application/x-ndjson" {
+		t.Fatalf("GET /v1/features:list = %d %s, want 200 application/x-ndjson", resp.StatusCode, ct)
+	}
+	lines := 0
+	for sc := bufio.NewScanner(resp.Body); sc.Scan(); lines++ {
+		var feature struct{ Location struct{ Latitude int32 } }
+		decode(t, sc.Text(), &feature)
+		if feature.Location.Latitude == 0 {
+			t.Errorf("line %d = %s, want a Feature", lines+1, sc.Text())
+		}
+	}
+	if lines != len(servicetest.Features()) {
+		t.Errorf("GET /v1/features:list returned %d lines, want %d", lines, len(servicetest.Features()))
+	}
+
+	// 范围内没有特征时返回空的响应体
+	code, body := get(t, srv, "/v1/features:list?rect=0,0,1,1")
+	if code != http.StatusOK || body != "" {
+		t.Errorf("GET /v1/features:list with an empty rect = %d %q, want 200 and no lines", code, body)
+	}
+}
+
+func TestListFeaturesErrorMidStream(t *testing.T) {
+	fake := routeguidetest.NewServer()
+	fake.SetFeatures(
+		&pb.Feature{Name: "a", Location: &pb.Point{Latitude: 1, Longitude: 1}},
+		&pb.Feature{Name: "b", Location: &pb.Point{Latitude: 2, Longitude: 2}},
+	)
+	srv := httptest.NewServer(gateway.New(routeguidetest.NewClient(t, fake)))
+	defer srv.Close()
+
+	// 第一个 Feature 之前失败时返回对应的状态码
+	fake.FailAt(routeguidetest.ListFeatures, 1, status.Error(codes.Unavailable, "down"))
+	if code, body := get(t, srv, "/v1/features:list?rect=0,0,10,10"); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /v1/features:list = %d %s, want 503", code, body)
+	}
+
+	// 已经输出了 Feature 之后错误在最后一行
+	fake.FailAt(routeguidetest.ListFeatures, 2, status.Error(codes.Unavailable, "down"))
+	code, body := get(t, srv, "/v1/features:list?rect=0,0,10,10")
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if code != http.StatusOK || len(lines) != 2 {
+		t.Fatalf("GET /v1/features:list = %d %q, want 200 and 2 lines", code, body)
+	}
+	var e errorBody
+	decode(t, lines[1], &e)
+	if e.Error.Code != "Unavailable" || e.Error.Message != "down" {
+		t.Errorf("last line = %s, want the Unavailable error", lines[1])
+	}
+}
+
+func TestListFeaturesPageAndSearch(t *testing.T) {
+	srv := startGateway(t)
+
+	code, body := get(t, srv, "/v1/features:page?page_size=2&order_by=distance&origin=407838351,-746143763")
+	var page struct {
+		Features      []struct{ Name string }
+		NextPageToken string
+		TotalSize     int
+	}
+	decode(t, body, &page)
+	if code != http.StatusOK || len(page.Features) != 2 || page.NextPageToken == "" || page.TotalSize != len(servicetest.Features()) {
+		t.Fatalf("GET /v1/features:page = %d %s", code, body)
+	}
+	if !strings.Contains(page.Features[0].Name, "Mendham") {
+		t.Errorf("first feature by distance = %q, want Mendham", page.Features[0].Name)
+	}
+
+	code, body = get(t, srv, "/v1/features:page?circle=407838351,-746143763,1000")
+	page.Features = nil
+	decode(t, body, &page)
+	if code != http.StatusOK || len(page.Features) != 1 {
+		t.Errorf("GET /v1/features:page?circle= = %d %s, want Mendham only", code, body)
+	}
+
+	code, body = get(t, srv, "/v1/features:search?q=whip")
+	var search struct {
+		Results     []struct{ Feature struct{ Name string } }
+		Completions []string
+	}
+	decode(t, body, &search)
+	if code != http.StatusOK || len(search.Results) != 1 || len(search.Completions) != 1 || search.Completions[0] != "whippany" {
+		t.Errorf("GET /v1/features:search?q=whip = %d %s", code, body)
+	}
+
+	code, body = get(t, srv, "/v1/features:reverse?lat=407838351&lng=-746143763")
+	var reverse struct {
+		Address    struct{ City, PostalCode string }
+		Confidence float64
+	}
+	decode(t, body, &reverse)
+	if code != http.StatusOK || reverse.Address.City != "Mendham" || reverse.Address.PostalCode != "07945" || reverse.Confidence != 1 {
+		t.Errorf("GET /v1/features:reverse = %d %s", code, body)
+	}
+}
+
+func TestRecordRoute(t *testing.T) {
+	srv := startGateway(t)
+	mendham := `{"latitude":407838351,"longitude":-746143763}`
+	whippany := `{"latitude":408122808,"longitude":-743999179}`
+
+	for name, body := range map[string]string{
+		"array":  "[" + mendham + ", " + whippany + "]",
+		"ndjson": mendham + "\n" + whippany + "\n",
+	} {
+		resp, err := http.Post(srv.URL+"/v1/routes:record", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var summary struct{ PointCount, FeatureCount, Distance int }
+		if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || summary.PointCount != 2 || summary.FeatureCount != 2 || summary.Distance == 0 {
+			t.Errorf("%s: POST /v1/routes:record = %d %+v, want 2 points and 2 features", name, resp.StatusCode, summary)
+		}
+	}
+
+	for _, body := range []string{"[" + mendham, `{"latitude":"north"}`, "[1]"} {
+		resp, err := http.Post(srv.URL+"/v1/routes:record", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("POST /v1/routes:record %q = %d, want 400", body, resp.StatusCode)
+		}
+	}
+
+	if code, _ := get(t, srv, "/v1/routes:record"); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /v1/routes:record = %d, want 405", code)
+	}
+}
+
+func TestRecordRouteLimits(t *testing.T) {
+	srv := startGateway(t, gateway.WithMaxBodySize(1024), gateway.WithMaxRoutePoints(3))
+	point := `{"latitude":407838351,"longitude":-746143763}` + "\n"
+	post := func(body string) (int, errorBody) {
+		t.Helper()
+		resp, err := http.Post(srv.URL+"/v1/routes:record", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var e errorBody
+		json.NewDecoder(resp.Body).Decode(&e)
+		return resp.StatusCode, e
+	}
+
+	if code, _ := post(strings.Repeat(point, 3)); code != http.StatusOK {
+		t.Errorf("POST 3 points = %d, want 200", code)
+	}
+	if code, e := post(strings.Repeat(point, 4)); code != http.StatusBadRequest || e.Error.Code != "InvalidArgument" {
+		t.Errorf("POST 4 points = %d %+v, want 400 InvalidArgument", code, e)
+	}
+	// 一个很长的值也会被请求体的大小限制住，不会整个读进内存
+	if code, e := post(`{"latitude":"` + strings.Repeat("9", 2048) + `"}`); code != http.StatusRequestEntityTooLarge || e.Error.Code != "ResourceExhausted" {
+		t.Errorf("POST a large body = %d %+v, want 413 ResourceExhausted", code, e)
+	}
+}
+
+func TestHTTPStatusFromCode(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:                 200,
+		codes.Canceled:           499,
+		codes.Unknown:            500,
+		codes.InvalidArgument:    400,
+		codes.DeadlineExceeded:   504,
+		codes.NotFound:           404,
+		codes.AlreadyExists:      409,
+		codes.PermissionDenied:   403,
+		codes.ResourceExhausted:  429,
+		codes.FailedPrecondition: 400,
+		codes.Aborted:            409,
+		codes.OutOfRange:         400,
+		codes.Unimplemented:      501,
+		codes.Internal:           500,
+		codes.Unavailable:        503,
+		codes.DataLoss:           500,
+		codes.Unauthenticated:    401,
+	}
+	for code, want := range tests {
+		if got := gateway.HTTPStatusFromCode(code); got != want {
+			t.Errorf("HTTPStatusFromCode(%v) = %d, want %d", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"fmt"
+	"testing"
+
+	"gRPCDemo/pb"
+)
+
+// normalize 把任意的 int32 映射到合法的坐标范围内，模糊测试只检查合法的坐标
+func normalize(lat, lng int32) *pb.Point {
+	return &pb.Point{Latitude: lat % (maxLat + 1), Longitude: lng % (maxLng + 1)}
+}
+
+// addDBSeeds 把数据库中相邻的 n 个位置作为一组种子输入
+func addDBSeeds(f *testing.F, n int) {
+	points := append(dbPoints(f), specialPoints...)
+	for i := 0; i+n <= len(points); i++ {
+		args := make([]interface{}, 0, 2*n)
+		for _, p := range points[i : i+n] {
+			args = append(args, p.Latitude, p.Longitude)
+		}
+		f.Add(args...)
+	}
+}
+
+func FuzzDistance(f *testing.F) {
+	addDBSeeds(f, 3)
+	f.Fuzz(func(t *testing.T, lat1, lng1, lat2, lng2, lat3, lng3 int32) {
+		a, b, c := normalize(lat1, lng1), normalize(lat2, lng2), normalize(lat3, lng3)
+		ab, ba := Distance(a, b), Distance(b, a)
+		if ab != ba {
+			t.Fatalf("Distance(%v, %v) = %d, reversed = %d", a, b, ab, ba)
+		}
+		if d := Distance(a, a); d != 0 {
+			t.Fatalf("Distance(%v, itself) = %d", a, d)
+		}
+		if ab < 0 || ab > 20015087 {
+			t.Fatalf("Distance(%v, %v) = %d, out of [0, πR]", a, b, ab)
+		}
+		if ac, bc := Distance(a, c), Distance(b, c); ac > ab+bc+2 {
+			t.Fatalf("triangle inequality: d(a,c) = %d > d(a,b) + d(b,c) = %d + %d", ac, ab, bc)
+		}
+	})
+}
+
+func FuzzInRange(f *testing.F) {
+	addDBSeeds(f, 3)
+	f.Fuzz(func(t *testing.T, lat, lng, lat1, lng1, lat2, lng2 int32) {
+		p, a, b := normalize(lat, lng), normalize(lat1, lng1), normalize(lat2, lng2)
+		want := InRange(p, &pb.Rectangle{Lo: a, Hi: b})
+		swapped := &pb.Rectangle{Lo: b, Hi: a}
+		mixed := &pb.Rectangle{
+			Lo: &pb.Point{Latitude: a.Latitude, Longitude: b.Longitude},
+			Hi: &pb.Point{Latitude: b.Latitude, Longitude: a.Longitude},
+		}
+		if InRange(p, swapped) != want || InRange(p, mixed) != want {
+			t.Fatalf("InRange(%v) depends on the order of the corners %v, %v", p, a, b)
+		}
+		if !InRange(a, swapped) || !InRange(b, mixed) {
+			t.Fatalf("corners %v, %v are not in their own rectangle", a, b)
+		}
+	})
+}
+
+func FuzzInPolygon(f *testing.F) {
+	addDBSeeds(f, 3)
+	f.Fuzz(func(t *testing.T, lat, lng, lat1, lng1, lat2, lng2 int32) {
+		p, a, b := normalize(lat, lng), normalize(lat1, lng1), normalize(lat2, lng2)
+		if got, want := InPolygon(p, rectPolygon(a, b)), InRange(p, &pb.Rectangle{Lo: a, Hi: b}); got != want {
+			t.Fatalf("InPolygon(%v, rectangle %v, %v) = %v, InRange = %v", p, a, b, got, want)
+		}
+		// 三角形的顶点和边的中点都在三角形中
+		triangle := &pb.Polygon{Exterior: &pb.LinearRing{Points: []*pb.Point{p, a, b}}}
+		mid := &pb.Point{
+			Latitude:  int32((int64(a.Latitude) + int64(b.Latitude)) / 2),
+			Longitude: int32((int64(a.Longitude) + int64(b.Longitude)) / 2),
+		}
+		if !InPolygon(a, triangle) || (a.Latitude%2 == b.Latitude%2 && a.Longitude%2 == b.Longitude%2 && !InPolygon(mid, triangle)) {
+			t.Fatalf("vertex %v or midpoint %v is not in triangle %v, %v, %v", a, mid, p, a, b)
+		}
+	})
+}
+
+func FuzzSerialize(f *testing.F) {
+	addDBSeeds(f, 1)
+	f.Fuzz(func(t *testing.T, lat, lng int32) {
+		p := &pb.Point{Latitude: lat, Longitude: lng}
+		var got pb.Point
+		if _, err := fmt.Sscanf(Serialize(p), "%d %d", &got.Latitude, &got.Longitude); err != nil {
+			t.Fatalf("Serialize(%v) = %q: %v", p, Serialize(p), err)
+		}
+		if got.Latitude != lat || got.Longitude != lng {
+			t.Fatalf("Serialize(%v) = %q, parsed back as %v", p, Serialize(p), &got)
+		}
+	})
+}
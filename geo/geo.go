@@ -0,0 +1,67 @@
+// Package geo 是 RouteGuide 使用的几何计算
+//
+// 坐标使用 E7 表示，即度数乘以 10^7，纬度的范围是 [-90°, 90°]，经度的范围是 [-180°, 180°]。
+// 所有函数都接受 nil 的 Point，它的经纬度视为 0。
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"gRPCDemo/pb"
+)
+
+// CoordFactor 是 E7 坐标和度数之间的换算系数
+const CoordFactor float64 = 1e7
+
+// EarthRadius 是计算距离时使用的地球半径，单位是米
+const EarthRadius float64 = 6371000
+
+func toRadians(num float64) float64 {
+	return num * math.Pi / float64(180)
+}
+
+// Distance 使用 haversine 公式计算两个点之间的大圆距离，单位是米，小数部分被舍去
+func Distance(p1 *pb.Point, p2 *pb.Point) int32 {
+	return int32(haversine(p1, p2))
+}
+
+func haversine(p1, p2 *pb.Point) float64 {
+	lat1 := toRadians(float64(p1.GetLatitude()) / CoordFactor)
+	lat2 := toRadians(float64(p2.GetLatitude()) / CoordFactor)
+	lng1 := toRadians(float64(p1.GetLongitude()) / CoordFactor)
+	lng2 := toRadians(float64(p2.GetLongitude()) / CoordFactor)
+	dlat := lat2 - lat1
+	dlng := lng2 - lng1
+
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlng/2)*math.Sin(dlng/2)
+	// 对跖点附近的舍入误差可能让 a 略大于 1，这时 math.Sqrt(1-a) 会返回 NaN
+	a = math.Max(0, math.Min(1, a))
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return EarthRadius * c
+}
+
+// InRange 判断 point 是否在 rect 所划定的范围内，边界上的点也在范围内。
+// rect 的两个角可以是任意两个相对的角，跨越 180° 经线的矩形不被支持。
+// rect 缺少任意一个角时返回 false
+func InRange(point *pb.Point, rect *pb.Rectangle) bool {
+	if point == nil || rect.GetLo() == nil || rect.GetHi() == nil {
+		return false
+	}
+	lo, hi := rect.GetLo(), rect.GetHi()
+	left := math.Min(float64(lo.Longitude), float64(hi.Longitude))
+	right := math.Max(float64(lo.Longitude), float64(hi.Longitude))
+	top := math.Max(float64(lo.Latitude), float64(hi.Latitude))
+	bottom := math.Min(float64(lo.Latitude), float64(hi.Latitude))
+
+	return float64(point.Longitude) >= left &&
+		float64(point.Longitude) <= right &&
+		float64(point.Latitude) <= top &&
+		float64(point.Latitude) >= bottom
+}
+
+// Serialize 返回 point 的字符串形式，不同的点对应不同的字符串，可以作为 map 的 key
+func Serialize(point *pb.Point) string {
+	return fmt.Sprintf("%d %d", point.GetLatitude(), point.GetLongitude())
+}
@@ -0,0 +1,216 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"gRPCDemo/pb"
+)
+
+const (
+	maxLat = 900000000
+	maxLng = 1800000000
+)
+
+// dbPoints 返回 testdata/route_guide_db.json 中所有特征的位置，作为测试和模糊测试的种子
+func dbPoints(t testing.TB) []*pb.Point {
+	t.Helper()
+	data, err := ioutil.ReadFile("../testdata/route_guide_db.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var features []*pb.Feature
+	if err := json.Unmarshal(data, &features); err != nil {
+		t.Fatal(err)
+	}
+	points := make([]*pb.Point, len(features))
+	for i, f := range features {
+		points[i] = f.Location
+	}
+	return points
+}
+
+// specialPoints 是容易出错的位置：原点、两极和 180° 经线
+var specialPoints = []*pb.Point{
+	{},
+	{Latitude: maxLat},
+	{Latitude: -maxLat},
+	{Longitude: maxLng},
+	{Longitude: -maxLng},
+	{Latitude: maxLat, Longitude: maxLng},
+	{Latitude: -maxLat, Longitude: -maxLng},
+}
+
+// point 是 testing/quick 生成的合法坐标，一部分取自 specialPoints
+type point struct {
+	*pb.Point
+}
+
+func (point) Generate(r *rand.Rand, _ int) reflect.Value {
+	if r.Intn(4) == 0 {
+		return reflect.ValueOf(point{specialPoints[r.Intn(len(specialPoints))]})
+	}
+	return reflect.ValueOf(point{&pb.Point{
+		Latitude:  int32(r.Int63n(2*maxLat+1) - maxLat),
+		Longitude: int32(r.Int63n(2*maxLng+1) - maxLng),
+	}})
+}
+
+func check(t *testing.T, f interface{}) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDistanceSymmetric(t *testing.T) {
+	check(t, func(a, b point) bool {
+		return Distance(a.Point, b.Point) == Distance(b.Point, a.Point)
+	})
+}
+
+func TestDistanceSamePointIsZero(t *testing.T) {
+	check(t, func(a point) bool {
+		return Distance(a.Point, a.Point) == 0
+	})
+}
+
+func TestTriangleInequality(t *testing.T) {
+	// 每个距离都被截断到整米，最多相差 2m
+	check(t, func(a, b, c point) bool {
+		return Distance(a.Point, c.Point) <= Distance(a.Point, b.Point)+Distance(b.Point, c.Point)+2
+	})
+}
+
+func TestDistanceBounds(t *testing.T) {
+	halfCircumference := int32(math.Floor(math.Pi * EarthRadius))
+	check(t, func(a, b point) bool {
+		d := Distance(a.Point, b.Point)
+		return d >= 0 && d <= halfCircumference
+	})
+}
+
+func TestDistanceKnownValues(t *testing.T) {
+	quarter := int32(math.Floor(math.Pi / 2 * EarthRadius))
+	tests := []struct {
+		name   string
+		p1, p2 *pb.Point
+		want   int32
+	}{
+		{"one degree along the equator", &pb.Point{}, &pb.Point{Longitude: 1e7}, 111194},
+		{"north pole, any longitude", &pb.Point{Latitude: maxLat, Longitude: 123456789}, &pb.Point{Latitude: maxLat, Longitude: -maxLng}, 0},
+		{"south pole, any longitude", &pb.Point{Latitude: -maxLat}, &pb.Point{Latitude: -maxLat, Longitude: maxLng}, 0},
+		{"pole to equator", &pb.Point{Latitude: maxLat}, &pb.Point{Longitude: 987654321}, quarter},
+		{"pole to pole", &pb.Point{Latitude: maxLat}, &pb.Point{Latitude: -maxLat}, 2 * quarter},
+		{"antipodes", &pb.Point{Longitude: -maxLng / 2}, &pb.Point{Longitude: maxLng / 2}, 2 * quarter},
+		{"antimeridian, same meridian", &pb.Point{Longitude: maxLng}, &pb.Point{Longitude: -maxLng}, 0},
+		{"antimeridian, short way round", &pb.Point{Longitude: maxLng - 1e7}, &pb.Point{Longitude: -maxLng + 1e7}, 2 * 111194},
+		{"nil is the origin", nil, &pb.Point{Longitude: 1e7}, 111194},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 允许 1m 的舍入误差
+			if got := Distance(tt.p1, tt.p2); got < tt.want-1 || got > tt.want+1 {
+				t.Errorf("Distance(%v, %v) = %d, want %d", tt.p1, tt.p2, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceAlongDBRoute(t *testing.T) {
+	points := dbPoints(t)
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if d := Distance(a, b); d <= 0 && Serialize(a) != Serialize(b) {
+			t.Errorf("Distance(%v, %v) = %d, want a positive distance", a, b, d)
+		}
+	}
+}
+
+func TestInRangeCornerOrder(t *testing.T) {
+	check(t, func(p, a, b point) bool {
+		corners := []*pb.Rectangle{
+			{Lo: a.Point, Hi: b.Point},
+			{Lo: b.Point, Hi: a.Point},
+			{Lo: &pb.Point{Latitude: a.Latitude, Longitude: b.Longitude}, Hi: &pb.Point{Latitude: b.Latitude, Longitude: a.Longitude}},
+			{Lo: &pb.Point{Latitude: b.Latitude, Longitude: a.Longitude}, Hi: &pb.Point{Latitude: a.Latitude, Longitude: b.Longitude}},
+		}
+		want := InRange(p.Point, corners[0])
+		for _, rect := range corners[1:] {
+			if InRange(p.Point, rect) != want {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestInRangeContainsCornersAndCenter(t *testing.T) {
+	check(t, func(a, b point) bool {
+		rect := &pb.Rectangle{Lo: a.Point, Hi: b.Point}
+		center := &pb.Point{
+			Latitude:  int32((int64(a.Latitude) + int64(b.Latitude)) / 2),
+			Longitude: int32((int64(a.Longitude) + int64(b.Longitude)) / 2),
+		}
+		return InRange(a.Point, rect) && InRange(b.Point, rect) && InRange(center, rect)
+	})
+}
+
+func TestInRange(t *testing.T) {
+	rect := &pb.Rectangle{Lo: &pb.Point{Latitude: 10, Longitude: 10}, Hi: &pb.Point{Latitude: 20, Longitude: 20}}
+	tests := []struct {
+		name  string
+		point *pb.Point
+		rect  *pb.Rectangle
+		want  bool
+	}{
+		{"inside", &pb.Point{Latitude: 15, Longitude: 15}, rect, true},
+		{"on the edge", &pb.Point{Latitude: 20, Longitude: 12}, rect, true},
+		{"north", &pb.Point{Latitude: 21, Longitude: 15}, rect, false},
+		{"west", &pb.Point{Latitude: 15, Longitude: 9}, rect, false},
+		{"nil point", nil, rect, false},
+		{"nil rect", &pb.Point{}, nil, false},
+		{"missing corner", &pb.Point{}, &pb.Rectangle{Lo: &pb.Point{}}, false},
+		{"whole world", &pb.Point{Latitude: -maxLat, Longitude: maxLng}, &pb.Rectangle{
+			Lo: &pb.Point{Latitude: -maxLat, Longitude: -maxLng}, Hi: &pb.Point{Latitude: maxLat, Longitude: maxLng},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InRange(tt.point, tt.rect); got != tt.want {
+				t.Errorf("InRange(%v, %v) = %v, want %v", tt.point, tt.rect, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializeInjective(t *testing.T) {
+	check(t, func(a, b point) bool {
+		same := a.Latitude == b.Latitude && a.Longitude == b.Longitude
+		return (Serialize(a.Point) == Serialize(b.Point)) == same
+	})
+	seen := make(map[string]*pb.Point)
+	for _, p := range dbPoints(t) {
+		key := Serialize(p)
+		if prev, ok := seen[key]; ok && (prev.Latitude != p.Latitude || prev.Longitude != p.Longitude) {
+			t.Errorf("Serialize(%v) = Serialize(%v) = %q", p, prev, key)
+		}
+		seen[key] = p
+	}
+	if got := Serialize(nil); got != Serialize(&pb.Point{}) {
+		t.Errorf("Serialize(nil) = %q, want %q", got, Serialize(&pb.Point{}))
+	}
+}
+
+func ExampleDistance() {
+	mendham := &pb.Point{Latitude: 407838351, Longitude: -746143763}
+	whippany := &pb.Point{Latitude: 408122808, Longitude: -743999179}
+	fmt.Println(Distance(mendham, whippany))
+	// Output: 18327
+}
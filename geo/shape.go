@@ -0,0 +1,220 @@
+package geo
+
+import (
+	"math"
+	"math/bits"
+
+	"gRPCDemo/pb"
+)
+
+// InPolygon 判断 point 是否在 polygon 中，边界上的点，包括洞的边界，都在多边形中。
+// 判断使用精确的整数运算，不受舍入误差影响。polygon 没有外环时返回 false
+func InPolygon(point *pb.Point, polygon *pb.Polygon) bool {
+	if point == nil || polygon.GetExterior() == nil {
+		return false
+	}
+	switch locate(point, polygon.GetExterior().GetPoints()) {
+	case outside:
+		return false
+	case boundary:
+		return true
+	}
+	for _, hole := range polygon.GetHoles() {
+		if locate(point, hole.GetPoints()) == inside {
+			return false
+		}
+	}
+	return true
+}
+
+// InCircle 判断 point 到圆心的大圆距离是否不超过半径
+func InCircle(point *pb.Point, circle *pb.Circle) bool {
+	if point == nil || circle.GetCenter() == nil {
+		return false
+	}
+	return haversine(point, circle.GetCenter()) <= circle.GetRadiusMeters()
+}
+
+// InCorridor 判断 point 到 corridor 的折线的距离是否不超过 buffer_meters，折线为空时返回 false
+func InCorridor(point *pb.Point, corridor *pb.Corridor) bool {
+	if point == nil || len(corridor.GetPath()) == 0 {
+		return false
+	}
+	return DistanceToPath(point, corridor.GetPath()) <= corridor.GetBufferMeters()
+}
+
+// DistanceToPath 返回 p 到折线 path 的最短距离，单位是米，path 只有一个点时就是到这个点的距离。
+// path 为空时返回 +Inf
+func DistanceToPath(p *pb.Point, path []*pb.Point) float64 {
+	if len(path) == 1 {
+		return haversine(p, path[0])
+	}
+	d := math.Inf(1)
+	for i := 1; i < len(path); i++ {
+		d = math.Min(d, DistanceToSegment(p, path[i-1], path[i]))
+	}
+	return d
+}
+
+// DistanceToSegment 返回 p 到大圆上的劣弧 ab 的最短距离，单位是米。
+// a 和 b 是对跖点时劣弧不唯一，这时返回到两个端点的距离中较小的一个
+func DistanceToSegment(p, a, b *pb.Point) float64 {
+	P, A, B := toVector(p), toVector(a), toVector(b)
+	n := cross(A, B)
+	if norm(n) < 1e-12 {
+		return math.Min(angle(P, A), angle(P, B)) * EarthRadius
+	}
+	n = scale(n, 1/norm(n))
+	// c 是 p 在 ab 所在大圆上的投影，p 是大圆的极点时 c 为零向量，大圆上所有点到 p 的距离都相同
+	sin := dot(P, n)
+	c := sub(P, scale(n, sin))
+	if norm(c) > 1e-12 && dot(cross(A, c), n) >= 0 && dot(cross(c, B), n) >= 0 {
+		return math.Abs(math.Asin(math.Max(-1, math.Min(1, sin)))) * EarthRadius
+	}
+	return math.Min(angle(P, A), angle(P, B)) * EarthRadius
+}
+
+type vector [3]float64
+
+// toVector 返回 p 在单位球面上对应的向量
+func toVector(p *pb.Point) vector {
+	lat := toRadians(float64(p.GetLatitude()) / CoordFactor)
+	lng := toRadians(float64(p.GetLongitude()) / CoordFactor)
+	return vector{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+func cross(u, v vector) vector {
+	return vector{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+}
+
+func dot(u, v vector) float64 {
+	return u[0]*v[0] + u[1]*v[1] + u[2]*v[2]
+}
+
+func norm(u vector) float64 {
+	return math.Sqrt(dot(u, u))
+}
+
+func scale(u vector, k float64) vector {
+	return vector{u[0] * k, u[1] * k, u[2] * k}
+}
+
+func sub(u, v vector) vector {
+	return vector{u[0] - v[0], u[1] - v[1], u[2] - v[2]}
+}
+
+// angle 返回两个单位向量之间的夹角，距离很近时比 math.Acos(dot(u, v)) 准确
+func angle(u, v vector) float64 {
+	return math.Atan2(norm(cross(u, v)), dot(u, v))
+}
+
+// location 是点相对于环的位置
+type location int
+
+const (
+	outside location = iota
+	boundary
+	inside
+)
+
+// locate 使用非零环绕数规则判断 p 相对于环 ring 的位置，ring 的首尾两个点相同时忽略最后一个点
+func locate(p *pb.Point, ring []*pb.Point) location {
+	n := len(ring)
+	if n > 1 && samePoint(ring[0], ring[n-1]) {
+		n--
+	}
+	if n == 0 {
+		return outside
+	}
+	winding := 0
+	for i := 0; i < n; i++ {
+		a, b := ring[i], ring[(i+1)%n]
+		if onSegment(p, a, b) {
+			return boundary
+		}
+		// 纬度是 y 轴，经度是 x 轴，统计环绕 p 的边的方向
+		if a.GetLatitude() <= p.GetLatitude() {
+			if b.GetLatitude() > p.GetLatitude() && orientation(a, b, p) > 0 {
+				winding++
+			}
+		} else if b.GetLatitude() <= p.GetLatitude() && orientation(a, b, p) < 0 {
+			winding--
+		}
+	}
+	if winding != 0 {
+		return inside
+	}
+	return outside
+}
+
+func samePoint(a, b *pb.Point) bool {
+	return a.GetLatitude() == b.GetLatitude() && a.GetLongitude() == b.GetLongitude()
+}
+
+// onSegment 判断 p 是否在线段 ab 上，包括端点
+func onSegment(p, a, b *pb.Point) bool {
+	if orientation(a, b, p) != 0 {
+		return false
+	}
+	return between(p.GetLatitude(), a.GetLatitude(), b.GetLatitude()) &&
+		between(p.GetLongitude(), a.GetLongitude(), b.GetLongitude())
+}
+
+func between(v, a, b int32) bool {
+	if a > b {
+		a, b = b, a
+	}
+	return a <= v && v <= b
+}
+
+// orientation 返回 c 在有向直线 ab 的哪一侧：1 是左侧，-1 是右侧，0 表示三点共线
+func orientation(a, b, c *pb.Point) int {
+	dx1 := int64(b.GetLongitude()) - int64(a.GetLongitude())
+	dy1 := int64(b.GetLatitude()) - int64(a.GetLatitude())
+	dx2 := int64(c.GetLongitude()) - int64(a.GetLongitude())
+	dy2 := int64(c.GetLatitude()) - int64(a.GetLatitude())
+	return compareProducts(dx1, dy2, dy1, dx2)
+}
+
+// compareProducts 比较 a*b 和 c*d 的大小。E7 坐标差的乘积可能超出 int64 的范围，所以使用 128 位的乘法
+func compareProducts(a, b, c, d int64) int {
+	s1, s2 := sign(a)*sign(b), sign(c)*sign(d)
+	if s1 != s2 {
+		if s1 > s2 {
+			return 1
+		}
+		return -1
+	}
+	if s1 == 0 {
+		return 0
+	}
+	hi1, lo1 := bits.Mul64(abs(a), abs(b))
+	hi2, lo2 := bits.Mul64(abs(c), abs(d))
+	cmp := 0
+	switch {
+	case hi1 != hi2 && hi1 > hi2, hi1 == hi2 && lo1 > lo2:
+		cmp = 1
+	case hi1 != hi2, lo1 < lo2:
+		cmp = -1
+	}
+	// 两个乘积都是负数时，绝对值大的乘积更小
+	return cmp * s1
+}
+
+func sign(v int64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// abs 的参数是坐标差，不会是 math.MinInt64
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
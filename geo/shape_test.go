@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"gRPCDemo/pb"
+)
+
+func ring(coords ...int32) *pb.LinearRing {
+	r := &pb.LinearRing{}
+	for i := 0; i+1 < len(coords); i += 2 {
+		r.Points = append(r.Points, &pb.Point{Latitude: coords[i], Longitude: coords[i+1]})
+	}
+	return r
+}
+
+// rectPolygon 返回和 InRange 使用的矩形相同的多边形
+func rectPolygon(a, b *pb.Point) *pb.Polygon {
+	return &pb.Polygon{Exterior: ring(
+		a.Latitude, a.Longitude,
+		a.Latitude, b.Longitude,
+		b.Latitude, b.Longitude,
+		b.Latitude, a.Longitude,
+	)}
+}
+
+func TestInPolygon(t *testing.T) {
+	// 0..100 的正方形，中间挖去 40..60 的正方形
+	square := &pb.Polygon{
+		Exterior: ring(0, 0, 0, 100, 100, 100, 100, 0, 0, 0),
+		Holes:    []*pb.LinearRing{ring(40, 40, 60, 40, 60, 60, 40, 60)},
+	}
+	// U 形，缺口是纬度 30..100、经度 30..70 的部分
+	u := &pb.Polygon{Exterior: ring(0, 0, 0, 100, 100, 100, 100, 70, 30, 70, 30, 30, 100, 30, 100, 0)}
+	tests := []struct {
+		name    string
+		point   *pb.Point
+		polygon *pb.Polygon
+		want    bool
+	}{
+		{"inside", &pb.Point{Latitude: 20, Longitude: 20}, square, true},
+		{"in the hole", &pb.Point{Latitude: 50, Longitude: 50}, square, false},
+		{"on the hole boundary", &pb.Point{Latitude: 40, Longitude: 50}, square, true},
+		{"on the edge", &pb.Point{Latitude: 0, Longitude: 50}, square, true},
+		{"on a vertex", &pb.Point{Latitude: 100, Longitude: 100}, square, true},
+		{"outside", &pb.Point{Latitude: 101, Longitude: 50}, square, false},
+		{"level with a vertex", &pb.Point{Latitude: 100, Longitude: -1}, square, false},
+		{"in the notch", &pb.Point{Latitude: 50, Longitude: 50}, u, false},
+		{"in an arm", &pb.Point{Latitude: 50, Longitude: 80}, u, true},
+		{"below the notch", &pb.Point{Latitude: 20, Longitude: 50}, u, true},
+		{"nil point", nil, square, false},
+		{"nil polygon", &pb.Point{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InPolygon(tt.point, tt.polygon); got != tt.want {
+				t.Errorf("InPolygon(%v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInPolygonMatchesInRange(t *testing.T) {
+	check(t, func(p, a, b point) bool {
+		return InPolygon(p.Point, rectPolygon(a.Point, b.Point)) == InRange(p.Point, &pb.Rectangle{Lo: a.Point, Hi: b.Point})
+	})
+}
+
+// 环的方向和是否闭合不影响结果
+func TestInPolygonRingOrder(t *testing.T) {
+	check(t, func(p, a, b, c point) bool {
+		open := &pb.Polygon{Exterior: &pb.LinearRing{Points: []*pb.Point{a.Point, b.Point, c.Point}}}
+		closed := &pb.Polygon{Exterior: &pb.LinearRing{Points: []*pb.Point{a.Point, b.Point, c.Point, a.Point}}}
+		reversed := &pb.Polygon{Exterior: &pb.LinearRing{Points: []*pb.Point{c.Point, b.Point, a.Point}}}
+		want := InPolygon(p.Point, open)
+		return InPolygon(p.Point, closed) == want && InPolygon(p.Point, reversed) == want
+	})
+}
+
+func TestCompareProducts(t *testing.T) {
+	// 坐标差的乘积超出 int64 的范围
+	check(t, func(a, b, c, d point) bool {
+		x1, y1 := int64(a.Longitude)-int64(b.Longitude), int64(a.Latitude)-int64(b.Latitude)
+		x2, y2 := int64(c.Longitude)-int64(d.Longitude), int64(c.Latitude)-int64(d.Latitude)
+		want := new(big.Int).Mul(big.NewInt(x1), big.NewInt(y2)).Cmp(new(big.Int).Mul(big.NewInt(x2), big.NewInt(y1)))
+		return compareProducts(x1, y2, x2, y1) == want
+	})
+	// 对角线上的点和对角线共线，旁边的点不共线
+	lo, hi := &pb.Point{Latitude: -maxLat, Longitude: -maxLng}, &pb.Point{Latitude: maxLat, Longitude: maxLng}
+	if got := orientation(lo, hi, &pb.Point{Latitude: maxLat / 2, Longitude: maxLng / 2}); got != 0 {
+		t.Errorf("orientation(diagonal midpoint) = %d, want 0", got)
+	}
+	if got := orientation(lo, hi, &pb.Point{Latitude: maxLat/2 + 1, Longitude: maxLng / 2}); got != 1 {
+		t.Errorf("orientation(north of the diagonal) = %d, want 1", got)
+	}
+}
+
+func TestDistanceToSegment(t *testing.T) {
+	const degree = 111194.9 // 赤道上 1° 的长度
+	a, b := &pb.Point{}, &pb.Point{Longitude: 1e7}
+	tests := []struct {
+		name    string
+		p, a, b *pb.Point
+		want    float64
+	}{
+		{"on the segment", &pb.Point{Longitude: 5e6}, a, b, 0},
+		{"north of the middle", &pb.Point{Latitude: 1e6, Longitude: 5e6}, a, b, degree / 10},
+		{"south of the middle", &pb.Point{Latitude: -1e6, Longitude: 5e6}, a, b, degree / 10},
+		{"beyond the end", &pb.Point{Longitude: 2e7}, a, b, degree},
+		{"before the start", &pb.Point{Longitude: -1e7}, a, b, degree},
+		{"degenerate segment", &pb.Point{Longitude: 1e7}, a, a, degree},
+		{"pole of the segment", &pb.Point{Latitude: maxLat}, a, b, math.Pi / 2 * EarthRadius},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistanceToSegment(tt.p, tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("DistanceToSegment(%v, %v, %v) = %f, want %f", tt.p, tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	// 到端点的距离和 DistanceToSegment 一样用向量的夹角计算，haversine 在对跖点附近和它相差几微米
+	inRange := func(p, a, b *pb.Point) bool {
+		d := DistanceToSegment(p, a, b)
+		P := toVector(p)
+		ends := math.Min(angle(P, toVector(a)), angle(P, toVector(b))) * EarthRadius
+		return d >= 0 && d <= ends+1e-3 && DistanceToSegment(a, a, b) < 1e-3
+	}
+	if p, a, b := (&pb.Point{Latitude: -899000277, Longitude: -1207507929}), (&pb.Point{Latitude: maxLat, Longitude: maxLng}), (&pb.Point{Latitude: 898237363, Longitude: 784865941}); !inRange(p, a, b) {
+		t.Errorf("DistanceToSegment(%v, %v, %v) is farther than the nearest end", p, a, b)
+	}
+	check(t, func(p, a, b point) bool {
+		return inRange(p.Point, a.Point, b.Point)
+	})
+}
+
+func TestInCircleAndCorridor(t *testing.T) {
+	mendham := &pb.Point{Latitude: 407838351, Longitude: -746143763}
+	whippany := &pb.Point{Latitude: 408122808, Longitude: -743999179}
+	// 两点相距 18327m
+	if !InCircle(whippany, &pb.Circle{Center: mendham, RadiusMeters: 18400}) {
+		t.Errorf("InCircle(18400m) = false, want true")
+	}
+	if InCircle(whippany, &pb.Circle{Center: mendham, RadiusMeters: 18300}) {
+		t.Errorf("InCircle(18300m) = true, want false")
+	}
+	if InCircle(whippany, nil) {
+		t.Errorf("InCircle(nil) = true, want false")
+	}
+
+	// 两点之间的折线经过中点
+	middle := &pb.Point{Latitude: (mendham.Latitude + whippany.Latitude) / 2, Longitude: (mendham.Longitude + whippany.Longitude) / 2}
+	path := &pb.Corridor{Path: []*pb.Point{mendham, whippany}, BufferMeters: 10}
+	if !InCorridor(middle, path) {
+		t.Errorf("InCorridor(midpoint) = false, want true, distance %f", DistanceToPath(middle, path.Path))
+	}
+	north := &pb.Point{Latitude: middle.Latitude + 1e4, Longitude: middle.Longitude}
+	if InCorridor(north, path) {
+		t.Errorf("InCorridor(111m north of the path) = true, want false")
+	}
+	if got := DistanceToPath(whippany, []*pb.Point{mendham}); math.Abs(got-18327.5) > 1 {
+		t.Errorf("DistanceToPath(single point) = %f, want 18327", got)
+	}
+	if InCorridor(middle, &pb.Corridor{BufferMeters: 10}) {
+		t.Errorf("InCorridor(empty path) = true, want false")
+	}
+}
@@ -0,0 +1,402 @@
+// Package grpcweb 让浏览器可以访问 gRPC 服务
+//
+// Handler 实现了 gRPC-Web 协议，支持一元调用和服务端流式调用；
+// WebSocketHandler 则把 WebSocket 上的 JSON 帧映射到双向流上，
+// 用来支持浏览器中无法使用 gRPC-Web 的 RouteChat 和 Conversations。
+//
+// 两个 Handler 只暴露传入的 services，调用方不应该传入管理接口、reflection 和健康检查等服务。
+// 带有 Origin 头的请求只有在 Origin 和请求的 Host 相同，或者在 WithAllowedOrigins 的列表中时才会被处理，
+// 没有 Origin 头的请求不是浏览器发出的，不做检查
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	contentTypeWeb     = "application/grpc-web"
+	contentTypeWebText = "application/grpc-web-text"
+
+	// trailerFlag 标记 gRPC-Web 响应中的 trailer 帧
+	trailerFlag byte = 0x80
+)
+
+// DefaultMaxMsgSize 是没有设置 WithMaxMsgSize 时单个请求消息的最大字节数，和 gRPC 服务端的默认值相同
+const DefaultMaxMsgSize = 4 << 20
+
+type options struct {
+	allowAllOrigins bool
+	origins         map[string]bool
+	maxMsgSize      int
+}
+
+func newOptions(opts []Option) options {
+	o := options{origins: make(map[string]bool), maxMsgSize: DefaultMaxMsgSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Option 配置 Handler 和 WebSocketHandler
+type Option func(*options)
+
+// WithAllowedOrigins 允许来自 origins 的跨域请求，例如 https://example.com。
+// "*" 表示允许所有的 Origin，这时 gRPC-Web 的响应不允许浏览器带上 cookie 等凭据
+func WithAllowedOrigins(origins ...string) Option {
+	return func(o *options) {
+		for _, origin := range origins {
+			if origin == "*" {
+				o.allowAllOrigins = true
+				continue
+			}
+			o.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+		}
+	}
+}
+
+// WithMaxMsgSize 设置单个请求消息的最大字节数，应该和服务端的 grpc.MaxRecvMsgSize 相同，n 为 0 时使用 DefaultMaxMsgSize
+func WithMaxMsgSize(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxMsgSize = n
+		}
+	}
+}
+
+// originAllowed 检查 r 的 Origin 头，trusted 表示可以把凭据发送给这个 Origin
+func (o *options) originAllowed(r *http.Request) (allowed, trusted bool) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true, false
+	}
+	if o.origins[strings.ToLower(origin)] {
+		return true, true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true, true
+	}
+	return o.allowAllOrigins, false
+}
+
+// methodInfo 记录方法的流类型，key 是 /pkg.Service/Method 形式的全名
+type methodInfo struct {
+	clientStreams bool
+	serverStreams bool
+}
+
+func methodsOf(services map[string]grpc.ServiceInfo) map[string]methodInfo {
+	methods := make(map[string]methodInfo)
+	for name, info := range services {
+		for _, m := range info.Methods {
+			methods[fmt.Sprintf("/%s/%s", name, m.Name)] = methodInfo{
+				clientStreams: m.IsClientStream,
+				serverStreams: m.IsServerStream,
+			}
+		}
+	}
+	return methods
+}
+
+// Handler 把 gRPC-Web 请求转发到 conn 上
+type Handler struct {
+	conn    grpc.ClientConnInterface
+	methods map[string]methodInfo
+	opts    options
+}
+
+// NewHandler 创建 gRPC-Web Handler，services 是允许浏览器调用的服务，通常从 grpc.Server.GetServiceInfo 中选出
+func NewHandler(conn grpc.ClientConnInterface, services map[string]grpc.ServiceInfo, opts ...Option) *Handler {
+	return &Handler{conn: conn, methods: methodsOf(services), opts: newOptions(opts)}
+}
+
+// IsGRPCWebRequest 判断 r 是否是 gRPC-Web 请求，包括 CORS 预检请求
+func IsGRPCWebRequest(r *http.Request) bool {
+	if r.Method == http.MethodOptions {
+		return strings.Contains(strings.ToLower(r.Header.Get("Access-Control-Request-Headers")), "x-grpc-web")
+	}
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeWeb)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowed, trusted := h.opts.originAllowed(r)
+	if !allowed {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	setCORSHeaders(w, r, trusted)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "gRPC-Web requests must be POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	text := strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeWebText)
+	respType := contentTypeWeb + "+proto"
+	if text {
+		respType = contentTypeWebText + "+proto"
+	}
+	rw := &responseWriter{w: w, text: text}
+
+	method, ok := h.methods[r.URL.Path]
+	if !ok {
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.Newf(codes.Unimplemented, "unknown method %s", r.URL.Path), nil)
+		return
+	}
+	if method.clientStreams {
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.Newf(codes.Unimplemented, "%s is client streaming, use the WebSocket bridge", r.URL.Path), nil)
+		return
+	}
+
+	// 消息前面有 5 个字节的帧头，-text 格式的请求体还经过了 base64 编码
+	limit := 5 + h.opts.maxMsgSize
+	if text {
+		limit = base64.StdEncoding.EncodedLen(limit)
+	}
+	req, err := readRequest(http.MaxBytesReader(w, r.Body, int64(limit)), text)
+	if err != nil {
+		code := codes.InvalidArgument
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code = codes.ResourceExhausted
+			err = fmt.Errorf("request message larger than max (%d)", h.opts.maxMsgSize)
+		}
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.New(code, err.Error()), nil)
+		return
+	}
+
+	ctx, cancel := requestContext(r)
+	defer cancel()
+	stream, err := h.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: method.serverStreams}, r.URL.Path, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.Convert(err), nil)
+		return
+	}
+	if err := stream.SendMsg(&req); err != nil && err != io.EOF {
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.Convert(err), nil)
+		return
+	}
+	if err := stream.CloseSend(); err != nil {
+		rw.writeHeader(respType, nil)
+		rw.writeTrailer(status.Convert(err), nil)
+		return
+	}
+
+	header, _ := stream.Header()
+	rw.writeHeader(respType, header)
+	for {
+		var resp []byte
+		err := stream.RecvMsg(&resp)
+		if err == io.EOF {
+			rw.writeTrailer(status.New(codes.OK, ""), stream.Trailer())
+			return
+		}
+		if err != nil {
+			rw.writeTrailer(status.Convert(err), stream.Trailer())
+			return
+		}
+		rw.writeFrame(0, resp)
+	}
+}
+
+// readRequest 读取请求体中的第一条消息，gRPC-Web 只支持单个请求消息
+func readRequest(body io.Reader, text bool) ([]byte, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if text {
+		if data, err = base64.StdEncoding.DecodeString(string(data)); err != nil {
+			return nil, fmt.Errorf("invalid base64 body: %v", err)
+		}
+	}
+	if len(data) < 5 {
+		return nil, fmt.Errorf("request body too short")
+	}
+	if data[0] != 0 {
+		return nil, fmt.Errorf("compressed requests are not supported")
+	}
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return nil, fmt.Errorf("request message truncated")
+	}
+	return data[5 : 5+length], nil
+}
+
+// requestContext 将请求头转换成 outgoing metadata，并处理 grpc-timeout
+func requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	md := metadata.MD{}
+	for k, vs := range r.Header {
+		k = strings.ToLower(k)
+		switch {
+		case k == "content-type", k == "content-length", k == "grpc-timeout", k == "connection",
+			k == "x-grpc-web", k == "x-user-agent", k == "user-agent", k == "origin", k == "cookie",
+			strings.HasPrefix(k, "accept"), strings.HasPrefix(k, "sec-"):
+			continue
+		}
+		md[k] = append(md[k], vs...)
+	}
+	ctx := metadata.NewOutgoingContext(r.Context(), md)
+	if d, ok := parseTimeout(r.Header.Get("grpc-timeout")); ok {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// parseTimeout 解析 gRPC 协议中 grpc-timeout 的格式，例如 100m, 5S
+func parseTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// setCORSHeaders 设置跨域访问需要的响应头，只有 trusted 的 Origin 才允许带上凭据
+func setCORSHeaders(w http.ResponseWriter, r *http.Request, trusted bool) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	h := w.Header()
+	if trusted {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+	} else {
+		h.Set("Access-Control-Allow-Origin", "*")
+	}
+	h.Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	h.Set("Access-Control-Allow-Headers", "content-type, x-grpc-web, x-user-agent, grpc-timeout, authorization, x-request-id")
+	h.Set("Access-Control-Expose-Headers", "grpc-status, grpc-message, x-request-id")
+	h.Add("Vary", "Origin")
+}
+
+// responseWriter 按照 gRPC-Web 的格式写入消息帧和 trailer 帧
+type responseWriter struct {
+	w    http.ResponseWriter
+	text bool
+}
+
+func (rw *responseWriter) writeHeader(contentType string, md metadata.MD) {
+	h := rw.w.Header()
+	for k, vs := range md {
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+			h.Add(k, v)
+		}
+	}
+	h.Set("Content-Type", contentType)
+	rw.w.WriteHeader(http.StatusOK)
+}
+
+func (rw *responseWriter) writeFrame(flag byte, data []byte) {
+	frame := make([]byte, 5+len(data))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+	if rw.text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+	}
+	rw.w.Write(frame)
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *responseWriter) writeTrailer(s *status.Status, md metadata.MD) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "grpc-status: %d\r\n", s.Code())
+	fmt.Fprintf(&b, "grpc-message: %s\r\n", encodeGrpcMessage(s.Message()))
+	for k, vs := range md {
+		// grpc-status 和 grpc-message 已经写过了，content-type 是只有 trailer 的响应中带过来的
+		if k == "grpc-status" || k == "grpc-message" || k == "content-type" {
+			continue
+		}
+		for _, v := range vs {
+			// 和 HTTP/2 上的 gRPC 一样，二进制的值需要 base64 编码
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+			fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+		}
+	}
+	rw.writeFrame(trailerFlag, []byte(b.String()))
+}
+
+// encodeGrpcMessage 按照 gRPC 协议对 grpc-message 进行百分号编码
+func encodeGrpcMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// rawCodec 不做任何编解码，直接转发消息的字节
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unexpected type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: unexpected type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,324 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var mendham = &pb.Point{Latitude: 407838351, Longitude: -746143763}
+
+// serviceInfo 把 desc 转换成 grpc.Server.GetServiceInfo 返回的格式
+func serviceInfo(descs ...*grpc.ServiceDesc) map[string]grpc.ServiceInfo {
+	services := make(map[string]grpc.ServiceInfo)
+	for _, desc := range descs {
+		var info grpc.ServiceInfo
+		for _, m := range desc.Methods {
+			info.Methods = append(info.Methods, grpc.MethodInfo{Name: m.MethodName})
+		}
+		for _, s := range desc.Streams {
+			info.Methods = append(info.Methods, grpc.MethodInfo{Name: s.StreamName, IsClientStream: s.ClientStreams, IsServerStream: s.ServerStreams})
+		}
+		services[desc.ServiceName] = info
+	}
+	return services
+}
+
+func startWeb(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	env := servicetest.Start(t, servicetest.WithServerOptions(grpc.UnaryInterceptor(validate.UnaryServerInterceptor)))
+	services := serviceInfo(&pb.RouteGuide_ServiceDesc, &pb.Echo_ServiceDesc)
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix+"/", NewWebSocketHandler(env.Conn, services, opts...))
+	mux.Handle("/", NewHandler(env.Conn, services, opts...))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func frame(flag byte, data []byte) []byte {
+	f := make([]byte, 5+len(data))
+	f[0] = flag
+	binary.BigEndian.PutUint32(f[1:5], uint32(len(data)))
+	copy(f[5:], data)
+	return f
+}
+
+// decodeText 解码 -text 格式的响应，每一帧是单独编码的，帧之间可能有填充
+func decodeText(t *testing.T, s string) []byte {
+	t.Helper()
+	var out []byte
+	for len(s) > 0 {
+		end := len(s)
+		// 以 = 结尾的 4 字节组是一段 base64 的结束
+		for i := 0; i+4 <= len(s); i += 4 {
+			if strings.Contains(s[i:i+4], "=") {
+				end = i + 4
+				break
+			}
+		}
+		data, err := base64.StdEncoding.DecodeString(s[:end])
+		if err != nil {
+			t.Fatalf("invalid base64 response %q: %v", s, err)
+		}
+		out = append(out, data...)
+		s = s[end:]
+	}
+	return out
+}
+
+type webResponse struct {
+	status   int
+	header   http.Header
+	messages [][]byte
+	trailer  map[string]string
+}
+
+// call 发送 gRPC-Web 请求并解析响应中的消息帧和 trailer 帧
+func call(t *testing.T, srv *httptest.Server, method string, body []byte, text bool, header http.Header) webResponse {
+	t.Helper()
+	contentType := "application/grpc-web+proto"
+	if text {
+		contentType = "application/grpc-web-text"
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+method, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Grpc-Web", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := webResponse{status: resp.StatusCode, header: resp.Header}
+	if resp.StatusCode != http.StatusOK {
+		return r
+	}
+	if text {
+		data = decodeText(t, string(data))
+	}
+	for len(data) > 0 {
+		if len(data) < 5 {
+			t.Fatalf("truncated frame header %x", data)
+		}
+		n := binary.BigEndian.Uint32(data[1:5])
+		payload := data[5 : 5+n]
+		if data[0]&trailerFlag != 0 {
+			r.trailer = make(map[string]string)
+			for _, line := range strings.Split(strings.TrimSpace(string(payload)), "\r\n") {
+				k, v, _ := strings.Cut(line, ": ")
+				r.trailer[k] = v
+			}
+		} else {
+			if r.trailer != nil {
+				t.Errorf("message frame after the trailer frame")
+			}
+			r.messages = append(r.messages, payload)
+		}
+		data = data[5+n:]
+	}
+	if r.trailer == nil {
+		t.Fatalf("%s: response has no trailer frame", method)
+	}
+	return r
+}
+
+func marshal(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestUnary(t *testing.T) {
+	srv := startWeb(t)
+	for _, text := range []bool{false, true} {
+		resp := call(t, srv, "/routeguide.RouteGuide/GetFeature", frame(0, marshal(t, mendham)), text, nil)
+		if resp.trailer["grpc-status"] != "0" || len(resp.messages) != 1 {
+			t.Fatalf("text=%v: GetFeature = %d messages, trailer %v", text, len(resp.messages), resp.trailer)
+		}
+		feature := new(pb.Feature)
+		if err := proto.Unmarshal(resp.messages[0], feature); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(feature.Name, "Mendham") {
+			t.Errorf("text=%v: GetFeature = %v, want Mendham", text, feature)
+		}
+		want := "application/grpc-web+proto"
+		if text {
+			want = "application/grpc-web-text+proto"
+		}
+		if ct := resp.header.Get("Content-Type"); ct != want {
+			t.Errorf("text=%v: Content-Type = %q, want %q", text, ct, want)
+		}
+	}
+}
+
+func TestServerStreaming(t *testing.T) {
+	srv := startWeb(t)
+	rect := &pb.Rectangle{Lo: &pb.Point{Latitude: 400000000, Longitude: -750000000}, Hi: &pb.Point{Latitude: 420000000, Longitude: -730000000}}
+	for _, text := range []bool{false, true} {
+		resp := call(t, srv, "/routeguide.RouteGuide/ListFeatures", frame(0, marshal(t, rect)), text, nil)
+		if resp.trailer["grpc-status"] != "0" || len(resp.messages) != len(servicetest.Features()) {
+			t.Errorf("text=%v: ListFeatures = %d messages, trailer %v, want %d", text, len(resp.messages), resp.trailer, len(servicetest.Features()))
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := startWeb(t, WithMaxMsgSize(64))
+	invalid := marshal(t, &pb.Point{Latitude: 1000000000})
+	tests := []struct {
+		name   string
+		method string
+		body   []byte
+		code   codes.Code
+		msg    string
+	}{
+		{"unknown method", "/routeguide.RouteGuide/Nope", frame(0, nil), codes.Unimplemented, "unknown method"},
+		{"hidden service", "/routeguide.FaultInjection/SetFaults", frame(0, nil), codes.Unimplemented, "unknown method"},
+		{"client streaming", "/routeguide.RouteGuide/RecordRoute", frame(0, nil), codes.Unimplemented, "WebSocket"},
+		{"short body", "/routeguide.RouteGuide/GetFeature", []byte{0, 0}, codes.InvalidArgument, "too short"},
+		{"compressed", "/routeguide.RouteGuide/GetFeature", frame(1, nil), codes.InvalidArgument, "compressed"},
+		{"truncated", "/routeguide.RouteGuide/GetFeature", frame(0, invalid)[:6], codes.InvalidArgument, "truncated"},
+		{"too large", "/routeguide.RouteGuide/GetFeature", frame(0, make([]byte, 100)), codes.ResourceExhausted, "larger than max"},
+		{"validation", "/routeguide.RouteGuide/GetFeature", frame(0, invalid), codes.InvalidArgument, "latitude"},
+	}
+	for _, tt := range tests {
+		for _, text := range []bool{false, true} {
+			resp := call(t, srv, tt.method, tt.body, text, nil)
+			if want := strconv.Itoa(int(tt.code)); resp.trailer["grpc-status"] != want || !strings.Contains(resp.trailer["grpc-message"], tt.msg) {
+				t.Errorf("%s (text=%v): trailer = %v, want grpc-status %s and a message containing %q", tt.name, text, resp.trailer, want, tt.msg)
+			}
+			if _, ok := resp.trailer["content-type"]; ok {
+				t.Errorf("%s (text=%v): trailer contains content-type", tt.name, text)
+			}
+			if len(resp.messages) != 0 {
+				t.Errorf("%s (text=%v): got %d messages, want none", tt.name, text, len(resp.messages))
+			}
+		}
+	}
+}
+
+func TestBinaryTrailer(t *testing.T) {
+	srv := startWeb(t)
+	resp := call(t, srv, "/routeguide.RouteGuide/GetFeature", frame(0, marshal(t, &pb.Point{Latitude: 1000000000})), false, nil)
+	details, err := base64.StdEncoding.DecodeString(resp.trailer["grpc-status-details-bin"])
+	if err != nil || !bytes.Contains(details, []byte("google.rpc.BadRequest")) {
+		t.Errorf("grpc-status-details-bin = %q, %v, want base64 encoded status details", resp.trailer["grpc-status-details-bin"], err)
+	}
+}
+
+func TestEncodeGrpcMessage(t *testing.T) {
+	if got := encodeGrpcMessage("a b%c\n你"); got != "a b%25c%0A%E4%BD%A0" {
+		t.Errorf("encodeGrpcMessage() = %q", got)
+	}
+}
+
+func TestCloseCode(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:              1000,
+		codes.Canceled:        4001,
+		codes.InvalidArgument: 4003,
+		codes.NotFound:        4005,
+		codes.Unavailable:     4014,
+	}
+	for code, want := range tests {
+		if got := CloseCode(code); got != want {
+			t.Errorf("CloseCode(%v) = %d, want %d", code, got, want)
+		}
+	}
+}
+
+func TestOrigins(t *testing.T) {
+	body := frame(0, marshal(t, mendham))
+	tests := []struct {
+		name        string
+		opts        []Option
+		origin      string
+		status      int
+		allowOrigin string
+		credentials string
+	}{
+		{"no origin", nil, "", http.StatusOK, "", ""},
+		{"unknown origin", nil, "https://evil.example", http.StatusForbidden, "", ""},
+		{"allowed origin", []Option{WithAllowedOrigins("https://app.example/")}, "https://app.example", http.StatusOK, "https://app.example", "true"},
+		{"other origin", []Option{WithAllowedOrigins("https://app.example")}, "https://evil.example", http.StatusForbidden, "", ""},
+		{"any origin", []Option{WithAllowedOrigins("*")}, "https://evil.example", http.StatusOK, "*", ""},
+	}
+	for _, tt := range tests {
+		srv := startWeb(t, tt.opts...)
+		header := http.Header{}
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+		resp := call(t, srv, "/routeguide.RouteGuide/GetFeature", body, false, header)
+		if resp.status != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.status, tt.status)
+			continue
+		}
+		if got := resp.header.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", tt.name, got, tt.allowOrigin)
+		}
+		if got := resp.header.Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+			t.Errorf("%s: Access-Control-Allow-Credentials = %q, want %q", tt.name, got, tt.credentials)
+		}
+	}
+
+	// 同源的请求不需要配置
+	srv := startWeb(t)
+	header := http.Header{"Origin": {srv.URL}}
+	if resp := call(t, srv, "/routeguide.RouteGuide/GetFeature", body, false, header); resp.status != http.StatusOK || resp.header.Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("same origin: status = %d, headers %v, want 200 with credentials", resp.status, resp.header)
+	}
+
+	// 预检请求同样检查 Origin
+	for origin, want := range map[string]int{"https://evil.example": http.StatusForbidden, srv.URL: http.StatusNoContent} {
+		req, _ := http.NewRequest(http.MethodOptions, srv.URL+"/routeguide.RouteGuide/GetFeature", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Headers", "x-grpc-web")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("OPTIONS from %s = %d, want %d", origin, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	for v, ok := range map[string]bool{"100m": true, "5S": true, "1H": true, "": false, "5": false, "5x": false, "xS": false} {
+		if _, got := parseTimeout(v); got != ok {
+			t.Errorf("parseTimeout(%q) ok = %v, want %v", v, got, ok)
+		}
+	}
+}
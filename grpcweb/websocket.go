@@ -0,0 +1,213 @@
+package grpcweb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// WebSocket 关闭码
+const (
+	closeNormal         = 1000
+	closeInvalidPayload = 1007
+	// closeStatusBase 加上 gRPC 状态码就是对应的关闭码，客户端可以用 code - 4000 还原出 gRPC 状态码
+	closeStatusBase = 4000
+)
+
+// CloseCode 返回 gRPC 状态码对应的 WebSocket 关闭码
+func CloseCode(code codes.Code) int {
+	if code == codes.OK {
+		return closeNormal
+	}
+	return closeStatusBase + int(code)
+}
+
+// WebSocketHandler 把 WebSocket 连接桥接到 gRPC 流上
+//
+// 连接的路径是 PathPrefix 加上方法全名，例如 /ws/routeguide.RouteGuide/RouteChat。
+// 客户端发送的每个文本帧是一条 JSON 格式的请求消息，发送空的文本帧表示客户端不再发送 (half-close)；
+// 服务端返回的每条消息同样是一个 JSON 文本帧。调用结束时，如果出错会先发送一个
+// {"error": {"code": ..., "message": ...}} 帧，然后以 CloseCode 对应的关闭码关闭连接
+type WebSocketHandler struct {
+	conn    grpc.ClientConnInterface
+	methods map[string]methodInfo
+	opts    options
+}
+
+// PathPrefix 是 WebSocket 桥接的路径前缀
+const PathPrefix = "/ws"
+
+// NewWebSocketHandler 创建 WebSocket 桥接，services 和 opts 的含义和 NewHandler 相同。
+// WebSocket 不受 CORS 的限制，所以 WithAllowedOrigins 中的 "*" 在这里同样允许所有的 Origin
+func NewWebSocketHandler(conn grpc.ClientConnInterface, services map[string]grpc.ServiceInfo, opts ...Option) *WebSocketHandler {
+	return &WebSocketHandler{conn: conn, methods: methodsOf(services), opts: newOptions(opts)}
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 浏览器允许任何网页打开跨域的 WebSocket，必须在握手之前检查 Origin
+	if allowed, _ := h.opts.originAllowed(r); !allowed {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	fullMethod := strings.TrimPrefix(r.URL.Path, PathPrefix)
+	method, ok := h.methods[fullMethod]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	md, err := methodDescriptor(fullMethod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s := websocket.Server{
+		// Origin 已经在上面检查过了，websocket 包默认的检查会拒绝没有 Origin 的非浏览器客户端
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			// JSON 格式的消息比 protobuf 大，这里留出一些余量
+			ws.MaxPayloadBytes = 2 * h.opts.maxMsgSize
+			b := &bridge{ws: ws, method: fullMethod, info: method, in: in, out: out}
+			b.run(h.conn, r)
+		},
+	}
+	s.ServeHTTP(w, r)
+}
+
+func methodDescriptor(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	name := strings.Replace(strings.TrimPrefix(fullMethod, "/"), "/", ".", 1)
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s is not a method", name)
+	}
+	return md, nil
+}
+
+// bridge 处理一个 WebSocket 连接对应的 gRPC 流
+type bridge struct {
+	ws      *websocket.Conn
+	method  string
+	info    methodInfo
+	in, out protoreflect.MessageType
+}
+
+func (b *bridge) run(conn grpc.ClientConnInterface, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", id)
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{
+		ClientStreams: b.info.clientStreams,
+		ServerStreams: b.info.serverStreams,
+	}, b.method)
+	if err != nil {
+		b.close(status.Convert(err))
+		return
+	}
+
+	// badInput 记录客户端发来的无法解析的消息，此时以 1007 关闭连接
+	badInput := make(chan error, 1)
+	go func() {
+		for {
+			var frame string
+			if err := websocket.Message.Receive(b.ws, &frame); err != nil {
+				// 客户端断开了连接，取消对应的 gRPC 流
+				cancel()
+				return
+			}
+			if frame == "" {
+				stream.CloseSend()
+				return
+			}
+			req := b.in.New().Interface()
+			if err := protojson.Unmarshal([]byte(frame), req); err != nil {
+				// 结束发送，让服务端已经产生的响应仍然可以发给客户端
+				badInput <- err
+				stream.CloseSend()
+				return
+			}
+			if err := stream.SendMsg(req); err != nil {
+				// 发送失败时，真正的错误会由 RecvMsg 返回
+				return
+			}
+		}
+	}()
+
+	for {
+		resp := b.out.New().Interface()
+		err := stream.RecvMsg(resp)
+		if err != nil {
+			select {
+			case e := <-badInput:
+				b.sendError(status.New(codes.InvalidArgument, e.Error()))
+				b.ws.WriteClose(closeInvalidPayload)
+			default:
+				if err == io.EOF {
+					err = nil
+				}
+				b.close(status.Convert(err))
+			}
+			return
+		}
+		if err := b.send(resp); err != nil {
+			return
+		}
+		if !b.info.serverStreams {
+			b.close(status.Convert(nil))
+			return
+		}
+	}
+}
+
+func (b *bridge) send(m proto.Message) error {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return websocket.Message.Send(b.ws, string(data))
+}
+
+func (b *bridge) sendError(s *status.Status) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    s.Code().String(),
+			"message": s.Message(),
+		},
+	})
+	websocket.Message.Send(b.ws, string(data))
+}
+
+func (b *bridge) close(s *status.Status) {
+	if s.Code() != codes.OK {
+		b.sendError(s)
+	}
+	b.ws.WriteClose(CloseCode(s.Code()))
+}
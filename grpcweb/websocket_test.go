@@ -0,0 +1,94 @@
+package grpcweb
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+// dialWS 连接到 srv 上 method 的 WebSocket 桥接
+func dialWS(t *testing.T, srvURL, method, origin string) (*websocket.Conn, error) {
+	t.Helper()
+	return websocket.Dial("ws"+strings.TrimPrefix(srvURL, "http")+PathPrefix+method, "", origin)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	var frame string
+	if err := websocket.Message.Receive(ws, &frame); err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(frame), &m); err != nil {
+		t.Fatalf("invalid frame %q: %v", frame, err)
+	}
+	return m
+}
+
+func TestWebSocketRouteChat(t *testing.T) {
+	srv := startWeb(t)
+	ws, err := dialWS(t, srv.URL, "/routeguide.RouteGuide/RouteChat", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	note := `{"location":{"latitude":1,"longitude":2},"message":"hi"}`
+	if err := websocket.Message.Send(ws, note); err != nil {
+		t.Fatal(err)
+	}
+	if m := receive(t, ws); m["message"] != "hi" {
+		t.Errorf("RouteChat reply = %v, want hi", m)
+	}
+
+	// 无法解析的消息返回 InvalidArgument
+	if err := websocket.Message.Send(ws, `{"location":`); err != nil {
+		t.Fatal(err)
+	}
+	m := receive(t, ws)
+	if e, _ := m["error"].(map[string]interface{}); e["code"] != "InvalidArgument" {
+		t.Errorf("reply to invalid JSON = %v, want InvalidArgument", m)
+	}
+}
+
+func TestWebSocketOrigins(t *testing.T) {
+	srv := startWeb(t, WithAllowedOrigins("https://app.example"))
+	for origin, ok := range map[string]bool{
+		srv.URL:                true,
+		"https://app.example":  true,
+		"https://evil.example": false,
+	} {
+		ws, err := dialWS(t, srv.URL, "/routeguide.Echo/Conversations", origin)
+		if ok != (err == nil) {
+			t.Errorf("Dial() from %s = %v, want ok %v", origin, err, ok)
+		}
+		if err == nil {
+			ws.Close()
+		}
+	}
+
+	// 不允许的 Origin 在握手之前就被拒绝
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+PathPrefix+"/routeguide.Echo/Conversations", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET from a disallowed origin = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestWebSocketServices(t *testing.T) {
+	srv := startWeb(t)
+	for _, method := range []string{"/routeguide.FaultInjection/SetFaults", "/grpc.health.v1.Health/Watch", "/routeguide.RouteGuide/Nope"} {
+		if ws, err := dialWS(t, srv.URL, method, srv.URL); err == nil {
+			ws.Close()
+			t.Errorf("Dial(%s) = nil, want an error", method)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.2
+// source: pb/fault.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FaultInjectionClient is the client API for FaultInjection service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FaultInjectionClient interface {
+	GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*Faults, error)
+	// SetFaults 替换所有规则，规则为空时不再注入故障
+	SetFaults(ctx context.Context, in *Faults, opts ...grpc.CallOption) (*Faults, error)
+}
+
+type faultInjectionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFaultInjectionClient(cc grpc.ClientConnInterface) FaultInjectionClient {
+	return &faultInjectionClient{cc}
+}
+
+func (c *faultInjectionClient) GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*Faults, error) {
+	out := new(Faults)
+	err := c.cc.Invoke(ctx, "/routeguide.FaultInjection/GetFaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *faultInjectionClient) SetFaults(ctx context.Context, in *Faults, opts ...grpc.CallOption) (*Faults, error) {
+	out := new(Faults)
+	err := c.cc.Invoke(ctx, "/routeguide.FaultInjection/SetFaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FaultInjectionServer is the server API for FaultInjection service.
+// All implementations must embed UnimplementedFaultInjectionServer
+// for forward compatibility
+type FaultInjectionServer interface {
+	GetFaults(context.Context, *GetFaultsRequest) (*Faults, error)
+	// SetFaults 替换所有规则，规则为空时不再注入故障
+	SetFaults(context.Context, *Faults) (*Faults, error)
+	mustEmbedUnimplementedFaultInjectionServer()
+}
+
+// UnimplementedFaultInjectionServer must be embedded to have forward compatible implementations.
+type UnimplementedFaultInjectionServer struct {
+}
+
+func (UnimplementedFaultInjectionServer) GetFaults(context.Context, *GetFaultsRequest) (*Faults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaults not implemented")
+}
+func (UnimplementedFaultInjectionServer) SetFaults(context.Context, *Faults) (*Faults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaults not implemented")
+}
+func (UnimplementedFaultInjectionServer) mustEmbedUnimplementedFaultInjectionServer() {}
+
+// UnsafeFaultInjectionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FaultInjectionServer will
+// result in compilation errors.
+type UnsafeFaultInjectionServer interface {
+	mustEmbedUnimplementedFaultInjectionServer()
+}
+
+func RegisterFaultInjectionServer(s grpc.ServiceRegistrar, srv FaultInjectionServer) {
+	s.RegisterService(&FaultInjection_ServiceDesc, srv)
+}
+
+func _FaultInjection_GetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FaultInjectionServer).GetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routeguide.FaultInjection/GetFaults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FaultInjectionServer).GetFaults(ctx, req.(*GetFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FaultInjection_SetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Faults)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FaultInjectionServer).SetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routeguide.FaultInjection/SetFaults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FaultInjectionServer).SetFaults(ctx, req.(*Faults))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FaultInjection_ServiceDesc is the grpc.ServiceDesc for FaultInjection service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FaultInjection_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "routeguide.FaultInjection",
+	HandlerType: (*FaultInjectionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFaults",
+			Handler:    _FaultInjection_GetFaults_Handler,
+		},
+		{
+			MethodName: "SetFaults",
+			Handler:    _FaultInjection_SetFaults_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pb/fault.proto",
+}
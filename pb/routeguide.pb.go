@@ -7,8 +7,12 @@
 package pb
 
 import (
+	_ "gRPCDemo/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,6 +24,163 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ListFeaturesRequest_Order int32
+
+const (
+	// 特征数据库中的顺序
+	ListFeaturesRequest_ORDER_UNSPECIFIED ListFeaturesRequest_Order = 0
+	// 按名字排序，没有名字的特征排在最后
+	ListFeaturesRequest_NAME ListFeaturesRequest_Order = 1
+	// 按到 origin 的距离排序，由近到远
+	ListFeaturesRequest_DISTANCE ListFeaturesRequest_Order = 2
+)
+
+// Enum value maps for ListFeaturesRequest_Order.
+var (
+	ListFeaturesRequest_Order_name = map[int32]string{
+		0: "ORDER_UNSPECIFIED",
+		1: "NAME",
+		2: "DISTANCE",
+	}
+	ListFeaturesRequest_Order_value = map[string]int32{
+		"ORDER_UNSPECIFIED": 0,
+		"NAME":              1,
+		"DISTANCE":          2,
+	}
+)
+
+func (x ListFeaturesRequest_Order) Enum() *ListFeaturesRequest_Order {
+	p := new(ListFeaturesRequest_Order)
+	*p = x
+	return p
+}
+
+func (x ListFeaturesRequest_Order) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListFeaturesRequest_Order) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_routeguide_proto_enumTypes[0].Descriptor()
+}
+
+func (ListFeaturesRequest_Order) Type() protoreflect.EnumType {
+	return &file_pb_routeguide_proto_enumTypes[0]
+}
+
+func (x ListFeaturesRequest_Order) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListFeaturesRequest_Order.Descriptor instead.
+func (ListFeaturesRequest_Order) EnumDescriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type GeofenceEvent_Type int32
+
+const (
+	GeofenceEvent_TYPE_UNSPECIFIED GeofenceEvent_Type = 0
+	GeofenceEvent_ENTER            GeofenceEvent_Type = 1
+	GeofenceEvent_EXIT             GeofenceEvent_Type = 2
+	GeofenceEvent_DWELL            GeofenceEvent_Type = 3
+)
+
+// Enum value maps for GeofenceEvent_Type.
+var (
+	GeofenceEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "ENTER",
+		2: "EXIT",
+		3: "DWELL",
+	}
+	GeofenceEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"ENTER":            1,
+		"EXIT":             2,
+		"DWELL":            3,
+	}
+)
+
+func (x GeofenceEvent_Type) Enum() *GeofenceEvent_Type {
+	p := new(GeofenceEvent_Type)
+	*p = x
+	return p
+}
+
+func (x GeofenceEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GeofenceEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_routeguide_proto_enumTypes[1].Descriptor()
+}
+
+func (GeofenceEvent_Type) Type() protoreflect.EnumType {
+	return &file_pb_routeguide_proto_enumTypes[1]
+}
+
+func (x GeofenceEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GeofenceEvent_Type.Descriptor instead.
+func (GeofenceEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{15, 0}
+}
+
+type RoomEvent_Type int32
+
+const (
+	RoomEvent_TYPE_UNSPECIFIED RoomEvent_Type = 0
+	RoomEvent_JOIN             RoomEvent_Type = 1
+	RoomEvent_LEAVE            RoomEvent_Type = 2
+	RoomEvent_MESSAGE          RoomEvent_Type = 3
+)
+
+// Enum value maps for RoomEvent_Type.
+var (
+	RoomEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "JOIN",
+		2: "LEAVE",
+		3: "MESSAGE",
+	}
+	RoomEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"JOIN":             1,
+		"LEAVE":            2,
+		"MESSAGE":          3,
+	}
+)
+
+func (x RoomEvent_Type) Enum() *RoomEvent_Type {
+	p := new(RoomEvent_Type)
+	*p = x
+	return p
+}
+
+func (x RoomEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoomEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_routeguide_proto_enumTypes[2].Descriptor()
+}
+
+func (RoomEvent_Type) Type() protoreflect.EnumType {
+	return &file_pb_routeguide_proto_enumTypes[2]
+}
+
+func (x RoomEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoomEvent_Type.Descriptor instead.
+func (RoomEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{21, 0}
+}
+
+// 经纬度使用 E7 表示，即度数乘以 10^7
 type Point struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -130,17 +291,33 @@ func (x *Rectangle) GetHi() *Point {
 	return nil
 }
 
-type Feature struct {
+// ListFeaturesRequest 是 QueryFeatures 和 ListFeaturesPage 的请求，
+// ListFeatures 仍然使用 Rectangle，已有的客户端不受影响
+type ListFeaturesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Location *Point `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	// area 是查询的范围，为空时不限制范围
+	//
+	// Types that are assignable to Area:
+	//	*ListFeaturesRequest_Rect
+	//	*ListFeaturesRequest_Polygon
+	//	*ListFeaturesRequest_Circle
+	//	*ListFeaturesRequest_Corridor
+	Area isListFeaturesRequest_Area `protobuf_oneof:"area"`
+	// page_size 为 0 时 ListFeaturesPage 返回 100 个，QueryFeatures 返回所有剩下的特征，
+	// 超过 1000 时按 1000 处理
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token 是上一页返回的 next_page_token，除了 page_size 之外的字段必须和上一页相同
+	PageToken string                    `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	OrderBy   ListFeaturesRequest_Order `protobuf:"varint,4,opt,name=order_by,json=orderBy,proto3,enum=routeguide.ListFeaturesRequest_Order" json:"order_by,omitempty"`
+	// origin 是 DISTANCE 排序的起点，这时必须设置
+	Origin *Point `protobuf:"bytes,5,opt,name=origin,proto3" json:"origin,omitempty"`
 }
 
-func (x *Feature) Reset() {
-	*x = Feature{}
+func (x *ListFeaturesRequest) Reset() {
+	*x = ListFeaturesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_routeguide_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -148,13 +325,13 @@ func (x *Feature) Reset() {
 	}
 }
 
-func (x *Feature) String() string {
+func (x *ListFeaturesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Feature) ProtoMessage() {}
+func (*ListFeaturesRequest) ProtoMessage() {}
 
-func (x *Feature) ProtoReflect() protoreflect.Message {
+func (x *ListFeaturesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_routeguide_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -166,36 +343,116 @@ func (x *Feature) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Feature.ProtoReflect.Descriptor instead.
-func (*Feature) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListFeaturesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeaturesRequest) Descriptor() ([]byte, []int) {
 	return file_pb_routeguide_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Feature) GetName() string {
+func (m *ListFeaturesRequest) GetArea() isListFeaturesRequest_Area {
+	if m != nil {
+		return m.Area
+	}
+	return nil
+}
+
+func (x *ListFeaturesRequest) GetRect() *Rectangle {
+	if x, ok := x.GetArea().(*ListFeaturesRequest_Rect); ok {
+		return x.Rect
+	}
+	return nil
+}
+
+func (x *ListFeaturesRequest) GetPolygon() *Polygon {
+	if x, ok := x.GetArea().(*ListFeaturesRequest_Polygon); ok {
+		return x.Polygon
+	}
+	return nil
+}
+
+func (x *ListFeaturesRequest) GetCircle() *Circle {
+	if x, ok := x.GetArea().(*ListFeaturesRequest_Circle); ok {
+		return x.Circle
+	}
+	return nil
+}
+
+func (x *ListFeaturesRequest) GetCorridor() *Corridor {
+	if x, ok := x.GetArea().(*ListFeaturesRequest_Corridor); ok {
+		return x.Corridor
+	}
+	return nil
+}
+
+func (x *ListFeaturesRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Name
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListFeaturesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
 	}
 	return ""
 }
 
-func (x *Feature) GetLocation() *Point {
+func (x *ListFeaturesRequest) GetOrderBy() ListFeaturesRequest_Order {
 	if x != nil {
-		return x.Location
+		return x.OrderBy
+	}
+	return ListFeaturesRequest_ORDER_UNSPECIFIED
+}
+
+func (x *ListFeaturesRequest) GetOrigin() *Point {
+	if x != nil {
+		return x.Origin
 	}
 	return nil
 }
 
-type RouteNode struct {
+type isListFeaturesRequest_Area interface {
+	isListFeaturesRequest_Area()
+}
+
+type ListFeaturesRequest_Rect struct {
+	Rect *Rectangle `protobuf:"bytes,1,opt,name=rect,proto3,oneof"`
+}
+
+type ListFeaturesRequest_Polygon struct {
+	Polygon *Polygon `protobuf:"bytes,6,opt,name=polygon,proto3,oneof"`
+}
+
+type ListFeaturesRequest_Circle struct {
+	Circle *Circle `protobuf:"bytes,7,opt,name=circle,proto3,oneof"`
+}
+
+type ListFeaturesRequest_Corridor struct {
+	Corridor *Corridor `protobuf:"bytes,8,opt,name=corridor,proto3,oneof"`
+}
+
+func (*ListFeaturesRequest_Rect) isListFeaturesRequest_Area() {}
+
+func (*ListFeaturesRequest_Polygon) isListFeaturesRequest_Area() {}
+
+func (*ListFeaturesRequest_Circle) isListFeaturesRequest_Area() {}
+
+func (*ListFeaturesRequest_Corridor) isListFeaturesRequest_Area() {}
+
+type ListFeaturesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Location *Point `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
-	Message  string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Features []*Feature `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+	// next_page_token 为空表示没有更多的特征
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// total_size 是请求匹配的特征总数，特征数据库在翻页期间被替换时只能作为参考
+	TotalSize int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 }
 
-func (x *RouteNode) Reset() {
-	*x = RouteNode{}
+func (x *ListFeaturesResponse) Reset() {
+	*x = ListFeaturesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_routeguide_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -203,13 +460,13 @@ func (x *RouteNode) Reset() {
 	}
 }
 
-func (x *RouteNode) String() string {
+func (x *ListFeaturesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RouteNode) ProtoMessage() {}
+func (*ListFeaturesResponse) ProtoMessage() {}
 
-func (x *RouteNode) ProtoReflect() protoreflect.Message {
+func (x *ListFeaturesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_routeguide_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -221,38 +478,43 @@ func (x *RouteNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteNode.ProtoReflect.Descriptor instead.
-func (*RouteNode) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListFeaturesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeaturesResponse) Descriptor() ([]byte, []int) {
 	return file_pb_routeguide_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RouteNode) GetLocation() *Point {
+func (x *ListFeaturesResponse) GetFeatures() []*Feature {
 	if x != nil {
-		return x.Location
+		return x.Features
 	}
 	return nil
 }
 
-func (x *RouteNode) GetMessage() string {
+func (x *ListFeaturesResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Message
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type RouteSummary struct {
+func (x *ListFeaturesResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+// LinearRing 是多边形的一个环，和 GeoJSON 一样最后一个点可以和第一个点相同，也可以省略
+type LinearRing struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PointCount   int32 `protobuf:"varint,1,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
-	FeatureCount int32 `protobuf:"varint,2,opt,name=feature_count,json=featureCount,proto3" json:"feature_count,omitempty"`
-	Distance     int32 `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
-	ElapsedTime  int32 `protobuf:"varint,4,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	Points []*Point `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
 }
 
-func (x *RouteSummary) Reset() {
-	*x = RouteSummary{}
+func (x *LinearRing) Reset() {
+	*x = LinearRing{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_routeguide_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -260,13 +522,13 @@ func (x *RouteSummary) Reset() {
 	}
 }
 
-func (x *RouteSummary) String() string {
+func (x *LinearRing) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RouteSummary) ProtoMessage() {}
+func (*LinearRing) ProtoMessage() {}
 
-func (x *RouteSummary) ProtoReflect() protoreflect.Message {
+func (x *LinearRing) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_routeguide_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -278,49 +540,32 @@ func (x *RouteSummary) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteSummary.ProtoReflect.Descriptor instead.
-func (*RouteSummary) Descriptor() ([]byte, []int) {
+// Deprecated: Use LinearRing.ProtoReflect.Descriptor instead.
+func (*LinearRing) Descriptor() ([]byte, []int) {
 	return file_pb_routeguide_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RouteSummary) GetPointCount() int32 {
-	if x != nil {
-		return x.PointCount
-	}
-	return 0
-}
-
-func (x *RouteSummary) GetFeatureCount() int32 {
-	if x != nil {
-		return x.FeatureCount
-	}
-	return 0
-}
-
-func (x *RouteSummary) GetDistance() int32 {
-	if x != nil {
-		return x.Distance
-	}
-	return 0
-}
-
-func (x *RouteSummary) GetElapsedTime() int32 {
+func (x *LinearRing) GetPoints() []*Point {
 	if x != nil {
-		return x.ElapsedTime
+		return x.Points
 	}
-	return 0
+	return nil
 }
 
-type StreamRequest struct {
+// Polygon 对应 GeoJSON 的 Polygon，边是经纬度平面上的直线，环的方向没有要求。
+// 边界上的点属于多边形，跨越 180° 经线的多边形不被支持
+type Polygon struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Question string `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	Exterior *LinearRing `protobuf:"bytes,1,opt,name=exterior,proto3" json:"exterior,omitempty"`
+	// holes 是多边形中挖去的部分，洞的边界仍然属于多边形
+	Holes []*LinearRing `protobuf:"bytes,2,rep,name=holes,proto3" json:"holes,omitempty"`
 }
 
-func (x *StreamRequest) Reset() {
-	*x = StreamRequest{}
+func (x *Polygon) Reset() {
+	*x = Polygon{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_routeguide_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -328,13 +573,13 @@ func (x *StreamRequest) Reset() {
 	}
 }
 
-func (x *StreamRequest) String() string {
+func (x *Polygon) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamRequest) ProtoMessage() {}
+func (*Polygon) ProtoMessage() {}
 
-func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+func (x *Polygon) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_routeguide_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -346,28 +591,37 @@ func (x *StreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
-func (*StreamRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Polygon.ProtoReflect.Descriptor instead.
+func (*Polygon) Descriptor() ([]byte, []int) {
 	return file_pb_routeguide_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *StreamRequest) GetQuestion() string {
+func (x *Polygon) GetExterior() *LinearRing {
 	if x != nil {
-		return x.Question
+		return x.Exterior
 	}
-	return ""
+	return nil
 }
 
-type StreamResponse struct {
+func (x *Polygon) GetHoles() []*LinearRing {
+	if x != nil {
+		return x.Holes
+	}
+	return nil
+}
+
+// Circle 是地球表面上到 center 的大圆距离不超过 radius_meters 的范围
+type Circle struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Answer string `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Center       *Point  `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	RadiusMeters float64 `protobuf:"fixed64,2,opt,name=radius_meters,json=radiusMeters,proto3" json:"radius_meters,omitempty"`
 }
 
-func (x *StreamResponse) Reset() {
-	*x = StreamResponse{}
+func (x *Circle) Reset() {
+	*x = Circle{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_routeguide_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -375,13 +629,13 @@ func (x *StreamResponse) Reset() {
 	}
 }
 
-func (x *StreamResponse) String() string {
+func (x *Circle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamResponse) ProtoMessage() {}
+func (*Circle) ProtoMessage() {}
 
-func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+func (x *Circle) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_routeguide_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -393,134 +647,1564 @@ func (x *StreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
-func (*StreamResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
+func (*Circle) Descriptor() ([]byte, []int) {
 	return file_pb_routeguide_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *StreamResponse) GetAnswer() string {
+func (x *Circle) GetCenter() *Point {
 	if x != nil {
-		return x.Answer
+		return x.Center
 	}
-	return ""
+	return nil
 }
 
-var File_pb_routeguide_proto protoreflect.FileDescriptor
+func (x *Circle) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
 
-var file_pb_routeguide_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x70, 0x62, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64,
-	0x65, 0x22, 0x41, 0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61,
-	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x61,
-	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74,
-	0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69,
-	0x74, 0x75, 0x64, 0x65, 0x22, 0x51, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c,
-	0x65, 0x12, 0x21, 0x0a, 0x02, 0x6c, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74,
-	0x52, 0x02, 0x6c, 0x6f, 0x12, 0x21, 0x0a, 0x02, 0x68, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f,
-	0x69, 0x6e, 0x74, 0x52, 0x02, 0x68, 0x69, 0x22, 0x4c, 0x0a, 0x07, 0x46, 0x65, 0x61, 0x74, 0x75,
-	0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6c, 0x6f, 0x63,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x6f,
-	0x64, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64,
-	0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x93, 0x01, 0x0a, 0x0c,
-	0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1f, 0x0a, 0x0b,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x0a, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x23, 0x0a,
-	0x0d, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x21,
-	0x0a, 0x0c, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d,
-	0x65, 0x22, 0x2b, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x28,
-	0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x32, 0x85, 0x02, 0x0a, 0x0a, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x47, 0x75, 0x69, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x65,
-	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
-	0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x00, 0x12,
-	0x3e, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12,
-	0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x63,
-	0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x1a, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75,
-	0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
-	0x3e, 0x0a, 0x0b, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x11,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e,
-	0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12,
-	0x3f, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x12, 0x15, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e,
-	0x6f, 0x64, 0x65, 0x1a, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
-	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01,
-	0x32, 0x54, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x4c, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x76,
-	0x65, 0x72, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64,
-	0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x0d, 0x5a, 0x0b, 0x67, 0x52, 0x50, 0x43, 0x44, 0x65,
-	0x6d, 0x6f, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+// Corridor 是到折线 path 的距离不超过 buffer_meters 的范围，折线的每一段是大圆上的劣弧
+type Corridor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path         []*Point `protobuf:"bytes,1,rep,name=path,proto3" json:"path,omitempty"`
+	BufferMeters float64  `protobuf:"fixed64,2,opt,name=buffer_meters,json=bufferMeters,proto3" json:"buffer_meters,omitempty"`
 }
 
-var (
-	file_pb_routeguide_proto_rawDescOnce sync.Once
-	file_pb_routeguide_proto_rawDescData = file_pb_routeguide_proto_rawDesc
-)
+func (x *Corridor) Reset() {
+	*x = Corridor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_pb_routeguide_proto_rawDescGZIP() []byte {
-	file_pb_routeguide_proto_rawDescOnce.Do(func() {
-		file_pb_routeguide_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_routeguide_proto_rawDescData)
-	})
-	return file_pb_routeguide_proto_rawDescData
+func (x *Corridor) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_pb_routeguide_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_pb_routeguide_proto_goTypes = []interface{}{
-	(*Point)(nil),          // 0: routeguide.Point
-	(*Rectangle)(nil),      // 1: routeguide.Rectangle
-	(*Feature)(nil),        // 2: routeguide.Feature
-	(*RouteNode)(nil),      // 3: routeguide.RouteNode
-	(*RouteSummary)(nil),   // 4: routeguide.RouteSummary
-	(*StreamRequest)(nil),  // 5: routeguide.StreamRequest
-	(*StreamResponse)(nil), // 6: routeguide.StreamResponse
+func (*Corridor) ProtoMessage() {}
+
+func (x *Corridor) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_pb_routeguide_proto_depIdxs = []int32{
-	0, // 0: routeguide.Rectangle.lo:type_name -> routeguide.Point
-	0, // 1: routeguide.Rectangle.hi:type_name -> routeguide.Point
-	0, // 2: routeguide.Feature.location:type_name -> routeguide.Point
-	0, // 3: routeguide.RouteNode.location:type_name -> routeguide.Point
-	0, // 4: routeguide.RouteGuide.GetFeature:input_type -> routeguide.Point
-	1, // 5: routeguide.RouteGuide.ListFeatures:input_type -> routeguide.Rectangle
-	0, // 6: routeguide.RouteGuide.RecordRoute:input_type -> routeguide.Point
-	3, // 7: routeguide.RouteGuide.RouteChat:input_type -> routeguide.RouteNode
-	5, // 8: routeguide.Echo.Conversations:input_type -> routeguide.StreamRequest
-	2, // 9: routeguide.RouteGuide.GetFeature:output_type -> routeguide.Feature
-	2, // 10: routeguide.RouteGuide.ListFeatures:output_type -> routeguide.Feature
-	4, // 11: routeguide.RouteGuide.RecordRoute:output_type -> routeguide.RouteSummary
-	3, // 12: routeguide.RouteGuide.RouteChat:output_type -> routeguide.RouteNode
-	6, // 13: routeguide.Echo.Conversations:output_type -> routeguide.StreamResponse
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+// Deprecated: Use Corridor.ProtoReflect.Descriptor instead.
+func (*Corridor) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{7}
 }
 
-func init() { file_pb_routeguide_proto_init() }
-func file_pb_routeguide_proto_init() {
-	if File_pb_routeguide_proto != nil {
-		return
+func (x *Corridor) GetPath() []*Point {
+	if x != nil {
+		return x.Path
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_pb_routeguide_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+	return nil
+}
+
+func (x *Corridor) GetBufferMeters() float64 {
+	if x != nil {
+		return x.BufferMeters
+	}
+	return 0
+}
+
+// SearchFeaturesRequest 按名字搜索特征，规则见 search 包
+type SearchFeaturesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// bias 不为空时离 bias 越近的特征排名越高
+	Bias *Point `protobuf:"bytes,2,opt,name=bias,proto3" json:"bias,omitempty"`
+	// bias_scale_meters 是距离的影响范围，默认是 10km
+	BiasScaleMeters float64 `protobuf:"fixed64,3,opt,name=bias_scale_meters,json=biasScaleMeters,proto3" json:"bias_scale_meters,omitempty"`
+	// limit 为 0 时返回 10 个结果，最多 100 个
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SearchFeaturesRequest) Reset() {
+	*x = SearchFeaturesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchFeaturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFeaturesRequest) ProtoMessage() {}
+
+func (x *SearchFeaturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFeaturesRequest.ProtoReflect.Descriptor instead.
+func (*SearchFeaturesRequest) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchFeaturesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchFeaturesRequest) GetBias() *Point {
+	if x != nil {
+		return x.Bias
+	}
+	return nil
+}
+
+func (x *SearchFeaturesRequest) GetBiasScaleMeters() float64 {
+	if x != nil {
+		return x.BiasScaleMeters
+	}
+	return 0
+}
+
+func (x *SearchFeaturesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchFeaturesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// results 按 score 从高到低排列
+	Results []*SearchFeaturesResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// completions 是补全了最后一个词的查询，用来做自动补全，query 以空格结尾时为空
+	Completions []string `protobuf:"bytes,2,rep,name=completions,proto3" json:"completions,omitempty"`
+}
+
+func (x *SearchFeaturesResponse) Reset() {
+	*x = SearchFeaturesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchFeaturesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFeaturesResponse) ProtoMessage() {}
+
+func (x *SearchFeaturesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFeaturesResponse.ProtoReflect.Descriptor instead.
+func (*SearchFeaturesResponse) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchFeaturesResponse) GetResults() []*SearchFeaturesResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchFeaturesResponse) GetCompletions() []string {
+	if x != nil {
+		return x.Completions
+	}
+	return nil
+}
+
+type ReverseGeocodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Point `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// radius_meters 是搜索的范围，默认是 1km
+	RadiusMeters float64 `protobuf:"fixed64,2,opt,name=radius_meters,json=radiusMeters,proto3" json:"radius_meters,omitempty"`
+}
+
+func (x *ReverseGeocodeRequest) Reset() {
+	*x = ReverseGeocodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReverseGeocodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseGeocodeRequest) ProtoMessage() {}
+
+func (x *ReverseGeocodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseGeocodeRequest.ProtoReflect.Descriptor instead.
+func (*ReverseGeocodeRequest) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReverseGeocodeRequest) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *ReverseGeocodeRequest) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+// Address 是从特征的名字中解析出来的地址，没有解析出来的部分为空，规则见 address 包
+type Address struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// street 是城市之前的部分，也可能是地标的名字
+	Street     string `protobuf:"bytes,1,opt,name=street,proto3" json:"street,omitempty"`
+	City       string `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	State      string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	PostalCode string `protobuf:"bytes,4,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country    string `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Address) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type ReverseGeocodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Feature *Feature `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	Address *Address `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// distance_meters 是 location 到特征的距离
+	DistanceMeters int32 `protobuf:"varint,3,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	// confidence 在 0 到 1 之间，特征越近、地址解析出来的部分越多越高
+	Confidence float64 `protobuf:"fixed64,4,opt,name=confidence,proto3" json:"confidence,omitempty"`
+}
+
+func (x *ReverseGeocodeResponse) Reset() {
+	*x = ReverseGeocodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReverseGeocodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseGeocodeResponse) ProtoMessage() {}
+
+func (x *ReverseGeocodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseGeocodeResponse.ProtoReflect.Descriptor instead.
+func (*ReverseGeocodeResponse) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReverseGeocodeResponse) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+func (x *ReverseGeocodeResponse) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ReverseGeocodeResponse) GetDistanceMeters() int32 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *ReverseGeocodeResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+// GeofenceOptions 配置一次 MonitorGeofences 调用，字段为 0 时使用默认值
+type GeofenceOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// radius_meters 是进入范围的距离，默认是 100m
+	RadiusMeters float64 `protobuf:"fixed64,1,opt,name=radius_meters,json=radiusMeters,proto3" json:"radius_meters,omitempty"`
+	// exit_radius_meters 是离开范围的距离，不能小于 radius_meters，默认是 radius_meters 的 1.2 倍。
+	// 两个距离之间的位置不会改变状态，这样位置在边界附近抖动时不会反复进入和离开
+	ExitRadiusMeters float64 `protobuf:"fixed64,2,opt,name=exit_radius_meters,json=exitRadiusMeters,proto3" json:"exit_radius_meters,omitempty"`
+	// dwell 是在范围内停留多久之后发送 DWELL 事件，默认是 1 分钟
+	Dwell *durationpb.Duration `protobuf:"bytes,3,opt,name=dwell,proto3" json:"dwell,omitempty"`
+}
+
+func (x *GeofenceOptions) Reset() {
+	*x = GeofenceOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeofenceOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofenceOptions) ProtoMessage() {}
+
+func (x *GeofenceOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofenceOptions.ProtoReflect.Descriptor instead.
+func (*GeofenceOptions) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GeofenceOptions) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+func (x *GeofenceOptions) GetExitRadiusMeters() float64 {
+	if x != nil {
+		return x.ExitRadiusMeters
+	}
+	return 0
+}
+
+func (x *GeofenceOptions) GetDwell() *durationpb.Duration {
+	if x != nil {
+		return x.Dwell
+	}
+	return nil
+}
+
+type GeofencePosition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Point `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// time 是定位的时间，为空时使用服务端收到位置的时间
+	Time *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// options 只在流中的第一条消息中生效
+	Options *GeofenceOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *GeofencePosition) Reset() {
+	*x = GeofencePosition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeofencePosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofencePosition) ProtoMessage() {}
+
+func (x *GeofencePosition) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofencePosition.ProtoReflect.Descriptor instead.
+func (*GeofencePosition) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GeofencePosition) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GeofencePosition) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *GeofencePosition) GetOptions() *GeofenceOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GeofenceEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    GeofenceEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=routeguide.GeofenceEvent_Type" json:"type,omitempty"`
+	Feature *Feature           `protobuf:"bytes,2,opt,name=feature,proto3" json:"feature,omitempty"`
+	// location 和 time 是触发事件的位置和它的时间
+	Location *Point                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Time     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// distance_meters 是 location 到特征的距离
+	DistanceMeters int32 `protobuf:"varint,5,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	// dwell 是到 time 为止在范围内停留的时间，ENTER 事件中为 0
+	Dwell *durationpb.Duration `protobuf:"bytes,6,opt,name=dwell,proto3" json:"dwell,omitempty"`
+}
+
+func (x *GeofenceEvent) Reset() {
+	*x = GeofenceEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeofenceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofenceEvent) ProtoMessage() {}
+
+func (x *GeofenceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofenceEvent.ProtoReflect.Descriptor instead.
+func (*GeofenceEvent) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GeofenceEvent) GetType() GeofenceEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return GeofenceEvent_TYPE_UNSPECIFIED
+}
+
+func (x *GeofenceEvent) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetDistanceMeters() int32 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *GeofenceEvent) GetDwell() *durationpb.Duration {
+	if x != nil {
+		return x.Dwell
+	}
+	return nil
+}
+
+type Feature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Location *Point `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+}
+
+func (x *Feature) Reset() {
+	*x = Feature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Feature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Feature) ProtoMessage() {}
+
+func (x *Feature) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Feature.ProtoReflect.Descriptor instead.
+func (*Feature) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Feature) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Feature) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type RouteNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Point `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Message  string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *RouteNode) Reset() {
+	*x = RouteNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouteNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteNode) ProtoMessage() {}
+
+func (x *RouteNode) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteNode.ProtoReflect.Descriptor instead.
+func (*RouteNode) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RouteNode) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *RouteNode) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RouteSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PointCount   int32 `protobuf:"varint,1,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
+	FeatureCount int32 `protobuf:"varint,2,opt,name=feature_count,json=featureCount,proto3" json:"feature_count,omitempty"`
+	Distance     int32 `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	ElapsedTime  int32 `protobuf:"varint,4,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+}
+
+func (x *RouteSummary) Reset() {
+	*x = RouteSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouteSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteSummary) ProtoMessage() {}
+
+func (x *RouteSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteSummary.ProtoReflect.Descriptor instead.
+func (*RouteSummary) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RouteSummary) GetPointCount() int32 {
+	if x != nil {
+		return x.PointCount
+	}
+	return 0
+}
+
+func (x *RouteSummary) GetFeatureCount() int32 {
+	if x != nil {
+		return x.FeatureCount
+	}
+	return 0
+}
+
+func (x *RouteSummary) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *RouteSummary) GetElapsedTime() int32 {
+	if x != nil {
+		return x.ElapsedTime
+	}
+	return 0
+}
+
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Question string `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	// room 不为空时加入这个房间，房间中每个问题都会广播给所有参与者。
+	// 只有流中第一条消息的 room 生效，也可以用 x-echo-room metadata 在发送问题之前加入
+	Room string `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+type StreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Answer string `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	// event 只在房间中设置
+	Event *RoomEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	// 下面的字段只在 x-echo-async 模式中设置，这时服务端不再是每收到一个问题回答一次
+	// reply_to 是回答对应的问题在流中的序号，从 1 开始
+	ReplyTo int64 `protobuf:"varint,3,opt,name=reply_to,json=replyTo,proto3" json:"reply_to,omitempty"`
+	// part 是同一个问题的第几个回答，从 1 开始，见 x-echo-answers
+	Part int32 `protobuf:"varint,4,opt,name=part,proto3" json:"part,omitempty"`
+	// heartbeat 不为空时这是服务端主动发送的心跳，没有回答
+	Heartbeat *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StreamResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *StreamResponse) GetEvent() *RoomEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetReplyTo() int64 {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return 0
+}
+
+func (x *StreamResponse) GetPart() int32 {
+	if x != nil {
+		return x.Part
+	}
+	return 0
+}
+
+func (x *StreamResponse) GetHeartbeat() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+type RoomEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        RoomEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=routeguide.RoomEvent_Type" json:"type,omitempty"`
+	Room        string         `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	Participant string         `protobuf:"bytes,3,opt,name=participant,proto3" json:"participant,omitempty"`
+	// question 是 MESSAGE 事件中参与者的问题
+	Question string `protobuf:"bytes,4,opt,name=question,proto3" json:"question,omitempty"`
+	// sequence 是事件在房间中的序号，从 1 开始
+	Sequence int64                  `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Time     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	// history 表示这是加入房间时重放的历史消息
+	History bool `protobuf:"varint,7,opt,name=history,proto3" json:"history,omitempty"`
+}
+
+func (x *RoomEvent) Reset() {
+	*x = RoomEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoomEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomEvent) ProtoMessage() {}
+
+func (x *RoomEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomEvent.ProtoReflect.Descriptor instead.
+func (*RoomEvent) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RoomEvent) GetType() RoomEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return RoomEvent_TYPE_UNSPECIFIED
+}
+
+func (x *RoomEvent) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RoomEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RoomEvent) GetHistory() bool {
+	if x != nil {
+		return x.History
+	}
+	return false
+}
+
+type SearchFeaturesResponse_Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Feature *Feature `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	// score 是相关性和距离综合之后的分数，只能用来比较同一次搜索的结果
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// distance_meters 是到 bias 的距离，没有设置 bias 时为 0
+	DistanceMeters int32 `protobuf:"varint,3,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+}
+
+func (x *SearchFeaturesResponse_Result) Reset() {
+	*x = SearchFeaturesResponse_Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_routeguide_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchFeaturesResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFeaturesResponse_Result) ProtoMessage() {}
+
+func (x *SearchFeaturesResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_routeguide_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFeaturesResponse_Result.ProtoReflect.Descriptor instead.
+func (*SearchFeaturesResponse_Result) Descriptor() ([]byte, []int) {
+	return file_pb_routeguide_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *SearchFeaturesResponse_Result) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+func (x *SearchFeaturesResponse_Result) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchFeaturesResponse_Result) GetDistanceMeters() int32 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+var File_pb_routeguide_proto protoreflect.FileDescriptor
+
+var file_pb_routeguide_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x62, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64,
+	0x65, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x73, 0x0a, 0x05, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x33, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x42, 0x17, 0xba, 0xfd, 0x18, 0x13, 0x1a, 0x11, 0x28, 0x80, 0xae, 0xec,
+	0xd2, 0xfc, 0xff, 0xff, 0xff, 0xff, 0x01, 0x18, 0x80, 0xd2, 0x93, 0xad, 0x03, 0x52, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x17, 0xba, 0xfd, 0x18, 0x13,
+	0x1a, 0x11, 0x28, 0x80, 0xdc, 0xd8, 0xa5, 0xf9, 0xff, 0xff, 0xff, 0xff, 0x01, 0x18, 0x80, 0xa4,
+	0xa7, 0xda, 0x06, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x63,
+	0x0a, 0x09, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x02, 0x6c,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67,
+	0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03,
+	0xc8, 0x01, 0x01, 0x52, 0x02, 0x6c, 0x6f, 0x12, 0x2a, 0x0a, 0x02, 0x68, 0x69, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03, 0xc8, 0x01, 0x01, 0x52,
+	0x02, 0x68, 0x69, 0x22, 0xde, 0x03, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x72,
+	0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65,
+	0x48, 0x00, 0x52, 0x04, 0x72, 0x65, 0x63, 0x74, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x6f, 0x6c, 0x79,
+	0x67, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x07, 0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x06, 0x63, 0x69, 0x72,
+	0x63, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x48, 0x00, 0x52,
+	0x06, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x63, 0x6f, 0x72, 0x72, 0x69,
+	0x64, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x72, 0x72, 0x69, 0x64, 0x6f, 0x72, 0x48,
+	0x00, 0x52, 0x08, 0x63, 0x6f, 0x72, 0x72, 0x69, 0x64, 0x6f, 0x72, 0x12, 0x25, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x08,
+	0xba, 0xfd, 0x18, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x28, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x09, 0xba, 0xfd, 0x18, 0x05, 0x72, 0x03, 0x18, 0x80,
+	0x02, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x4b, 0x0a, 0x08,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x25,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x09, 0xba, 0xfd, 0x18, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01,
+	0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x29, 0x0a, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x22, 0x36, 0x0a, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x15, 0x0a,
+	0x11, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x01, 0x12, 0x0c,
+	0x0a, 0x08, 0x44, 0x49, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x02, 0x42, 0x06, 0x0a, 0x04,
+	0x61, 0x72, 0x65, 0x61, 0x22, 0x8e, 0x01, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a,
+	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x26,
+	0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x45, 0x0a, 0x0a, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x72, 0x52,
+	0x69, 0x6e, 0x67, 0x12, 0x37, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0c, 0xba, 0xfd, 0x18, 0x08, 0x92, 0x01, 0x05, 0x08,
+	0x03, 0x10, 0x90, 0x4e, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x7f, 0x0a, 0x07,
+	0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72,
+	0x69, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x72, 0x52, 0x69, 0x6e,
+	0x67, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x08, 0x65, 0x78, 0x74, 0x65,
+	0x72, 0x69, 0x6f, 0x72, 0x12, 0x37, 0x0a, 0x05, 0x68, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x72, 0x52, 0x69, 0x6e, 0x67, 0x42, 0x09, 0xba, 0xfd, 0x18,
+	0x05, 0x92, 0x01, 0x02, 0x10, 0x64, 0x52, 0x05, 0x68, 0x6f, 0x6c, 0x65, 0x73, 0x22, 0x7b, 0x0a,
+	0x06, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67,
+	0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03,
+	0xc8, 0x01, 0x01, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0d, 0x72,
+	0x61, 0x64, 0x69, 0x75, 0x73, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18, 0x14, 0x12, 0x12, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00, 0x50, 0xf8, 0x1b, 0x73, 0x41, 0x52, 0x0c, 0x72, 0x61,
+	0x64, 0x69, 0x75, 0x73, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x22, 0x7e, 0x0a, 0x08, 0x43, 0x6f,
+	0x72, 0x72, 0x69, 0x64, 0x6f, 0x72, 0x12, 0x33, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64,
+	0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0c, 0xba, 0xfd, 0x18, 0x08, 0x92, 0x01, 0x05,
+	0x10, 0x90, 0x4e, 0x08, 0x01, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x3d, 0x0a, 0x0d, 0x62,
+	0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18, 0x14, 0x12, 0x12, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00, 0x00, 0x80, 0x84, 0x2e, 0x41, 0x52, 0x0c, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x22, 0xc9, 0x01, 0x0a, 0x15, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x0b, 0xba, 0xfd, 0x18, 0x07, 0x72, 0x05, 0x18, 0x80, 0x02, 0x10, 0x01,
+	0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x04, 0x62, 0x69, 0x61, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x04, 0x62, 0x69, 0x61, 0x73, 0x12, 0x44,
+	0x0a, 0x11, 0x62, 0x69, 0x61, 0x73, 0x5f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18, 0x14, 0x12,
+	0x12, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00, 0x50, 0xf8,
+	0x1b, 0x73, 0x41, 0x52, 0x0f, 0x62, 0x69, 0x61, 0x73, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x4d, 0x65,
+	0x74, 0x65, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x42, 0x0a, 0xba, 0xfd, 0x18, 0x06, 0x1a, 0x04, 0x28, 0x00, 0x18, 0x64, 0x52,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xf7, 0x01, 0x0a, 0x16, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x43, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x76, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x22, 0x8e, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x47, 0x65, 0x6f, 0x63,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42,
+	0x07, 0xba, 0xfd, 0x18, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x5f, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18, 0x14, 0x12,
+	0x12, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x6a, 0xf8, 0x40, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x52, 0x0c, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4d, 0x65, 0x74, 0x65, 0x72,
+	0x73, 0x22, 0x86, 0x01, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x72, 0x65, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x72, 0x65, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x22, 0xbf, 0x01, 0x0a, 0x16, 0x52,
+	0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x47, 0x65, 0x6f, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75,
+	0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x64, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x22, 0xc9, 0x01, 0x0a,
+	0x0f, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18, 0x14, 0x12, 0x12, 0x19,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x6a, 0xf8, 0x40, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x52, 0x0c, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12,
+	0x46, 0x0a, 0x12, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x5f, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x42, 0x18, 0xba, 0xfd, 0x18,
+	0x14, 0x12, 0x12, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x6a, 0x08, 0x41, 0x29, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x10, 0x65, 0x78, 0x69, 0x74, 0x52, 0x61, 0x64, 0x69, 0x75,
+	0x73, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x64, 0x77, 0x65, 0x6c, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x05, 0x64, 0x77, 0x65, 0x6c, 0x6c, 0x22, 0xb1, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x6f,
+	0x66, 0x65, 0x6e, 0x63, 0x65, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x08, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75,
+	0x69, 0x64, 0x65, 0x2e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xe9, 0x02, 0x0a,
+	0x0d, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x32,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e,
+	0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x2d, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x64, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x64, 0x77, 0x65,
+	0x6c, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x64, 0x77, 0x65, 0x6c, 0x6c, 0x22, 0x3c, 0x0a, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x4e, 0x54, 0x45,
+	0x52, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x58, 0x49, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a,
+	0x05, 0x44, 0x57, 0x45, 0x4c, 0x4c, 0x10, 0x03, 0x22, 0x4c, 0x0a, 0x07, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6a, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e,
+	0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x07, 0xba, 0xfd, 0x18, 0x03, 0xc8, 0x01,
+	0x01, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0b, 0xba, 0xfd,
+	0x18, 0x07, 0x72, 0x05, 0x10, 0x01, 0x18, 0x80, 0x08, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x93, 0x01, 0x0a, 0x0c, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x65, 0x6c, 0x61,
+	0x70, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x57, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x08, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0b, 0xba, 0xfd, 0x18,
+	0x07, 0x72, 0x05, 0x10, 0x01, 0x18, 0x80, 0x20, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x09, 0xba, 0xfd, 0x18, 0x05, 0x72, 0x03, 0x18, 0x80, 0x01, 0x52, 0x04, 0x72, 0x6f, 0x6f,
+	0x6d, 0x22, 0xbe, 0x01, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x05,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x70,
+	0x6c, 0x79, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x70,
+	0x6c, 0x79, 0x54, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x72, 0x74, 0x12, 0x38, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72,
+	0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
+	0x61, 0x74, 0x22, 0xb3, 0x02, 0x0a, 0x09, 0x52, 0x6f, 0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x6f, 0x6f, 0x6d,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x72, 0x6f, 0x6f, 0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x2e,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x3e, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x14, 0x0a, 0x10, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4a, 0x4f, 0x49, 0x4e, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x4c, 0x45, 0x41, 0x56, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x4d,
+	0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x03, 0x32, 0xcb, 0x06, 0x0a, 0x0a, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x47, 0x75, 0x69, 0x64, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x11, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x14, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x12, 0x57, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x12, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x1a, 0x13, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22,
+	0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x3a, 0x6c, 0x69, 0x73, 0x74, 0x30, 0x01, 0x12, 0x5a, 0x0a, 0x0b,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x11, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x18,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16,
+	0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x3a,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x28, 0x01, 0x12, 0x3f, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x43, 0x68, 0x61, 0x74, 0x12, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x1a, 0x15, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x4e,
+	0x6f, 0x64, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x0d, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x74, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75,
+	0x69, 0x64, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x3a, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x75, 0x0a, 0x0e, 0x52, 0x65,
+	0x76, 0x65, 0x72, 0x73, 0x65, 0x47, 0x65, 0x6f, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x47, 0x65, 0x6f, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x76,
+	0x65, 0x72, 0x73, 0x65, 0x47, 0x65, 0x6f, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x12, 0x14, 0x2f, 0x76, 0x31,
+	0x2f, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x3a, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x12, 0x51, 0x0a, 0x10, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x47, 0x65, 0x6f, 0x66,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69,
+	0x64, 0x65, 0x2e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x1a, 0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65,
+	0x2e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x70, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x3a, 0x70, 0x61, 0x67, 0x65, 0x32, 0x54, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x4c,
+	0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x67, 0x75, 0x69, 0x64, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x0d, 0x5a, 0x0b,
+	0x67, 0x52, 0x50, 0x43, 0x44, 0x65, 0x6d, 0x6f, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_routeguide_proto_rawDescOnce sync.Once
+	file_pb_routeguide_proto_rawDescData = file_pb_routeguide_proto_rawDesc
+)
+
+func file_pb_routeguide_proto_rawDescGZIP() []byte {
+	file_pb_routeguide_proto_rawDescOnce.Do(func() {
+		file_pb_routeguide_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_routeguide_proto_rawDescData)
+	})
+	return file_pb_routeguide_proto_rawDescData
+}
+
+var file_pb_routeguide_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pb_routeguide_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_pb_routeguide_proto_goTypes = []interface{}{
+	(ListFeaturesRequest_Order)(0),        // 0: routeguide.ListFeaturesRequest.Order
+	(GeofenceEvent_Type)(0),               // 1: routeguide.GeofenceEvent.Type
+	(RoomEvent_Type)(0),                   // 2: routeguide.RoomEvent.Type
+	(*Point)(nil),                         // 3: routeguide.Point
+	(*Rectangle)(nil),                     // 4: routeguide.Rectangle
+	(*ListFeaturesRequest)(nil),           // 5: routeguide.ListFeaturesRequest
+	(*ListFeaturesResponse)(nil),          // 6: routeguide.ListFeaturesResponse
+	(*LinearRing)(nil),                    // 7: routeguide.LinearRing
+	(*Polygon)(nil),                       // 8: routeguide.Polygon
+	(*Circle)(nil),                        // 9: routeguide.Circle
+	(*Corridor)(nil),                      // 10: routeguide.Corridor
+	(*SearchFeaturesRequest)(nil),         // 11: routeguide.SearchFeaturesRequest
+	(*SearchFeaturesResponse)(nil),        // 12: routeguide.SearchFeaturesResponse
+	(*ReverseGeocodeRequest)(nil),         // 13: routeguide.ReverseGeocodeRequest
+	(*Address)(nil),                       // 14: routeguide.Address
+	(*ReverseGeocodeResponse)(nil),        // 15: routeguide.ReverseGeocodeResponse
+	(*GeofenceOptions)(nil),               // 16: routeguide.GeofenceOptions
+	(*GeofencePosition)(nil),              // 17: routeguide.GeofencePosition
+	(*GeofenceEvent)(nil),                 // 18: routeguide.GeofenceEvent
+	(*Feature)(nil),                       // 19: routeguide.Feature
+	(*RouteNode)(nil),                     // 20: routeguide.RouteNode
+	(*RouteSummary)(nil),                  // 21: routeguide.RouteSummary
+	(*StreamRequest)(nil),                 // 22: routeguide.StreamRequest
+	(*StreamResponse)(nil),                // 23: routeguide.StreamResponse
+	(*RoomEvent)(nil),                     // 24: routeguide.RoomEvent
+	(*SearchFeaturesResponse_Result)(nil), // 25: routeguide.SearchFeaturesResponse.Result
+	(*durationpb.Duration)(nil),           // 26: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),         // 27: google.protobuf.Timestamp
+}
+var file_pb_routeguide_proto_depIdxs = []int32{
+	3,  // 0: routeguide.Rectangle.lo:type_name -> routeguide.Point
+	3,  // 1: routeguide.Rectangle.hi:type_name -> routeguide.Point
+	4,  // 2: routeguide.ListFeaturesRequest.rect:type_name -> routeguide.Rectangle
+	8,  // 3: routeguide.ListFeaturesRequest.polygon:type_name -> routeguide.Polygon
+	9,  // 4: routeguide.ListFeaturesRequest.circle:type_name -> routeguide.Circle
+	10, // 5: routeguide.ListFeaturesRequest.corridor:type_name -> routeguide.Corridor
+	0,  // 6: routeguide.ListFeaturesRequest.order_by:type_name -> routeguide.ListFeaturesRequest.Order
+	3,  // 7: routeguide.ListFeaturesRequest.origin:type_name -> routeguide.Point
+	19, // 8: routeguide.ListFeaturesResponse.features:type_name -> routeguide.Feature
+	3,  // 9: routeguide.LinearRing.points:type_name -> routeguide.Point
+	7,  // 10: routeguide.Polygon.exterior:type_name -> routeguide.LinearRing
+	7,  // 11: routeguide.Polygon.holes:type_name -> routeguide.LinearRing
+	3,  // 12: routeguide.Circle.center:type_name -> routeguide.Point
+	3,  // 13: routeguide.Corridor.path:type_name -> routeguide.Point
+	3,  // 14: routeguide.SearchFeaturesRequest.bias:type_name -> routeguide.Point
+	25, // 15: routeguide.SearchFeaturesResponse.results:type_name -> routeguide.SearchFeaturesResponse.Result
+	3,  // 16: routeguide.ReverseGeocodeRequest.location:type_name -> routeguide.Point
+	19, // 17: routeguide.ReverseGeocodeResponse.feature:type_name -> routeguide.Feature
+	14, // 18: routeguide.ReverseGeocodeResponse.address:type_name -> routeguide.Address
+	26, // 19: routeguide.GeofenceOptions.dwell:type_name -> google.protobuf.Duration
+	3,  // 20: routeguide.GeofencePosition.location:type_name -> routeguide.Point
+	27, // 21: routeguide.GeofencePosition.time:type_name -> google.protobuf.Timestamp
+	16, // 22: routeguide.GeofencePosition.options:type_name -> routeguide.GeofenceOptions
+	1,  // 23: routeguide.GeofenceEvent.type:type_name -> routeguide.GeofenceEvent.Type
+	19, // 24: routeguide.GeofenceEvent.feature:type_name -> routeguide.Feature
+	3,  // 25: routeguide.GeofenceEvent.location:type_name -> routeguide.Point
+	27, // 26: routeguide.GeofenceEvent.time:type_name -> google.protobuf.Timestamp
+	26, // 27: routeguide.GeofenceEvent.dwell:type_name -> google.protobuf.Duration
+	3,  // 28: routeguide.Feature.location:type_name -> routeguide.Point
+	3,  // 29: routeguide.RouteNode.location:type_name -> routeguide.Point
+	24, // 30: routeguide.StreamResponse.event:type_name -> routeguide.RoomEvent
+	27, // 31: routeguide.StreamResponse.heartbeat:type_name -> google.protobuf.Timestamp
+	2,  // 32: routeguide.RoomEvent.type:type_name -> routeguide.RoomEvent.Type
+	27, // 33: routeguide.RoomEvent.time:type_name -> google.protobuf.Timestamp
+	19, // 34: routeguide.SearchFeaturesResponse.Result.feature:type_name -> routeguide.Feature
+	3,  // 35: routeguide.RouteGuide.GetFeature:input_type -> routeguide.Point
+	4,  // 36: routeguide.RouteGuide.ListFeatures:input_type -> routeguide.Rectangle
+	3,  // 37: routeguide.RouteGuide.RecordRoute:input_type -> routeguide.Point
+	20, // 38: routeguide.RouteGuide.RouteChat:input_type -> routeguide.RouteNode
+	5,  // 39: routeguide.RouteGuide.QueryFeatures:input_type -> routeguide.ListFeaturesRequest
+	11, // 40: routeguide.RouteGuide.SearchFeatures:input_type -> routeguide.SearchFeaturesRequest
+	13, // 41: routeguide.RouteGuide.ReverseGeocode:input_type -> routeguide.ReverseGeocodeRequest
+	17, // 42: routeguide.RouteGuide.MonitorGeofences:input_type -> routeguide.GeofencePosition
+	5,  // 43: routeguide.RouteGuide.ListFeaturesPage:input_type -> routeguide.ListFeaturesRequest
+	22, // 44: routeguide.Echo.Conversations:input_type -> routeguide.StreamRequest
+	19, // 45: routeguide.RouteGuide.GetFeature:output_type -> routeguide.Feature
+	19, // 46: routeguide.RouteGuide.ListFeatures:output_type -> routeguide.Feature
+	21, // 47: routeguide.RouteGuide.RecordRoute:output_type -> routeguide.RouteSummary
+	20, // 48: routeguide.RouteGuide.RouteChat:output_type -> routeguide.RouteNode
+	19, // 49: routeguide.RouteGuide.QueryFeatures:output_type -> routeguide.Feature
+	12, // 50: routeguide.RouteGuide.SearchFeatures:output_type -> routeguide.SearchFeaturesResponse
+	15, // 51: routeguide.RouteGuide.ReverseGeocode:output_type -> routeguide.ReverseGeocodeResponse
+	18, // 52: routeguide.RouteGuide.MonitorGeofences:output_type -> routeguide.GeofenceEvent
+	6,  // 53: routeguide.RouteGuide.ListFeaturesPage:output_type -> routeguide.ListFeaturesResponse
+	23, // 54: routeguide.Echo.Conversations:output_type -> routeguide.StreamResponse
+	45, // [45:55] is the sub-list for method output_type
+	35, // [35:45] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_pb_routeguide_proto_init() }
+func file_pb_routeguide_proto_init() {
+	if File_pb_routeguide_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pb_routeguide_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Point); i {
 			case 0:
 				return &v.state
@@ -545,7 +2229,7 @@ func file_pb_routeguide_proto_init() {
 			}
 		}
 		file_pb_routeguide_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Feature); i {
+			switch v := v.(*ListFeaturesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -557,7 +2241,7 @@ func file_pb_routeguide_proto_init() {
 			}
 		}
 		file_pb_routeguide_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteNode); i {
+			switch v := v.(*ListFeaturesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -569,7 +2253,7 @@ func file_pb_routeguide_proto_init() {
 			}
 		}
 		file_pb_routeguide_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteSummary); i {
+			switch v := v.(*LinearRing); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -581,7 +2265,7 @@ func file_pb_routeguide_proto_init() {
 			}
 		}
 		file_pb_routeguide_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StreamRequest); i {
+			switch v := v.(*Polygon); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -593,6 +2277,174 @@ func file_pb_routeguide_proto_init() {
 			}
 		}
 		file_pb_routeguide_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Circle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Corridor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchFeaturesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchFeaturesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReverseGeocodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Address); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReverseGeocodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeofenceOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeofencePosition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeofenceEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Feature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StreamResponse); i {
 			case 0:
 				return &v.state
@@ -604,19 +2456,50 @@ func file_pb_routeguide_proto_init() {
 				return nil
 			}
 		}
+		file_pb_routeguide_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoomEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_routeguide_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchFeaturesResponse_Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pb_routeguide_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*ListFeaturesRequest_Rect)(nil),
+		(*ListFeaturesRequest_Polygon)(nil),
+		(*ListFeaturesRequest_Circle)(nil),
+		(*ListFeaturesRequest_Corridor)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pb_routeguide_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      3,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
 		GoTypes:           file_pb_routeguide_proto_goTypes,
 		DependencyIndexes: file_pb_routeguide_proto_depIdxs,
+		EnumInfos:         file_pb_routeguide_proto_enumTypes,
 		MessageInfos:      file_pb_routeguide_proto_msgTypes,
 	}.Build()
 	File_pb_routeguide_proto = out.File
@@ -22,10 +22,26 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RouteGuideClient interface {
+	// GET /v1/features?lat=407838351&lng=-746143763
 	GetFeature(ctx context.Context, in *Point, opts ...grpc.CallOption) (*Feature, error)
+	// GET /v1/features:list?rect=lo_lat,lo_lng,hi_lat,hi_lng
+	// 返回换行分隔的 JSON (NDJSON)，每行一个 Feature
 	ListFeatures(ctx context.Context, in *Rectangle, opts ...grpc.CallOption) (RouteGuide_ListFeaturesClient, error)
+	// POST /v1/routes:record，请求体为 Point 数组或者 NDJSON
 	RecordRoute(ctx context.Context, opts ...grpc.CallOption) (RouteGuide_RecordRouteClient, error)
 	RouteChat(ctx context.Context, opts ...grpc.CallOption) (RouteGuide_RouteChatClient, error)
+	// QueryFeatures 和 ListFeatures 一样返回流，但是支持分页和排序。
+	// 匹配的总数在 x-total-count header 中，下一页的 page_token 在 x-next-page-token trailer 中
+	QueryFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (RouteGuide_QueryFeaturesClient, error)
+	// GET /v1/features:search?q=new+jer&bias=lat,lng&limit=10
+	SearchFeatures(ctx context.Context, in *SearchFeaturesRequest, opts ...grpc.CallOption) (*SearchFeaturesResponse, error)
+	// ReverseGeocode 返回 radius_meters 范围内离 location 最近的有名字的特征，没有时返回 NotFound。
+	// GET /v1/features:reverse?lat=407838351&lng=-746143763&radius=1000
+	ReverseGeocode(ctx context.Context, in *ReverseGeocodeRequest, opts ...grpc.CallOption) (*ReverseGeocodeResponse, error)
+	// MonitorGeofences 接收客户端的位置，位置进入、离开特征周围的范围或者在范围内停留足够久时发送事件
+	MonitorGeofences(ctx context.Context, opts ...grpc.CallOption) (RouteGuide_MonitorGeofencesClient, error)
+	// GET /v1/features:page?rect=...&page_size=&page_token=&order_by=distance&origin=lat,lng
+	ListFeaturesPage(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error)
 }
 
 type routeGuideClient struct {
@@ -142,14 +158,120 @@ func (x *routeGuideRouteChatClient) Recv() (*RouteNode, error) {
 	return m, nil
 }
 
+func (c *routeGuideClient) QueryFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (RouteGuide_QueryFeaturesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RouteGuide_ServiceDesc.Streams[3], "/routeguide.RouteGuide/QueryFeatures", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routeGuideQueryFeaturesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RouteGuide_QueryFeaturesClient interface {
+	Recv() (*Feature, error)
+	grpc.ClientStream
+}
+
+type routeGuideQueryFeaturesClient struct {
+	grpc.ClientStream
+}
+
+func (x *routeGuideQueryFeaturesClient) Recv() (*Feature, error) {
+	m := new(Feature)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routeGuideClient) SearchFeatures(ctx context.Context, in *SearchFeaturesRequest, opts ...grpc.CallOption) (*SearchFeaturesResponse, error) {
+	out := new(SearchFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/routeguide.RouteGuide/SearchFeatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routeGuideClient) ReverseGeocode(ctx context.Context, in *ReverseGeocodeRequest, opts ...grpc.CallOption) (*ReverseGeocodeResponse, error) {
+	out := new(ReverseGeocodeResponse)
+	err := c.cc.Invoke(ctx, "/routeguide.RouteGuide/ReverseGeocode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routeGuideClient) MonitorGeofences(ctx context.Context, opts ...grpc.CallOption) (RouteGuide_MonitorGeofencesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RouteGuide_ServiceDesc.Streams[4], "/routeguide.RouteGuide/MonitorGeofences", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routeGuideMonitorGeofencesClient{stream}
+	return x, nil
+}
+
+type RouteGuide_MonitorGeofencesClient interface {
+	Send(*GeofencePosition) error
+	Recv() (*GeofenceEvent, error)
+	grpc.ClientStream
+}
+
+type routeGuideMonitorGeofencesClient struct {
+	grpc.ClientStream
+}
+
+func (x *routeGuideMonitorGeofencesClient) Send(m *GeofencePosition) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *routeGuideMonitorGeofencesClient) Recv() (*GeofenceEvent, error) {
+	m := new(GeofenceEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routeGuideClient) ListFeaturesPage(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error) {
+	out := new(ListFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/routeguide.RouteGuide/ListFeaturesPage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouteGuideServer is the server API for RouteGuide service.
 // All implementations must embed UnimplementedRouteGuideServer
 // for forward compatibility
 type RouteGuideServer interface {
+	// GET /v1/features?lat=407838351&lng=-746143763
 	GetFeature(context.Context, *Point) (*Feature, error)
+	// GET /v1/features:list?rect=lo_lat,lo_lng,hi_lat,hi_lng
+	// 返回换行分隔的 JSON (NDJSON)，每行一个 Feature
 	ListFeatures(*Rectangle, RouteGuide_ListFeaturesServer) error
+	// POST /v1/routes:record，请求体为 Point 数组或者 NDJSON
 	RecordRoute(RouteGuide_RecordRouteServer) error
 	RouteChat(RouteGuide_RouteChatServer) error
+	// QueryFeatures 和 ListFeatures 一样返回流，但是支持分页和排序。
+	// 匹配的总数在 x-total-count header 中，下一页的 page_token 在 x-next-page-token trailer 中
+	QueryFeatures(*ListFeaturesRequest, RouteGuide_QueryFeaturesServer) error
+	// GET /v1/features:search?q=new+jer&bias=lat,lng&limit=10
+	SearchFeatures(context.Context, *SearchFeaturesRequest) (*SearchFeaturesResponse, error)
+	// ReverseGeocode 返回 radius_meters 范围内离 location 最近的有名字的特征，没有时返回 NotFound。
+	// GET /v1/features:reverse?lat=407838351&lng=-746143763&radius=1000
+	ReverseGeocode(context.Context, *ReverseGeocodeRequest) (*ReverseGeocodeResponse, error)
+	// MonitorGeofences 接收客户端的位置，位置进入、离开特征周围的范围或者在范围内停留足够久时发送事件
+	MonitorGeofences(RouteGuide_MonitorGeofencesServer) error
+	// GET /v1/features:page?rect=...&page_size=&page_token=&order_by=distance&origin=lat,lng
+	ListFeaturesPage(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error)
 	mustEmbedUnimplementedRouteGuideServer()
 }
 
@@ -169,6 +291,21 @@ func (UnimplementedRouteGuideServer) RecordRoute(RouteGuide_RecordRouteServer) e
 func (UnimplementedRouteGuideServer) RouteChat(RouteGuide_RouteChatServer) error {
 	return status.Errorf(codes.Unimplemented, "method RouteChat not implemented")
 }
+func (UnimplementedRouteGuideServer) QueryFeatures(*ListFeaturesRequest, RouteGuide_QueryFeaturesServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryFeatures not implemented")
+}
+func (UnimplementedRouteGuideServer) SearchFeatures(context.Context, *SearchFeaturesRequest) (*SearchFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchFeatures not implemented")
+}
+func (UnimplementedRouteGuideServer) ReverseGeocode(context.Context, *ReverseGeocodeRequest) (*ReverseGeocodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReverseGeocode not implemented")
+}
+func (UnimplementedRouteGuideServer) MonitorGeofences(RouteGuide_MonitorGeofencesServer) error {
+	return status.Errorf(codes.Unimplemented, "method MonitorGeofences not implemented")
+}
+func (UnimplementedRouteGuideServer) ListFeaturesPage(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeaturesPage not implemented")
+}
 func (UnimplementedRouteGuideServer) mustEmbedUnimplementedRouteGuideServer() {}
 
 // UnsafeRouteGuideServer may be embedded to opt out of forward compatibility for this service.
@@ -273,6 +410,107 @@ func (x *routeGuideRouteChatServer) Recv() (*RouteNode, error) {
 	return m, nil
 }
 
+func _RouteGuide_QueryFeatures_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListFeaturesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouteGuideServer).QueryFeatures(m, &routeGuideQueryFeaturesServer{stream})
+}
+
+type RouteGuide_QueryFeaturesServer interface {
+	Send(*Feature) error
+	grpc.ServerStream
+}
+
+type routeGuideQueryFeaturesServer struct {
+	grpc.ServerStream
+}
+
+func (x *routeGuideQueryFeaturesServer) Send(m *Feature) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RouteGuide_SearchFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouteGuideServer).SearchFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routeguide.RouteGuide/SearchFeatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouteGuideServer).SearchFeatures(ctx, req.(*SearchFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RouteGuide_ReverseGeocode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReverseGeocodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouteGuideServer).ReverseGeocode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routeguide.RouteGuide/ReverseGeocode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouteGuideServer).ReverseGeocode(ctx, req.(*ReverseGeocodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RouteGuide_MonitorGeofences_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RouteGuideServer).MonitorGeofences(&routeGuideMonitorGeofencesServer{stream})
+}
+
+type RouteGuide_MonitorGeofencesServer interface {
+	Send(*GeofenceEvent) error
+	Recv() (*GeofencePosition, error)
+	grpc.ServerStream
+}
+
+type routeGuideMonitorGeofencesServer struct {
+	grpc.ServerStream
+}
+
+func (x *routeGuideMonitorGeofencesServer) Send(m *GeofenceEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *routeGuideMonitorGeofencesServer) Recv() (*GeofencePosition, error) {
+	m := new(GeofencePosition)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _RouteGuide_ListFeaturesPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouteGuideServer).ListFeaturesPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routeguide.RouteGuide/ListFeaturesPage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouteGuideServer).ListFeaturesPage(ctx, req.(*ListFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RouteGuide_ServiceDesc is the grpc.ServiceDesc for RouteGuide service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -284,6 +522,18 @@ var RouteGuide_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFeature",
 			Handler:    _RouteGuide_GetFeature_Handler,
 		},
+		{
+			MethodName: "SearchFeatures",
+			Handler:    _RouteGuide_SearchFeatures_Handler,
+		},
+		{
+			MethodName: "ReverseGeocode",
+			Handler:    _RouteGuide_ReverseGeocode_Handler,
+		},
+		{
+			MethodName: "ListFeaturesPage",
+			Handler:    _RouteGuide_ListFeaturesPage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -302,6 +552,17 @@ var RouteGuide_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "QueryFeatures",
+			Handler:       _RouteGuide_QueryFeatures_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MonitorGeofences",
+			Handler:       _RouteGuide_MonitorGeofences_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "pb/routeguide.proto",
 }
@@ -0,0 +1,402 @@
+// Package client 封装了 pb.RouteGuideClient，调用方不再需要自己处理 stream.Recv() 和 io.EOF
+//
+// 所有方法返回的错误都是 *Error，可以用 errors.Is 判断错误类型。
+// 如果传入的 ctx 没有设置 deadline，除了 RouteChat 之外的方法都会使用
+// WithTimeout 设置的默认超时时间，RouteChat 是长连接，只受 ctx 控制
+package client
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultTimeout 是调用没有设置 deadline 时使用的超时时间
+const DefaultTimeout = 10 * time.Second
+
+// Client 是 RouteGuide 服务的客户端，可以被多个 goroutine 同时使用
+type Client struct {
+	rg      pb.RouteGuideClient
+	timeout time.Duration
+}
+
+// Option 用来配置 Client
+type Option func(*Client)
+
+// WithTimeout 设置默认的超时时间，d 为 0 表示不设置超时
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// New 使用 cc 创建一个 Client
+func New(cc grpc.ClientConnInterface, opts ...Option) *Client {
+	c := &Client{
+		rg:      pb.NewRouteGuideClient(cc),
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// withDefaultTimeout 在 ctx 没有 deadline 时加上默认的超时时间
+func (c *Client) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// GetFeature 返回 point 处的 Feature，该位置没有 Feature 时返回的 Feature 名字为空
+func (c *Client) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+	feature, err := c.rg.GetFeature(ctx, point)
+	return feature, wrapError("GetFeature", err)
+}
+
+// ListFeatures 对 rect 范围内的每个 Feature 调用一次 fn，fn 返回错误时停止遍历并返回该错误
+func (c *Client) ListFeatures(ctx context.Context, rect *pb.Rectangle, fn func(*pb.Feature) error) error {
+	it := c.Features(ctx, rect)
+	defer it.Close()
+	for it.Next() {
+		if err := fn(it.Feature()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Features 返回 rect 范围内 Feature 的迭代器，使用完之后需要调用 Close
+//
+//	it := c.Features(ctx, rect)
+//	defer it.Close()
+//	for it.Next() {
+//		feature := it.Feature()
+//	}
+//	if err := it.Err(); err != nil { ... }
+func (c *Client) Features(ctx context.Context, rect *pb.Rectangle) *FeatureIterator {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	it := &FeatureIterator{method: "ListFeatures", cancel: cancel}
+	stream, err := c.rg.ListFeatures(ctx, rect)
+	it.stream, it.err = stream, wrapError(it.method, err)
+	return it
+}
+
+// QueryFeatures 返回 req 请求的一页 Feature 的迭代器，遍历结束之后可以从 NextPageToken 得到下一页
+func (c *Client) QueryFeatures(ctx context.Context, req *pb.ListFeaturesRequest) *FeatureIterator {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	it := &FeatureIterator{method: "QueryFeatures", cancel: cancel}
+	stream, err := c.rg.QueryFeatures(ctx, req)
+	it.stream, it.err = stream, wrapError(it.method, err)
+	return it
+}
+
+// ListFeaturesPage 返回 req 请求的一页 Feature
+func (c *Client) ListFeaturesPage(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+	resp, err := c.rg.ListFeaturesPage(ctx, req)
+	return resp, wrapError("ListFeaturesPage", err)
+}
+
+// SearchFeatures 按名字搜索 Feature
+func (c *Client) SearchFeatures(ctx context.Context, req *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+	resp, err := c.rg.SearchFeatures(ctx, req)
+	return resp, wrapError("SearchFeatures", err)
+}
+
+// ReverseGeocode 返回 req.Location 附近最近的有名字的特征和它的地址，范围内没有特征时返回的错误满足
+// errors.Is(err, ErrNotFound)
+func (c *Client) ReverseGeocode(ctx context.Context, req *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+	resp, err := c.rg.ReverseGeocode(ctx, req)
+	return resp, wrapError("ReverseGeocode", err)
+}
+
+// QueryFeatures 返回分页信息使用的 metadata，和 service.TotalCountKey, service.NextPageTokenKey 保持一致
+const (
+	totalCountKey    = "x-total-count"
+	nextPageTokenKey = "x-next-page-token"
+)
+
+// featureStream 是 ListFeatures 和 QueryFeatures 返回的流
+type featureStream interface {
+	Recv() (*pb.Feature, error)
+	grpc.ClientStream
+}
+
+// FeatureIterator 遍历 ListFeatures 或者 QueryFeatures 返回的 Feature
+type FeatureIterator struct {
+	method  string
+	stream  featureStream
+	cancel  context.CancelFunc
+	current *pb.Feature
+	err     error
+	done    bool
+}
+
+// Next 读取下一个 Feature，没有更多的 Feature 或者出错时返回 false
+func (it *FeatureIterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	feature, err := it.stream.Recv()
+	if err == io.EOF {
+		it.done = true
+		it.current = nil
+		return false
+	}
+	if err != nil {
+		it.err = wrapError(it.method, err)
+		it.current = nil
+		return false
+	}
+	it.current = feature
+	return true
+}
+
+// Feature 返回 Next 读取到的 Feature
+func (it *FeatureIterator) Feature() *pb.Feature {
+	return it.current
+}
+
+// Err 返回遍历过程中遇到的错误，正常结束时返回 nil
+func (it *FeatureIterator) Err() error {
+	return it.err
+}
+
+// TotalCount 返回 QueryFeatures 匹配的 Feature 总数，服务端没有返回时为 -1
+func (it *FeatureIterator) TotalCount() int {
+	if it.stream == nil {
+		return -1
+	}
+	md, err := it.stream.Header()
+	if err != nil || len(md.Get(totalCountKey)) == 0 {
+		return -1
+	}
+	n, err := strconv.Atoi(md.Get(totalCountKey)[0])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// NextPageToken 返回 QueryFeatures 下一页的 page_token，只有在 Next 返回 false 之后调用才有意义，
+// 没有下一页时返回空字符串
+func (it *FeatureIterator) NextPageToken() string {
+	if it.stream == nil || !it.done {
+		return ""
+	}
+	if v := it.stream.Trailer().Get(nextPageTokenKey); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Close 结束遍历并释放流占用的资源，可以多次调用
+func (it *FeatureIterator) Close() {
+	it.done = true
+	it.cancel()
+}
+
+// RecordRoute 发送 points 并返回服务端计算的路线摘要
+func (c *Client) RecordRoute(ctx context.Context, points []*pb.Point) (*pb.RouteSummary, error) {
+	ch := make(chan *pb.Point, len(points))
+	for _, p := range points {
+		ch <- p
+	}
+	close(ch)
+	return c.RecordRouteChan(ctx, ch)
+}
+
+// RecordRouteChan 发送 points 中的所有点，points 关闭之后返回路线摘要
+func (c *Client) RecordRouteChan(ctx context.Context, points <-chan *pb.Point) (*pb.RouteSummary, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+	stream, err := c.rg.RecordRoute(ctx)
+	if err != nil {
+		return nil, wrapError("RecordRoute", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, wrapError("RecordRoute", ctx.Err())
+		case point, ok := <-points:
+			if !ok {
+				summary, err := stream.CloseAndRecv()
+				return summary, wrapError("RecordRoute", err)
+			}
+			if err := stream.Send(point); err != nil {
+				// Send 返回 io.EOF 时，真正的错误需要通过 RecvMsg 获取
+				if err == io.EOF {
+					err = stream.RecvMsg(new(pb.RouteSummary))
+				}
+				return nil, wrapError("RecordRoute", err)
+			}
+		}
+	}
+}
+
+// RouteChat 打开一个 RouteChat 会话
+func (c *Client) RouteChat(ctx context.Context) (*ChatSession, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.rg.RouteChat(ctx)
+	if err != nil {
+		cancel()
+		return nil, wrapError("RouteChat", err)
+	}
+	s := &ChatSession{
+		stream: stream,
+		cancel: cancel,
+		notes:  make(chan *pb.RouteNode),
+	}
+	go s.recvLoop(ctx)
+	return s, nil
+}
+
+// ChatSession 是一个 RouteChat 会话，Send 和 Notes 可以在不同的 goroutine 中使用
+type ChatSession struct {
+	stream pb.RouteGuide_RouteChatClient
+	cancel context.CancelFunc
+	notes  chan *pb.RouteNode
+	err    error
+}
+
+func (s *ChatSession) recvLoop(ctx context.Context) {
+	defer close(s.notes)
+	for {
+		note, err := s.stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			s.err = wrapError("RouteChat", err)
+			return
+		}
+		select {
+		case s.notes <- note:
+		case <-ctx.Done():
+			s.err = wrapError("RouteChat", ctx.Err())
+			return
+		}
+	}
+}
+
+// Send 发送一条消息，Send 不能被多个 goroutine 同时调用
+func (s *ChatSession) Send(note *pb.RouteNode) error {
+	err := s.stream.Send(note)
+	if err == io.EOF {
+		// 流已经结束，错误会通过 Err 返回
+		return nil
+	}
+	return wrapError("RouteChat", err)
+}
+
+// CloseSend 告诉服务端不会再发送消息，之后仍然可以从 Notes 中读取服务端的消息
+func (s *ChatSession) CloseSend() error {
+	return wrapError("RouteChat", s.stream.CloseSend())
+}
+
+// Notes 返回服务端发来的消息，会话结束之后 channel 会被关闭
+func (s *ChatSession) Notes() <-chan *pb.RouteNode {
+	return s.notes
+}
+
+// Err 返回会话结束的原因，只有在 Notes 被关闭之后调用才有意义，正常结束时返回 nil
+func (s *ChatSession) Err() error {
+	return s.err
+}
+
+// Close 立即结束会话
+func (s *ChatSession) Close() {
+	s.cancel()
+}
+
+// MonitorGeofences 打开一个 MonitorGeofences 会话，第一个位置的 Options 配置整个会话
+func (c *Client) MonitorGeofences(ctx context.Context) (*GeofenceSession, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.rg.MonitorGeofences(ctx)
+	if err != nil {
+		cancel()
+		return nil, wrapError("MonitorGeofences", err)
+	}
+	s := &GeofenceSession{
+		stream: stream,
+		cancel: cancel,
+		events: make(chan *pb.GeofenceEvent),
+	}
+	go s.recvLoop(ctx)
+	return s, nil
+}
+
+// GeofenceSession 是一个 MonitorGeofences 会话，一个位置可能触发任意多个事件，
+// Send 和 Events 可以在不同的 goroutine 中使用
+type GeofenceSession struct {
+	stream pb.RouteGuide_MonitorGeofencesClient
+	cancel context.CancelFunc
+	events chan *pb.GeofenceEvent
+	err    error
+}
+
+func (s *GeofenceSession) recvLoop(ctx context.Context) {
+	defer close(s.events)
+	for {
+		event, err := s.stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			s.err = wrapError("MonitorGeofences", err)
+			return
+		}
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			s.err = wrapError("MonitorGeofences", ctx.Err())
+			return
+		}
+	}
+}
+
+// Send 发送一个位置，Send 不能被多个 goroutine 同时调用
+func (s *GeofenceSession) Send(pos *pb.GeofencePosition) error {
+	err := s.stream.Send(pos)
+	if err == io.EOF {
+		// 流已经结束，错误会通过 Err 返回
+		return nil
+	}
+	return wrapError("MonitorGeofences", err)
+}
+
+// CloseSend 告诉服务端不会再发送位置，之后仍然可以从 Events 中读取已经触发的事件
+func (s *GeofenceSession) CloseSend() error {
+	return wrapError("MonitorGeofences", s.stream.CloseSend())
+}
+
+// Events 返回服务端发来的事件，会话结束之后 channel 会被关闭
+func (s *GeofenceSession) Events() <-chan *pb.GeofenceEvent {
+	return s.events
+}
+
+// Err 返回会话结束的原因，只有在 Events 被关闭之后调用才有意义，正常结束时返回 nil
+func (s *GeofenceSession) Err() error {
+	return s.err
+}
+
+// Close 立即结束会话
+func (s *GeofenceSession) Close() {
+	s.cancel()
+}
@@ -0,0 +1,349 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/client"
+	"gRPCDemo/routeguide/routeguidetest"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	home   = &pb.Point{Latitude: 10000000, Longitude: 10000000}
+	office = &pb.Point{Latitude: 20000000, Longitude: 20000000}
+	park   = &pb.Point{Latitude: 30000000, Longitude: 30000000}
+	world  = &pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{Latitude: 100000000, Longitude: 100000000}}
+)
+
+func newFakeClient(t *testing.T, opts ...client.Option) (*routeguidetest.Server, *client.Client) {
+	t.Helper()
+	fake := routeguidetest.NewServer()
+	fake.SetFeatures(
+		&pb.Feature{Name: "home", Location: home},
+		&pb.Feature{Name: "office", Location: office},
+		&pb.Feature{Name: "park", Location: park},
+	)
+	return fake, client.New(routeguidetest.Dial(t, fake), opts...)
+}
+
+func TestFeatureIterator(t *testing.T) {
+	fake, c := newFakeClient(t)
+	ctx := context.Background()
+
+	it := c.Features(ctx, world)
+	var names []string
+	for it.Next() {
+		names = append(names, it.Feature().GetName())
+	}
+	if err := it.Err(); err != nil || len(names) != 3 {
+		t.Errorf("Features() = %v, %v, want 3 features", names, err)
+	}
+	if it.Next() || it.Feature() != nil {
+		t.Error("Next() after the end = true, want false and no feature")
+	}
+	it.Close()
+	it.Close()
+
+	// 中途出错时已经收到的 Feature 仍然可以使用，错误保留方法名
+	fake.FailAt(routeguidetest.ListFeatures, 2, status.Error(codes.Unavailable, "down"))
+	it = c.Features(ctx, world)
+	defer it.Close()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	var e *client.Error
+	if n != 1 || !errors.Is(it.Err(), client.ErrUnavailable) || !errors.As(it.Err(), &e) || e.Method != "ListFeatures" {
+		t.Errorf("Features() with a failure = %d features, %v, want 1 and ListFeatures Unavailable", n, it.Err())
+	}
+	if it.Next() {
+		t.Error("Next() after an error = true, want false")
+	}
+
+	// Close 之后不再读取
+	fake.FailAt(routeguidetest.ListFeatures, 0, nil)
+	it = c.Features(ctx, world)
+	if !it.Next() {
+		t.Fatalf("Next() = false, %v", it.Err())
+	}
+	it.Close()
+	if it.Next() {
+		t.Error("Next() after Close = true, want false")
+	}
+}
+
+func TestListFeaturesStopsOnCallbackError(t *testing.T) {
+	_, c := newFakeClient(t)
+	stop := errors.New("stop")
+	n := 0
+	err := c.ListFeatures(context.Background(), world, func(*pb.Feature) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 2 {
+		t.Errorf("ListFeatures() = %v after %d features, want stop after 2", err, n)
+	}
+}
+
+func TestQueryFeaturesPaging(t *testing.T) {
+	env := servicetest.Start(t)
+	c := client.New(env.Conn)
+	ctx := context.Background()
+
+	req := &pb.ListFeaturesRequest{PageSize: 4}
+	var names []string
+	for pages := 0; ; pages++ {
+		it := c.QueryFeatures(ctx, req)
+		for it.Next() {
+			names = append(names, it.Feature().GetName())
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("QueryFeatures() = %v", err)
+		}
+		if got := it.TotalCount(); got != len(servicetest.Features()) {
+			t.Errorf("TotalCount() = %d, want %d", got, len(servicetest.Features()))
+		}
+		token := it.NextPageToken()
+		it.Close()
+		if token == "" {
+			break
+		}
+		if pages > 10 {
+			t.Fatal("too many pages")
+		}
+		req.PageToken = token
+	}
+	if len(names) != len(servicetest.Features()) {
+		t.Errorf("QueryFeatures() returned %d features in all pages, want %d", len(names), len(servicetest.Features()))
+	}
+
+	// 出错时没有分页信息
+	it := c.QueryFeatures(ctx, &pb.ListFeaturesRequest{PageToken: "nope"})
+	defer it.Close()
+	if it.Next() || !errors.Is(it.Err(), client.ErrInvalidArgument) || it.NextPageToken() != "" {
+		t.Errorf("QueryFeatures() with a bad token = %v, want InvalidArgument", it.Err())
+	}
+}
+
+func TestRecordRouteChanCancel(t *testing.T) {
+	_, c := newFakeClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	points := make(chan *pb.Point)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.RecordRouteChan(ctx, points)
+		done <- err
+	}()
+	points <- home
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, client.ErrCanceled) || !errors.Is(err, context.Canceled) {
+			t.Errorf("RecordRouteChan() after cancel = %v, want Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RecordRouteChan() did not return after cancel")
+	}
+}
+
+func TestRecordRouteChanDefaultTimeout(t *testing.T) {
+	_, c := newFakeClient(t, client.WithTimeout(50*time.Millisecond))
+	points := make(chan *pb.Point)
+	_, err := c.RecordRouteChan(context.Background(), points)
+	if !errors.Is(err, client.ErrDeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RecordRouteChan() with an open channel = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestRecordRoute(t *testing.T) {
+	fake, c := newFakeClient(t)
+	summary, err := c.RecordRoute(context.Background(), []*pb.Point{home, office})
+	if err != nil || summary.PointCount != 2 || summary.FeatureCount != 2 {
+		t.Errorf("RecordRoute() = %v, %v, want 2 points and 2 features", summary, err)
+	}
+
+	fake.FailAt(routeguidetest.RecordRoute, 1, status.Error(codes.InvalidArgument, "bad point"))
+	if _, err := c.RecordRoute(context.Background(), []*pb.Point{home, office}); !errors.Is(err, client.ErrInvalidArgument) {
+		t.Errorf("RecordRoute() with a failure = %v, want InvalidArgument", err)
+	}
+}
+
+// drain 读取 s 剩下的所有消息，返回消息的数量
+func drain(t *testing.T, s *client.ChatSession) int {
+	t.Helper()
+	n := 0
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-s.Notes():
+			if !ok {
+				return n
+			}
+			n++
+		case <-timeout:
+			t.Fatal("Notes() was not closed")
+		}
+	}
+}
+
+func TestChatSession(t *testing.T) {
+	fake, c := newFakeClient(t)
+	ctx := context.Background()
+
+	// CloseSend 之后服务端结束调用，Notes 被关闭，Err 为 nil
+	s, err := c.RouteChat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(&pb.RouteNode{Location: home, Message: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if note := <-s.Notes(); note.GetMessage() != "a" {
+		t.Errorf("first note = %v, want a", note)
+	}
+	if err := s.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	if n := drain(t, s); n != 0 || s.Err() != nil {
+		t.Errorf("after CloseSend: %d more notes, Err() = %v, want 0 and nil", n, s.Err())
+	}
+
+	// Close 立即结束会话
+	s, err = c.RouteChat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Send(&pb.RouteNode{Location: home, Message: "a"})
+	s.Close()
+	drain(t, s)
+	if err := s.Err(); err != nil && !errors.Is(err, client.ErrCanceled) {
+		t.Errorf("Err() after Close = %v, want nil or Canceled", err)
+	}
+
+	// 服务端的错误通过 Err 返回，Send 不返回 io.EOF
+	fake.FailAt(routeguidetest.RouteChat, 2, status.Error(codes.Unavailable, "down"))
+	s, err = c.RouteChat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Send(&pb.RouteNode{Location: home, Message: "a"})
+	s.Send(&pb.RouteNode{Location: home, Message: "b"})
+	drain(t, s)
+	for i := 0; i < 3; i++ {
+		if err := s.Send(&pb.RouteNode{Location: home, Message: "c"}); err != nil {
+			t.Errorf("Send() after the server failed = %v, want nil", err)
+		}
+	}
+	if !errors.Is(s.Err(), client.ErrUnavailable) {
+		t.Errorf("Err() = %v, want Unavailable", s.Err())
+	}
+}
+
+func TestGeofenceSession(t *testing.T) {
+	fake, c := newFakeClient(t)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	position := func(p *pb.Point, after time.Duration) *pb.GeofencePosition {
+		return &pb.GeofencePosition{Location: p, Time: timestamppb.New(start.Add(after))}
+	}
+
+	s, err := c.MonitorGeofences(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for _, pos := range []*pb.GeofencePosition{position(home, 0), position(home, 2*time.Minute), position(office, 3*time.Minute)} {
+		if err := s.Send(pos); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.CloseSend()
+	var got []string
+	for ev := range s.Events() {
+		got = append(got, ev.Type.String()+" "+ev.Feature.GetName())
+	}
+	want := "[ENTER home DWELL home EXIT home ENTER office]"
+	if fmt.Sprint(got) != want || s.Err() != nil {
+		t.Errorf("events = %v, Err() = %v, want %s", got, s.Err(), want)
+	}
+
+	// 服务端的错误通过 Err 返回
+	fake.FailAt(routeguidetest.MonitorGeofences, 1, status.Error(codes.Unavailable, "down"))
+	s, err = c.MonitorGeofences(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Send(position(home, 0))
+	for range s.Events() {
+		t.Error("got an event after the server failed")
+	}
+	if !errors.Is(s.Err(), client.ErrUnavailable) {
+		t.Errorf("Err() = %v, want Unavailable", s.Err())
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	fake, c := newFakeClient(t)
+	sentinels := map[codes.Code]error{
+		codes.Canceled:           client.ErrCanceled,
+		codes.DeadlineExceeded:   client.ErrDeadlineExceeded,
+		codes.InvalidArgument:    client.ErrInvalidArgument,
+		codes.NotFound:           client.ErrNotFound,
+		codes.ResourceExhausted:  client.ErrResourceExhausted,
+		codes.PermissionDenied:   client.ErrPermissionDenied,
+		codes.Unauthenticated:    client.ErrUnauthenticated,
+		codes.Unimplemented:      client.ErrUnimplemented,
+		codes.Unavailable:        client.ErrUnavailable,
+		codes.Internal:           client.ErrInternal,
+		codes.FailedPrecondition: client.ErrFailedPrecondition,
+	}
+	for code := range sentinels {
+		code := code
+		fake.OnGetFeature(func(context.Context, *pb.Point) (*pb.Feature, error) {
+			return nil, status.Error(code, "boom")
+		})
+		_, err := c.GetFeature(context.Background(), home)
+		for other, s := range sentinels {
+			if got := errors.Is(err, s); got != (other == code) {
+				t.Errorf("errors.Is(%v, %v) = %v", err, s, got)
+			}
+		}
+		var e *client.Error
+		if !errors.As(err, &e) || e.Method != "GetFeature" || e.Message != "boom" || status.Code(err) != code {
+			t.Errorf("GetFeature() = %#v, want a GetFeature %v error", err, code)
+		}
+		if want := "routeguide: GetFeature: " + code.String() + ": boom"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+		if errors.Is(err, context.Canceled) != (code == codes.Canceled) || errors.Is(err, context.DeadlineExceeded) != (code == codes.DeadlineExceeded) {
+			t.Errorf("%v unwraps to the wrong context error", err)
+		}
+	}
+
+	// 客户端自己的超时同样被转换
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	fake.OnGetFeature(func(ctx context.Context, _ *pb.Point) (*pb.Feature, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if _, err := c.GetFeature(ctx, home); !errors.Is(err, client.ErrDeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetFeature() past the deadline = %v, want DeadlineExceeded", err)
+	}
+}
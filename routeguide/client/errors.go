@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error 是调用 RouteGuide 服务失败时返回的错误，它保留了 gRPC 的状态码和调用的方法
+//
+// 可以使用 errors.Is 和下面的 ErrXXX 比较错误类型，例如
+//
+//	if errors.Is(err, client.ErrUnavailable) { ... }
+type Error struct {
+	Code    codes.Code
+	Message string
+	// Method 是出错的方法名，例如 ListFeatures
+	Method string
+
+	status *status.Status
+}
+
+// 常见的错误类型，只用于 errors.Is 的比较
+var (
+	ErrCanceled           = &Error{Code: codes.Canceled}
+	ErrDeadlineExceeded   = &Error{Code: codes.DeadlineExceeded}
+	ErrInvalidArgument    = &Error{Code: codes.InvalidArgument}
+	ErrNotFound           = &Error{Code: codes.NotFound}
+	ErrResourceExhausted  = &Error{Code: codes.ResourceExhausted}
+	ErrPermissionDenied   = &Error{Code: codes.PermissionDenied}
+	ErrUnauthenticated    = &Error{Code: codes.Unauthenticated}
+	ErrUnimplemented      = &Error{Code: codes.Unimplemented}
+	ErrUnavailable        = &Error{Code: codes.Unavailable}
+	ErrInternal           = &Error{Code: codes.Internal}
+	ErrFailedPrecondition = &Error{Code: codes.FailedPrecondition}
+)
+
+func (e *Error) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("routeguide: %s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("routeguide: %s: %s: %s", e.Method, e.Code, e.Message)
+}
+
+// Is 在状态码相同时返回 true
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Unwrap 让取消和超时的错误同样可以和 context.Canceled, context.DeadlineExceeded 比较
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// GRPCStatus 让 status.FromError 和 status.Code 仍然可以作用于 Error
+func (e *Error) GRPCStatus() *status.Status {
+	if e.status != nil {
+		return e.status
+	}
+	return status.New(e.Code, e.Message)
+}
+
+// wrapError 将 gRPC 调用返回的错误转换成 *Error，nil 和 *Error 保持不变
+func wrapError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	var s *status.Status
+	switch err {
+	case context.Canceled:
+		s = status.New(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		s = status.New(codes.DeadlineExceeded, err.Error())
+	default:
+		s = status.Convert(err)
+	}
+	return &Error{Code: s.Code(), Message: s.Message(), Method: method, status: s}
+}
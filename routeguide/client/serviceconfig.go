@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/codes"
+	// 注册客户端健康检查
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultServiceConfig 是 RouteGuide 客户端默认使用的 service config
+//
+// GetFeature 和 ListFeatures 等查询方法是幂等的，遇到 UNAVAILABLE 时可以安全地重试。
+// GetFeature 使用 hedging，其他方法使用重试，按照 gRFC A6 一个方法只能使用其中一种。
+// grpc-go 会忽略 hedgingPolicy，这部分由 HedgingInterceptor 实现
+const DefaultServiceConfig = `{
+  "methodConfig": [
+    {
+      "name": [{"service": "routeguide.RouteGuide", "method": "GetFeature"}],
+      "waitForReady": true,
+      "hedgingPolicy": {
+        "maxAttempts": 3,
+        "hedgingDelay": "0.5s",
+        "nonFatalStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED"]
+      }
+    },
+    {
+      "name": [
+        {"service": "routeguide.RouteGuide", "method": "ListFeatures"},
+        {"service": "routeguide.RouteGuide", "method": "QueryFeatures"},
+        {"service": "routeguide.RouteGuide", "method": "ListFeaturesPage"},
+        {"service": "routeguide.RouteGuide", "method": "SearchFeatures"},
+        {"service": "routeguide.RouteGuide", "method": "ReverseGeocode"}
+      ],
+      "waitForReady": true,
+      "retryPolicy": {
+        "maxAttempts": 4,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    }
+  ]
+}`
+
+// 支持的负载均衡策略
+const (
+	RoundRobin   = "round_robin"
+	LeastRequest = "least_request"
+)
+
+// healthCheckService 是客户端健康检查使用的服务名，svc 在加载完特征数据库之后才会将其标记为 SERVING
+const healthCheckService = "routeguide.RouteGuide"
+
+// WithLoadBalancing 在 service config cfg 中加入负载均衡策略和客户端健康检查的配置，
+// cfg 为空时使用 DefaultServiceConfig，policy 为空时不做修改，即使用 gRPC 默认的 pick_first
+//
+// 开启健康检查之后，处于 NOT_SERVING 状态的副本不会被选中
+func WithLoadBalancing(cfg, policy string) (string, error) {
+	if cfg == "" {
+		cfg = DefaultServiceConfig
+	}
+	var lbConfig map[string]interface{}
+	switch policy {
+	case "":
+		return cfg, nil
+	case RoundRobin:
+		lbConfig = map[string]interface{}{"round_robin": map[string]interface{}{}}
+	case LeastRequest:
+		lbConfig = map[string]interface{}{leastrequest.Name: map[string]interface{}{"choiceCount": 2}}
+	default:
+		return "", fmt.Errorf("unknown load balancing policy %q, want %s or %s", policy, RoundRobin, LeastRequest)
+	}
+
+	var sc map[string]interface{}
+	if err := json.Unmarshal([]byte(cfg), &sc); err != nil {
+		return "", fmt.Errorf("invalid service config: %v", err)
+	}
+	sc["loadBalancingConfig"] = []interface{}{lbConfig}
+	sc["healthCheckConfig"] = map[string]interface{}{"serviceName": healthCheckService}
+	out, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// maxHedgingAttempts 和 gRPC 对 retry/hedging 的 maxAttempts 的上限保持一致
+const maxHedgingAttempts = 5
+
+// hedgingPolicy 对应 service config 中的 hedgingPolicy
+type hedgingPolicy struct {
+	MaxAttempts         int          `json:"maxAttempts"`
+	HedgingDelay        string       `json:"hedgingDelay"`
+	NonFatalStatusCodes []codes.Code `json:"nonFatalStatusCodes"`
+
+	delay time.Duration
+}
+
+type serviceConfig struct {
+	MethodConfig []struct {
+		Name []struct {
+			Service string `json:"service"`
+			Method  string `json:"method"`
+		} `json:"name"`
+		RetryPolicy   json.RawMessage `json:"retryPolicy"`
+		HedgingPolicy *hedgingPolicy  `json:"hedgingPolicy"`
+	} `json:"methodConfig"`
+}
+
+// parseHedgingPolicies 从 service config 中解析出每个方法的 hedgingPolicy，
+// key 是 /service/method，只指定 service 的配置 key 为 /service/
+func parseHedgingPolicies(cfg string) (map[string]*hedgingPolicy, error) {
+	var sc serviceConfig
+	if err := json.Unmarshal([]byte(cfg), &sc); err != nil {
+		return nil, fmt.Errorf("invalid service config: %v", err)
+	}
+
+	policies := make(map[string]*hedgingPolicy)
+	for _, mc := range sc.MethodConfig {
+		p := mc.HedgingPolicy
+		if p == nil {
+			continue
+		}
+		// hedging 的每次调用都会被 grpc-go 按照 retryPolicy 再重试，调用的次数会成倍增加
+		if mc.RetryPolicy != nil {
+			return nil, fmt.Errorf("invalid service config: retryPolicy and hedgingPolicy are mutually exclusive")
+		}
+		if p.MaxAttempts < 2 {
+			return nil, fmt.Errorf("invalid hedgingPolicy: maxAttempts must be greater than 1, got %d", p.MaxAttempts)
+		}
+		if p.MaxAttempts > maxHedgingAttempts {
+			p.MaxAttempts = maxHedgingAttempts
+		}
+		if p.HedgingDelay != "" {
+			d, err := time.ParseDuration(p.HedgingDelay)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid hedgingPolicy: hedgingDelay %q", p.HedgingDelay)
+			}
+			p.delay = d
+		}
+		for _, n := range mc.Name {
+			policies[fmt.Sprintf("/%s/%s", n.Service, n.Method)] = p
+		}
+	}
+	return policies, nil
+}
+
+func (p *hedgingPolicy) nonFatal(code codes.Code) bool {
+	for _, c := range p.NonFatalStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// DialOptions 返回使用 cfg 作为默认 service config 所需的 DialOption，包括 hedging 拦截器，
+// cfg 为空时使用 DefaultServiceConfig
+func DialOptions(cfg string) ([]grpc.DialOption, error) {
+	if cfg == "" {
+		cfg = DefaultServiceConfig
+	}
+	hedging, err := HedgingInterceptor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(cfg),
+		grpc.WithChainUnaryInterceptor(hedging),
+	}, nil
+}
+
+// HedgingInterceptor 根据 service config 中的 hedgingPolicy 对一元调用进行 hedging
+//
+// 第一次调用发出之后，每隔 hedgingDelay 再并发地发出一次相同的调用，最多 maxAttempts 次。
+// 任意一次调用成功或者返回非 nonFatalStatusCodes 中的错误时，取消其他调用并返回该结果。
+// 某次调用返回 nonFatalStatusCodes 中的错误时，立即发出下一次调用
+func HedgingInterceptor(cfg string) (grpc.UnaryClientInterceptor, error) {
+	policies, err := parseHedgingPolicies(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		p, ok := policies[method]
+		if !ok {
+			p, ok = policies[method[:strings.LastIndex(method, "/")+1]]
+		}
+		m, isProto := reply.(proto.Message)
+		if !ok || !isProto {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return hedge(ctx, p, method, req, m, cc, invoker, opts...)
+	}, nil
+}
+
+type attemptResult struct {
+	reply proto.Message
+	err   error
+}
+
+func hedge(ctx context.Context, p *hedgingPolicy, method string, req interface{}, reply proto.Message, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, p.MaxAttempts)
+	attempt := func() {
+		// 每次调用使用单独的 reply，避免并发写入调用方的 reply
+		r := reply.ProtoReflect().New().Interface()
+		err := invoker(ctx, method, req, r, cc, opts...)
+		results <- attemptResult{reply: r, err: err}
+	}
+
+	started, finished := 1, 0
+	go attempt()
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for finished < started {
+		select {
+		case <-timer.C:
+			if started < p.MaxAttempts {
+				started++
+				go attempt()
+				timer.Reset(p.delay)
+			}
+		case res := <-results:
+			finished++
+			if res.err == nil {
+				proto.Reset(reply)
+				proto.Merge(reply, res.reply)
+				return nil
+			}
+			lastErr = res.err
+			if !p.nonFatal(status.Code(res.err)) {
+				return res.err
+			}
+			if started < p.MaxAttempts {
+				started++
+				go attempt()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(p.delay)
+			}
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// faultyServer 是 RouteGuide 服务的替身，前 failures 次调用返回 code，
+// 第 stallCall 次 GetFeature 调用会一直阻塞到被取消
+type faultyServer struct {
+	pb.UnimplementedRouteGuideServer
+
+	code      codes.Code
+	failures  int32
+	stallCall int32
+
+	calls    int32
+	canceled int32
+}
+
+func (s *faultyServer) fault(ctx context.Context) error {
+	n := atomic.AddInt32(&s.calls, 1)
+	if n == s.stallCall {
+		<-ctx.Done()
+		atomic.AddInt32(&s.canceled, 1)
+		return ctx.Err()
+	}
+	if n <= s.failures {
+		return status.Errorf(s.code, "injected failure %d", n)
+	}
+	return nil
+}
+
+func (s *faultyServer) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	if err := s.fault(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.Feature{Name: "feature", Location: point}, nil
+}
+
+func (s *faultyServer) ListFeatures(rect *pb.Rectangle, stream pb.RouteGuide_ListFeaturesServer) error {
+	if err := s.fault(stream.Context()); err != nil {
+		return err
+	}
+	for _, p := range []*pb.Point{rect.Lo, rect.Hi} {
+		if err := stream.Send(&pb.Feature{Location: p}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestClient(t *testing.T, srv pb.RouteGuideServer, cfg string) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterRouteGuideServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	opts, err := DialOptions(cfg)
+	if err != nil {
+		t.Fatalf("DialOptions() = %v", err)
+	}
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.Dial("bufconn", opts...)
+	if err != nil {
+		t.Fatalf("grpc.Dial() = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return New(conn)
+}
+
+func TestGetFeatureRetriesUnavailable(t *testing.T) {
+	srv := &faultyServer{code: codes.Unavailable, failures: 2}
+	c := newTestClient(t, srv, "")
+
+	feature, err := c.GetFeature(context.Background(), &pb.Point{Latitude: 1, Longitude: 2})
+	if err != nil {
+		t.Fatalf("GetFeature() = %v, want nil", err)
+	}
+	if feature.GetName() != "feature" {
+		t.Errorf("GetFeature() name = %q, want %q", feature.GetName(), "feature")
+	}
+	if n := atomic.LoadInt32(&srv.calls); n < 3 {
+		t.Errorf("server saw %d calls, want at least 3", n)
+	}
+}
+
+func TestListFeaturesRetriesUnavailable(t *testing.T) {
+	srv := &faultyServer{code: codes.Unavailable, failures: 2}
+	c := newTestClient(t, srv, "")
+
+	var got int
+	rect := &pb.Rectangle{Lo: &pb.Point{Latitude: 1}, Hi: &pb.Point{Latitude: 2}}
+	err := c.ListFeatures(context.Background(), rect, func(*pb.Feature) error {
+		got++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ListFeatures() = %v, want nil", err)
+	}
+	if got != 2 {
+		t.Errorf("ListFeatures() returned %d features, want 2", got)
+	}
+	if n := atomic.LoadInt32(&srv.calls); n != 3 {
+		t.Errorf("server saw %d calls, want 3", n)
+	}
+}
+
+func TestGetFeatureAttemptsAreNotMultiplied(t *testing.T) {
+	srv := &faultyServer{code: codes.Unavailable, failures: 100}
+	c := newTestClient(t, srv, "")
+
+	if _, err := c.GetFeature(context.Background(), &pb.Point{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("GetFeature() = %v, want %v", err, ErrUnavailable)
+	}
+	// 只有 hedgingPolicy 的 maxAttempts 次调用，每次调用不会再被重试
+	if n := atomic.LoadInt32(&srv.calls); n != 3 {
+		t.Errorf("server saw %d calls, want hedging maxAttempts 3", n)
+	}
+}
+
+func TestNonRetryableCodeIsNotRetried(t *testing.T) {
+	srv := &faultyServer{code: codes.InvalidArgument, failures: 1}
+	c := newTestClient(t, srv, "")
+
+	_, err := c.GetFeature(context.Background(), &pb.Point{})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("GetFeature() = %v, want %v", err, ErrInvalidArgument)
+	}
+	if n := atomic.LoadInt32(&srv.calls); n != 1 {
+		t.Errorf("server saw %d calls, want 1", n)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	srv := &faultyServer{code: codes.Unavailable, failures: 100}
+	c := newTestClient(t, srv, "")
+
+	rect := &pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{}}
+	err := c.ListFeatures(context.Background(), rect, func(*pb.Feature) error { return nil })
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("ListFeatures() = %v, want %v", err, ErrUnavailable)
+	}
+	if n := atomic.LoadInt32(&srv.calls); n != 4 {
+		t.Errorf("server saw %d calls, want maxAttempts 4", n)
+	}
+}
+
+func TestHedgingReturnsFirstResponse(t *testing.T) {
+	const cfg = `{
+	  "methodConfig": [{
+	    "name": [{"service": "routeguide.RouteGuide", "method": "GetFeature"}],
+	    "hedgingPolicy": {"maxAttempts": 3, "hedgingDelay": "0.05s", "nonFatalStatusCodes": ["UNAVAILABLE"]}
+	  }]
+	}`
+	srv := &faultyServer{stallCall: 1}
+	c := newTestClient(t, srv, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	feature, err := c.GetFeature(ctx, &pb.Point{Latitude: 3})
+	if err != nil {
+		t.Fatalf("GetFeature() = %v, want nil", err)
+	}
+	if feature.GetLocation().GetLatitude() != 3 {
+		t.Errorf("GetFeature() = %v, want latitude 3", feature)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetFeature() took %v, hedged call should answer after about 50ms", elapsed)
+	}
+
+	// 返回结果之后，被阻塞的第一次调用应该被取消
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&srv.canceled) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stalled attempt was not canceled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// countingServer 记录 GetFeature 被并发调用的最大数量
+type countingServer struct {
+	pb.UnimplementedRouteGuideServer
+
+	mu       sync.Mutex
+	inflight int
+	peak     int
+}
+
+func (s *countingServer) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	s.mu.Lock()
+	s.inflight++
+	if s.inflight > s.peak {
+		s.peak = s.inflight
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inflight--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(300 * time.Millisecond):
+		return &pb.Feature{Location: point}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestHedgingRespectsMaxAttempts(t *testing.T) {
+	const cfg = `{
+	  "methodConfig": [{
+	    "name": [{"service": "routeguide.RouteGuide"}],
+	    "hedgingPolicy": {"maxAttempts": 2, "hedgingDelay": "0.01s"}
+	  }]
+	}`
+	srv := &countingServer{}
+	c := newTestClient(t, srv, cfg)
+
+	if _, err := c.GetFeature(context.Background(), &pb.Point{}); err != nil {
+		t.Fatalf("GetFeature() = %v, want nil", err)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.peak != 2 {
+		t.Errorf("peak concurrent attempts = %d, want 2", srv.peak)
+	}
+}
+
+func TestParseHedgingPoliciesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+	}{
+		{"invalid json", `{`},
+		{"too few attempts", `{"methodConfig": [{"name": [{"service": "s"}], "hedgingPolicy": {"maxAttempts": 1}}]}`},
+		{"bad delay", `{"methodConfig": [{"name": [{"service": "s"}], "hedgingPolicy": {"maxAttempts": 2, "hedgingDelay": "soon"}}]}`},
+		{"bad code", `{"methodConfig": [{"name": [{"service": "s"}], "hedgingPolicy": {"maxAttempts": 2, "nonFatalStatusCodes": ["NOPE"]}}]}`},
+		{"retry and hedging", `{"methodConfig": [{"name": [{"service": "s"}], "retryPolicy": {"maxAttempts": 2}, "hedgingPolicy": {"maxAttempts": 2}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := HedgingInterceptor(tt.cfg); err == nil {
+				t.Errorf("HedgingInterceptor(%s) = nil error, want error", tt.cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package resolver 实现了 routeguide:/// 形式的 gRPC 名字解析，客户端不需要外部代理就可以把请求分摊到多个 svc 副本上
+//
+// 支持两种 target:
+//
+//	routeguide:///localhost:10000,localhost:10001   逗号分隔的地址列表
+//	routeguide:///file/path/to/endpoints            从文件中读取地址，文件变化时自动更新
+//
+// 文件路径是 file/ 之后的部分，绝对路径需要写成 routeguide:///file//etc/routeguide/endpoints。
+// 文件中每行一个 host:port，空行和以 # 开头的行会被忽略。
+//
+// 导入这个包就会注册 routeguide scheme。
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme 是这个包注册的 scheme
+const Scheme = "routeguide"
+
+// filePrefix 表示 target 是一个地址文件
+const filePrefix = "file/"
+
+// DefaultPollInterval 是检查地址文件是否发生变化的时间间隔
+const DefaultPollInterval = 5 * time.Second
+
+var logger = grpclog.Component("routeguide-resolver")
+
+func init() {
+	resolver.Register(NewBuilder(DefaultPollInterval))
+}
+
+// NewBuilder 返回 routeguide scheme 的 resolver.Builder，pollInterval 是检查地址文件的时间间隔。
+// 通常不需要直接调用，可以配合 grpc.WithResolvers 使用不同的 pollInterval
+func NewBuilder(pollInterval time.Duration) resolver.Builder {
+	return &builder{pollInterval: pollInterval}
+}
+
+type builder struct {
+	pollInterval time.Duration
+}
+
+func (b *builder) Scheme() string {
+	return Scheme
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	endpoint := target.Endpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("routeguide resolver: empty target %q", target.URL.String())
+	}
+
+	if !strings.HasPrefix(endpoint, filePrefix) {
+		addrs := parseAddrs(strings.Split(endpoint, ","))
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("routeguide resolver: no address in target %q", target.URL.String())
+		}
+		if err := cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+			logger.Warningf("failed to update state: %v", err)
+		}
+		return staticResolver{}, nil
+	}
+
+	r := &fileResolver{
+		path:     strings.TrimPrefix(endpoint, filePrefix),
+		cc:       cc,
+		interval: b.pollInterval,
+		resolve:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	// 第一次读取文件失败时直接返回错误，让使用者尽早发现配置问题
+	if err := r.update(); err != nil {
+		return nil, err
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+// staticResolver 的地址列表不会变化
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (staticResolver) Close() {}
+
+// fileResolver 定期读取地址文件，内容变化时更新地址列表
+type fileResolver struct {
+	path     string
+	cc       resolver.ClientConn
+	interval time.Duration
+
+	last    []byte
+	resolve chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func (r *fileResolver) watch() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.resolve:
+		}
+		if err := r.update(); err != nil {
+			// 保留之前的地址列表，由 ClientConn 决定是否重试
+			logger.Warningf("%v", err)
+			r.cc.ReportError(err)
+		}
+	}
+}
+
+// update 读取地址文件，内容和上次相同时不会更新 ClientConn
+func (r *fileResolver) update() error {
+	data, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("routeguide resolver: %v", err)
+	}
+	if r.last != nil && bytes.Equal(data, r.last) {
+		return nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	addrs := parseAddrs(lines)
+	if len(addrs) == 0 {
+		return fmt.Errorf("routeguide resolver: no address in %s", r.path)
+	}
+	logger.Infof("resolved %d addresses from %s", len(addrs), r.path)
+	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		// 不记录这次的内容，下次即使文件没有变化也会重新更新
+		return err
+	}
+	r.last = data
+	return nil
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolve <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fileResolver) Close() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// parseAddrs 去掉空白、空行和注释，返回剩下的地址
+func parseAddrs(lines []string) []resolver.Address {
+	var addrs []resolver.Address
+	for _, line := range lines {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{Addr: line})
+	}
+	return addrs
+}
@@ -0,0 +1,207 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/client"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+)
+
+// namedServer 返回的 Feature 名字是副本的名字，用来区分请求落在了哪个副本上
+type namedServer struct {
+	pb.UnimplementedRouteGuideServer
+	name string
+}
+
+func (s *namedServer) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	return &pb.Feature{Name: s.name, Location: point}, nil
+}
+
+func startReplica(t *testing.T, name string, st healthpb.HealthCheckResponse_ServingStatus) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() = %v", err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterRouteGuideServer(s, &namedServer{name: name})
+	hs := health.NewServer()
+	hs.SetServingStatus("routeguide.RouteGuide", st)
+	healthpb.RegisterHealthServer(s, hs)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func dial(t *testing.T, target, policy string) *client.Client {
+	t.Helper()
+	cfg, err := client.WithLoadBalancing("", policy)
+	if err != nil {
+		t.Fatalf("WithLoadBalancing() = %v", err)
+	}
+	opts, err := client.DialOptions(cfg)
+	if err != nil {
+		t.Fatalf("DialOptions() = %v", err)
+	}
+	opts = append(opts,
+		grpc.WithResolvers(NewBuilder(20*time.Millisecond)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		t.Fatalf("grpc.Dial(%q) = %v", target, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return client.New(conn, client.WithTimeout(5*time.Second))
+}
+
+// seen 调用 n 次 GetFeature，返回每个副本处理的次数
+func seen(t *testing.T, c *client.Client, n int) map[string]int {
+	t.Helper()
+	got := make(map[string]int)
+	for i := 0; i < n; i++ {
+		f, err := c.GetFeature(context.Background(), &pb.Point{})
+		if err != nil {
+			t.Fatalf("GetFeature() = %v", err)
+		}
+		got[f.GetName()]++
+	}
+	return got
+}
+
+func TestStaticListRoundRobin(t *testing.T) {
+	a := startReplica(t, "a", healthpb.HealthCheckResponse_SERVING)
+	b := startReplica(t, "b", healthpb.HealthCheckResponse_SERVING)
+	c := dial(t, "routeguide:///"+a+","+b, client.RoundRobin)
+
+	// 健康检查完成之前可能只有一个副本可用，多调用几次直到两个副本都被选中
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := seen(t, c, 10)
+		if got["a"] > 0 && got["b"] > 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("calls were not spread across replicas: %v", got)
+		}
+	}
+}
+
+func TestUnhealthyReplicaIsSkipped(t *testing.T) {
+	for _, policy := range []string{client.RoundRobin, client.LeastRequest} {
+		t.Run(policy, func(t *testing.T) {
+			a := startReplica(t, "a", healthpb.HealthCheckResponse_SERVING)
+			b := startReplica(t, "b", healthpb.HealthCheckResponse_NOT_SERVING)
+			c := dial(t, "routeguide:///"+a+","+b, policy)
+
+			if got := seen(t, c, 20); got["b"] != 0 {
+				t.Errorf("NOT_SERVING replica handled %d calls, want 0", got["b"])
+			}
+		})
+	}
+}
+
+func TestFileIsWatched(t *testing.T) {
+	a := startReplica(t, "a", healthpb.HealthCheckResponse_SERVING)
+	b := startReplica(t, "b", healthpb.HealthCheckResponse_SERVING)
+
+	path := filepath.Join(t.TempDir(), "endpoints")
+	write := func(lines ...string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("# replicas", a, "")
+	c := dial(t, "routeguide:///file/"+path, client.RoundRobin)
+
+	if got := seen(t, c, 5); got["a"] != 5 {
+		t.Fatalf("calls = %v, want all on a", got)
+	}
+
+	write(b)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got := seen(t, c, 5); got["b"] == 5 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("resolver did not pick up the new endpoints file")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// rejectingConn 的 UpdateState 依次返回 errs 中的错误，用完之后返回 nil
+type rejectingConn struct {
+	resolver.ClientConn
+	errs    []error
+	updates int
+}
+
+func (c *rejectingConn) UpdateState(resolver.State) error {
+	c.updates++
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func TestFileUpdateRetriedAfterRejection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints")
+	if err := ioutil.WriteFile(path, []byte("127.0.0.1:1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cc := &rejectingConn{errs: []error{errors.New("rejected")}}
+	r := &fileResolver{path: path, cc: cc}
+
+	if err := r.update(); err == nil {
+		t.Fatal("update() = nil, want the error of UpdateState")
+	}
+	// 文件没有变化，但是上次被拒绝了，需要再更新一次
+	if err := r.update(); err != nil || cc.updates != 2 {
+		t.Fatalf("second update() = %v after %d updates, want nil after 2", err, cc.updates)
+	}
+	if err := r.update(); err != nil || cc.updates != 2 {
+		t.Errorf("third update() = %v after %d updates, want no update for the same content", err, cc.updates)
+	}
+}
+
+func TestParseAddrs(t *testing.T) {
+	addrs := parseAddrs([]string{" a:1 ", "", "# comment", "b:2 # trailing"})
+	if len(addrs) != 2 || addrs[0].Addr != "a:1" || addrs[1].Addr != "b:2" {
+		t.Errorf("parseAddrs() = %v, want [a:1 b:2]", addrs)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	for _, target := range []string{"routeguide:///", "routeguide:///,,", "routeguide:///file/does/not/exist"} {
+		conn, err := grpc.Dial(target,
+			grpc.WithResolvers(NewBuilder(time.Second)),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err == nil {
+			// grpc.Dial 是非阻塞的，构建 resolver 的错误会在第一次调用时返回
+			_, err = client.New(conn).GetFeature(context.Background(), &pb.Point{})
+			conn.Close()
+		}
+		if err == nil {
+			t.Errorf("dial %q succeeded, want error", target)
+		}
+	}
+}
@@ -0,0 +1,753 @@
+// Package routeguidetest 提供了一个可编程的 RouteGuide 假服务，使用 RouteGuide 的代码不需要启动 svc 就可以做单元测试
+//
+//	fake := routeguidetest.NewServer()
+//	fake.SetFeatures(&pb.Feature{Name: "home", Location: home})
+//	fake.FailAt(routeguidetest.ListFeatures, 3, status.Error(codes.Unavailable, "boom"))
+//	fake.SetLatency(routeguidetest.GetFeature, 50*time.Millisecond)
+//
+//	c := routeguidetest.NewClient(t, fake)
+//	... 调用被测试的代码 ...
+//
+//	for _, call := range fake.Calls() { ... }
+//
+// 没有脚本的方法行为和真实的服务一致：GetFeature 和 ListFeatures 查询 SetFeatures 设置的特征，
+// QueryFeatures 和 ListFeaturesPage 按范围、排序和分页查询 SetFeatures 设置的特征，page_token 是特征的下标，
+// SearchFeatures 使用 search 包在 SetFeatures 设置的特征中搜索，ReverseGeocode 返回范围内最近的有名字的特征，
+// 地址由 address 包解析，
+// RecordRoute 根据收到的点计算 RouteSummary，RouteChat 把收到的消息原样返回，
+// MonitorGeofences 在位置进入、离开特征周围的范围或者在范围内停留足够久时发送事件
+package routeguidetest
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gRPCDemo/address"
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+	"gRPCDemo/search"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RouteGuide 的方法名，用于 FailAt, SetLatency 和 Call.Method
+const (
+	GetFeature       = "GetFeature"
+	ListFeatures     = "ListFeatures"
+	RecordRoute      = "RecordRoute"
+	RouteChat        = "RouteChat"
+	QueryFeatures    = "QueryFeatures"
+	ListFeaturesPage = "ListFeaturesPage"
+	MonitorGeofences = "MonitorGeofences"
+	SearchFeatures   = "SearchFeatures"
+	ReverseGeocode   = "ReverseGeocode"
+)
+
+// QueryFeatures 返回分页信息使用的 metadata，和 service.TotalCountKey, service.NextPageTokenKey 保持一致
+const (
+	totalCountKey    = "x-total-count"
+	nextPageTokenKey = "x-next-page-token"
+)
+
+// 分页和 MonitorGeofences 的默认值，和真实的服务一致
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+
+	defaultReverseGeocodeRadius = 1000.0
+
+	defaultGeofenceRadius = 100.0
+	defaultGeofenceDwell  = time.Minute
+	exitRadiusFactor      = 1.2
+)
+
+// Call 记录了一次调用
+type Call struct {
+	Method   string
+	Metadata metadata.MD
+	// Requests 是客户端发送的消息，一元调用和服务端流只有一个
+	Requests []proto.Message
+	// Err 是返回给客户端的错误
+	Err error
+}
+
+type fault struct {
+	n   int
+	err error
+}
+
+// Server 是 RouteGuide 的假服务，所有方法都可以在测试运行时并发调用
+type Server struct {
+	pb.UnimplementedRouteGuideServer
+
+	mu            sync.Mutex
+	features      []*pb.Feature
+	summary       *pb.RouteSummary
+	getFeature    func(context.Context, *pb.Point) (*pb.Feature, error)
+	routeChat     func(*pb.RouteNode) []*pb.RouteNode
+	queryFeatures func(context.Context, *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error)
+	geofences     func(*pb.GeofencePosition) []*pb.GeofenceEvent
+	search        func(context.Context, *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error)
+	reverse       func(context.Context, *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error)
+	faults        map[string]fault
+	latency       map[string]time.Duration
+	// unaryCalls 是每个一元方法被调用的次数
+	unaryCalls map[string]int
+	calls      []*Call
+}
+
+// NewServer 返回没有任何特征和脚本的假服务
+func NewServer() *Server {
+	return &Server{
+		faults:     make(map[string]fault),
+		latency:    make(map[string]time.Duration),
+		unaryCalls: make(map[string]int),
+	}
+}
+
+// SetFeatures 设置 GetFeature 和 ListFeatures 使用的特征
+func (s *Server) SetFeatures(features ...*pb.Feature) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.features = features
+}
+
+// OnGetFeature 使用 fn 处理 GetFeature，fn 为 nil 时恢复默认行为
+func (s *Server) OnGetFeature(fn func(ctx context.Context, point *pb.Point) (*pb.Feature, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.getFeature = fn
+}
+
+// SetRouteSummary 设置 RecordRoute 返回的结果，summary 为 nil 时根据收到的点计算
+func (s *Server) SetRouteSummary(summary *pb.RouteSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summary = summary
+}
+
+// OnRouteChat 使用 fn 生成 RouteChat 对每条消息的回复，fn 为 nil 时原样返回收到的消息
+func (s *Server) OnRouteChat(fn func(note *pb.RouteNode) []*pb.RouteNode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routeChat = fn
+}
+
+// OnQueryFeatures 使用 fn 处理 QueryFeatures 和 ListFeaturesPage，QueryFeatures 依次发送 fn 返回的特征，
+// 并通过 metadata 返回 TotalSize 和 NextPageToken。fn 为 nil 时恢复默认行为
+func (s *Server) OnQueryFeatures(fn func(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryFeatures = fn
+}
+
+// OnMonitorGeofences 使用 fn 生成 MonitorGeofences 对每个位置的事件，fn 为 nil 时恢复默认行为
+func (s *Server) OnMonitorGeofences(fn func(pos *pb.GeofencePosition) []*pb.GeofenceEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.geofences = fn
+}
+
+// OnSearchFeatures 使用 fn 处理 SearchFeatures，fn 为 nil 时恢复默认行为
+func (s *Server) OnSearchFeatures(fn func(ctx context.Context, req *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.search = fn
+}
+
+// OnReverseGeocode 使用 fn 处理 ReverseGeocode，fn 为 nil 时恢复默认行为
+func (s *Server) OnReverseGeocode(fn func(ctx context.Context, req *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reverse = fn
+}
+
+// FailAt 让 method 在第 n 条消息时返回 err，n 从 1 开始:
+//
+//	GetFeature        第 n 次调用
+//	ListFeaturesPage  第 n 次调用
+//	SearchFeatures    第 n 次调用
+//	ReverseGeocode    第 n 次调用
+//	ListFeatures      每次调用发送第 n 个 Feature 之前，之前的 n-1 个 Feature 会正常发送
+//	QueryFeatures     和 ListFeatures 相同
+//	RecordRoute       每次调用收到第 n 个 Point 时
+//	RouteChat         每次调用收到第 n 条消息时
+//	MonitorGeofences  每次调用收到第 n 个位置时
+//
+// err 为 nil 时取消 method 上的错误
+func (s *Server) FailAt(method string, n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.faults, method)
+		return
+	}
+	s.faults[method] = fault{n: n, err: err}
+}
+
+// SetLatency 让 method 在发送每条回复之前等待 d，客户端取消调用时立即返回
+func (s *Server) SetLatency(method string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[method] = d
+}
+
+// Calls 返回到目前为止所有调用的记录，按调用开始的顺序排列
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, len(s.calls))
+	for i, c := range s.calls {
+		calls[i] = *c
+		calls[i].Requests = append([]proto.Message(nil), c.Requests...)
+	}
+	return calls
+}
+
+// Requests 返回 method 的所有调用收到的消息
+func (s *Server) Requests(method string) []proto.Message {
+	var requests []proto.Message
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			requests = append(requests, c.Requests...)
+		}
+	}
+	return requests
+}
+
+func (s *Server) begin(ctx context.Context, method string) *Call {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c := &Call{Method: method, Metadata: md.Copy()}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, c)
+	return c
+}
+
+func (s *Server) record(c *Call, msg proto.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Requests = append(c.Requests, proto.Clone(msg))
+}
+
+func (s *Server) end(c *Call, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Err = err
+	return err
+}
+
+// fault 返回 method 在第 n 条消息时需要注入的错误
+func (s *Server) fault(method string, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.faults[method]; ok && f.n == n {
+		return f.err
+	}
+	return nil
+}
+
+// unaryCall 增加一元方法 method 的调用次数并返回它
+func (s *Server) unaryCall(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unaryCalls[method]++
+	return s.unaryCalls[method]
+}
+
+func (s *Server) delay(ctx context.Context, method string) error {
+	s.mu.Lock()
+	d := s.latency[method]
+	s.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (s *Server) snapshot() ([]*pb.Feature, *pb.RouteSummary, func(context.Context, *pb.Point) (*pb.Feature, error), func(*pb.RouteNode) []*pb.RouteNode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.features, s.summary, s.getFeature, s.routeChat
+}
+
+func (s *Server) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	c := s.begin(ctx, GetFeature)
+	s.record(c, point)
+
+	if err := s.fault(GetFeature, s.unaryCall(GetFeature)); err != nil {
+		return nil, s.end(c, err)
+	}
+	if err := s.delay(ctx, GetFeature); err != nil {
+		return nil, s.end(c, err)
+	}
+
+	features, _, fn, _ := s.snapshot()
+	if fn != nil {
+		feature, err := fn(ctx, point)
+		return feature, s.end(c, err)
+	}
+	for _, feature := range features {
+		if proto.Equal(feature.GetLocation(), point) {
+			return feature, s.end(c, nil)
+		}
+	}
+	return &pb.Feature{Location: point}, s.end(c, nil)
+}
+
+func (s *Server) ListFeatures(rect *pb.Rectangle, stream pb.RouteGuide_ListFeaturesServer) error {
+	c := s.begin(stream.Context(), ListFeatures)
+	s.record(c, rect)
+
+	features, _, _, _ := s.snapshot()
+	n := 0
+	for _, feature := range features {
+		if !geo.InRange(feature.GetLocation(), rect) {
+			continue
+		}
+		n++
+		if err := s.fault(ListFeatures, n); err != nil {
+			return s.end(c, err)
+		}
+		if err := s.delay(stream.Context(), ListFeatures); err != nil {
+			return s.end(c, err)
+		}
+		if err := stream.Send(feature); err != nil {
+			return s.end(c, err)
+		}
+	}
+	return s.end(c, nil)
+}
+
+func (s *Server) QueryFeatures(req *pb.ListFeaturesRequest, stream pb.RouteGuide_QueryFeaturesServer) error {
+	c := s.begin(stream.Context(), QueryFeatures)
+	s.record(c, req)
+
+	page, err := s.query(stream.Context(), req, 0)
+	if err != nil {
+		return s.end(c, err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(totalCountKey, strconv.Itoa(int(page.TotalSize)))); err != nil {
+		return s.end(c, err)
+	}
+	for i, feature := range page.Features {
+		if err := s.fault(QueryFeatures, i+1); err != nil {
+			return s.end(c, err)
+		}
+		if err := s.delay(stream.Context(), QueryFeatures); err != nil {
+			return s.end(c, err)
+		}
+		if err := stream.Send(feature); err != nil {
+			return s.end(c, err)
+		}
+	}
+	if page.NextPageToken != "" {
+		stream.SetTrailer(metadata.Pairs(nextPageTokenKey, page.NextPageToken))
+	}
+	return s.end(c, nil)
+}
+
+func (s *Server) ListFeaturesPage(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error) {
+	c := s.begin(ctx, ListFeaturesPage)
+	s.record(c, req)
+
+	if err := s.fault(ListFeaturesPage, s.unaryCall(ListFeaturesPage)); err != nil {
+		return nil, s.end(c, err)
+	}
+	if err := s.delay(ctx, ListFeaturesPage); err != nil {
+		return nil, s.end(c, err)
+	}
+	page, err := s.query(ctx, req, defaultPageSize)
+	if err != nil {
+		return nil, s.end(c, err)
+	}
+	return page, s.end(c, nil)
+}
+
+// query 返回 OnQueryFeatures 设置的脚本或者默认的查询结果，page_size 为 0 时一页有 defaultSize 个，
+// defaultSize 为 0 表示不限制
+func (s *Server) query(ctx context.Context, req *pb.ListFeaturesRequest, defaultSize int) (*pb.ListFeaturesResponse, error) {
+	s.mu.Lock()
+	features, fn := s.features, s.queryFeatures
+	s.mu.Unlock()
+	if fn != nil {
+		return fn(ctx, req)
+	}
+
+	if req.OrderBy == pb.ListFeaturesRequest_DISTANCE && req.Origin == nil {
+		return nil, status.Error(codes.InvalidArgument, "origin is required when ordering by distance")
+	}
+	offset := 0
+	if req.PageToken != "" {
+		n, err := strconv.Atoi(req.PageToken)
+		if err != nil || n < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", req.PageToken)
+		}
+		offset = n
+	}
+
+	var matches []*pb.Feature
+	for _, feature := range features {
+		if inArea(feature.GetLocation(), req) {
+			matches = append(matches, feature)
+		}
+	}
+	switch req.OrderBy {
+	case pb.ListFeaturesRequest_NAME:
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i].GetName(), matches[j].GetName()
+			if a == "" || b == "" {
+				return b == "" && a != ""
+			}
+			return a < b
+		})
+	case pb.ListFeaturesRequest_DISTANCE:
+		sort.SliceStable(matches, func(i, j int) bool {
+			return geo.Distance(req.Origin, matches[i].GetLocation()) < geo.Distance(req.Origin, matches[j].GetLocation())
+		})
+	}
+
+	page := &pb.ListFeaturesResponse{TotalSize: int32(len(matches))}
+	if offset >= len(matches) {
+		return page, nil
+	}
+	size := int(req.PageSize)
+	if size == 0 {
+		size = defaultSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	end := len(matches)
+	if size > 0 && offset+size < end {
+		end = offset + size
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	page.Features = matches[offset:end]
+	return page, nil
+}
+
+// inArea 判断 p 是否在 req.Area 中，没有设置范围时总是返回 true
+func inArea(p *pb.Point, req *pb.ListFeaturesRequest) bool {
+	switch area := req.Area.(type) {
+	case *pb.ListFeaturesRequest_Rect:
+		return geo.InRange(p, area.Rect)
+	case *pb.ListFeaturesRequest_Polygon:
+		return geo.InPolygon(p, area.Polygon)
+	case *pb.ListFeaturesRequest_Circle:
+		return geo.InCircle(p, area.Circle)
+	case *pb.ListFeaturesRequest_Corridor:
+		return geo.InCorridor(p, area.Corridor)
+	}
+	return true
+}
+
+func (s *Server) SearchFeatures(ctx context.Context, req *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error) {
+	c := s.begin(ctx, SearchFeatures)
+	s.record(c, req)
+
+	if err := s.fault(SearchFeatures, s.unaryCall(SearchFeatures)); err != nil {
+		return nil, s.end(c, err)
+	}
+	if err := s.delay(ctx, SearchFeatures); err != nil {
+		return nil, s.end(c, err)
+	}
+
+	s.mu.Lock()
+	features, fn := s.features, s.search
+	s.mu.Unlock()
+	if fn != nil {
+		resp, err := fn(ctx, req)
+		return resp, s.end(c, err)
+	}
+	results, completions := search.NewIndex(features).Search(search.Query{
+		Text:      req.Query,
+		Bias:      req.Bias,
+		BiasScale: req.BiasScaleMeters,
+		Limit:     int(req.Limit),
+	})
+	resp := &pb.SearchFeaturesResponse{Completions: completions}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &pb.SearchFeaturesResponse_Result{
+			Feature:        r.Feature,
+			Score:          r.Score,
+			DistanceMeters: r.Distance,
+		})
+	}
+	return resp, s.end(c, nil)
+}
+
+func (s *Server) ReverseGeocode(ctx context.Context, req *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error) {
+	c := s.begin(ctx, ReverseGeocode)
+	s.record(c, req)
+
+	if err := s.fault(ReverseGeocode, s.unaryCall(ReverseGeocode)); err != nil {
+		return nil, s.end(c, err)
+	}
+	if err := s.delay(ctx, ReverseGeocode); err != nil {
+		return nil, s.end(c, err)
+	}
+
+	s.mu.Lock()
+	features, fn := s.features, s.reverse
+	s.mu.Unlock()
+	if fn != nil {
+		resp, err := fn(ctx, req)
+		return resp, s.end(c, err)
+	}
+	radius := req.RadiusMeters
+	if radius == 0 {
+		radius = defaultReverseGeocodeRadius
+	}
+	var nearest *pb.Feature
+	distance := int32(math.MaxInt32)
+	for _, feature := range features {
+		if feature.GetName() == "" {
+			continue
+		}
+		if d := geo.Distance(req.Location, feature.GetLocation()); d < distance {
+			nearest, distance = feature, d
+		}
+	}
+	if nearest == nil || float64(distance) > radius {
+		return nil, s.end(c, status.Errorf(codes.NotFound, "no named feature within %vm", radius))
+	}
+	a := address.Parse(nearest.Name)
+	return &pb.ReverseGeocodeResponse{
+		Feature: nearest,
+		Address: &pb.Address{
+			Street:     a.Street,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		},
+		DistanceMeters: distance,
+		Confidence:     a.Completeness() * (1 - float64(distance)/(2*radius)),
+	}, s.end(c, nil)
+}
+
+func (s *Server) RecordRoute(stream pb.RouteGuide_RecordRouteServer) error {
+	c := s.begin(stream.Context(), RecordRoute)
+	features, summary, _, _ := s.snapshot()
+
+	computed := &pb.RouteSummary{}
+	var last *pb.Point
+	for n := 1; ; n++ {
+		point, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return s.end(c, err)
+		}
+		s.record(c, point)
+		if err := s.fault(RecordRoute, n); err != nil {
+			return s.end(c, err)
+		}
+		computed.PointCount++
+		for _, feature := range features {
+			if proto.Equal(feature.GetLocation(), point) {
+				computed.FeatureCount++
+			}
+		}
+		if last != nil {
+			computed.Distance += geo.Distance(last, point)
+		}
+		last = point
+	}
+
+	if err := s.delay(stream.Context(), RecordRoute); err != nil {
+		return s.end(c, err)
+	}
+	if summary == nil {
+		summary = computed
+	}
+	return s.end(c, stream.SendAndClose(summary))
+}
+
+func (s *Server) RouteChat(stream pb.RouteGuide_RouteChatServer) error {
+	c := s.begin(stream.Context(), RouteChat)
+	for n := 1; ; n++ {
+		note, err := stream.Recv()
+		if err == io.EOF {
+			return s.end(c, nil)
+		}
+		if err != nil {
+			return s.end(c, err)
+		}
+		s.record(c, note)
+		if err := s.fault(RouteChat, n); err != nil {
+			return s.end(c, err)
+		}
+
+		replies := []*pb.RouteNode{note}
+		if _, _, _, fn := s.snapshot(); fn != nil {
+			replies = fn(note)
+		}
+		for _, reply := range replies {
+			if err := s.delay(stream.Context(), RouteChat); err != nil {
+				return s.end(c, err)
+			}
+			if err := stream.Send(reply); err != nil {
+				return s.end(c, err)
+			}
+		}
+	}
+}
+
+func (s *Server) MonitorGeofences(stream pb.RouteGuide_MonitorGeofencesServer) error {
+	c := s.begin(stream.Context(), MonitorGeofences)
+	s.mu.Lock()
+	features, fn := s.features, s.geofences
+	s.mu.Unlock()
+
+	var fences *geofences
+	for n := 1; ; n++ {
+		pos, err := stream.Recv()
+		if err == io.EOF {
+			return s.end(c, nil)
+		}
+		if err != nil {
+			return s.end(c, err)
+		}
+		s.record(c, pos)
+		if err := s.fault(MonitorGeofences, n); err != nil {
+			return s.end(c, err)
+		}
+
+		var events []*pb.GeofenceEvent
+		if fn != nil {
+			events = fn(pos)
+		} else {
+			if fences == nil {
+				fences = newGeofences(features, pos.Options)
+			}
+			events = fences.update(pos)
+		}
+		for _, event := range events {
+			if err := s.delay(stream.Context(), MonitorGeofences); err != nil {
+				return s.end(c, err)
+			}
+			if err := stream.Send(event); err != nil {
+				return s.end(c, err)
+			}
+		}
+	}
+}
+
+// geofences 是 MonitorGeofences 默认行为的状态，使用调用开始时的特征，不检查 options 是否合法
+type geofences struct {
+	features   []*pb.Feature
+	radius     float64
+	exitRadius float64
+	dwell      time.Duration
+	// entered 是位置所在范围的特征的下标和进入的时间，dwelled 记录已经发送过 DWELL 事件的特征
+	entered map[int]time.Time
+	dwelled map[int]bool
+}
+
+func newGeofences(features []*pb.Feature, o *pb.GeofenceOptions) *geofences {
+	g := &geofences{
+		features:   features,
+		radius:     o.GetRadiusMeters(),
+		exitRadius: o.GetExitRadiusMeters(),
+		dwell:      o.GetDwell().AsDuration(),
+		entered:    make(map[int]time.Time),
+		dwelled:    make(map[int]bool),
+	}
+	if g.radius == 0 {
+		g.radius = defaultGeofenceRadius
+	}
+	if g.exitRadius == 0 {
+		g.exitRadius = g.radius * exitRadiusFactor
+	}
+	if g.dwell <= 0 {
+		g.dwell = defaultGeofenceDwell
+	}
+	return g
+}
+
+// update 返回位置 pos 触发的事件，按特征的顺序排列
+func (g *geofences) update(pos *pb.GeofencePosition) []*pb.GeofenceEvent {
+	t := time.Now()
+	if pos.Time != nil {
+		t = pos.Time.AsTime()
+	}
+	var events []*pb.GeofenceEvent
+	for i, feature := range g.features {
+		distance := geo.Distance(pos.Location, feature.GetLocation())
+		event := &pb.GeofenceEvent{Feature: feature, Location: pos.Location, Time: timestamppb.New(t), DistanceMeters: distance}
+		entered, inside := g.entered[i]
+		switch {
+		case inside && float64(distance) > g.exitRadius:
+			event.Type = pb.GeofenceEvent_EXIT
+			delete(g.entered, i)
+			delete(g.dwelled, i)
+		case inside && !g.dwelled[i] && t.Sub(entered) >= g.dwell:
+			event.Type = pb.GeofenceEvent_DWELL
+			g.dwelled[i] = true
+		case !inside && float64(distance) <= g.radius:
+			event.Type = pb.GeofenceEvent_ENTER
+			entered = t
+			g.entered[i] = t
+		default:
+			continue
+		}
+		event.Dwell = durationpb.New(t.Sub(entered))
+		events = append(events, event)
+	}
+	return events
+}
+
+// Dial 在内存中的 bufconn 上启动 s，返回连接到它的客户端连接，可以配合 routeguide/client 使用。
+// 测试结束时服务和连接都会被关闭
+func Dial(t testing.TB, s *Server, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterRouteGuideServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	conn, err := grpc.Dial("bufconn", opts...)
+	if err != nil {
+		t.Fatalf("routeguidetest: grpc.Dial() = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// NewClient 在 bufconn 上启动 s，返回可以直接使用的 pb.RouteGuideClient
+func NewClient(t testing.TB, s *Server, opts ...grpc.DialOption) pb.RouteGuideClient {
+	t.Helper()
+	return pb.NewRouteGuideClient(Dial(t, s, opts...))
+}
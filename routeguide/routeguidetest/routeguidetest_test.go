@@ -0,0 +1,323 @@
+package routeguidetest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/client"
+	"gRPCDemo/routeguide/routeguidetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	home   = &pb.Point{Latitude: 10000000, Longitude: 10000000}
+	office = &pb.Point{Latitude: 50000000, Longitude: 50000000}
+	world  = &pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{Latitude: 100000000, Longitude: 100000000}}
+)
+
+func newFake() *routeguidetest.Server {
+	fake := routeguidetest.NewServer()
+	fake.SetFeatures(
+		&pb.Feature{Name: "home", Location: home},
+		&pb.Feature{Name: "office", Location: office},
+	)
+	return fake
+}
+
+func TestDefaultBehavior(t *testing.T) {
+	fake := newFake()
+	c := routeguidetest.NewClient(t, fake)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "r1")
+
+	feature, err := c.GetFeature(ctx, home)
+	if err != nil || feature.GetName() != "home" {
+		t.Fatalf("GetFeature(home) = %v, %v, want home", feature, err)
+	}
+
+	stream, err := c.ListFeatures(ctx, world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for {
+		f, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		names = append(names, f.GetName())
+	}
+	if len(names) != 2 {
+		t.Errorf("ListFeatures() = %v, want home and office", names)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].Method != routeguidetest.GetFeature || calls[1].Method != routeguidetest.ListFeatures {
+		t.Fatalf("Calls() = %v, want GetFeature and ListFeatures", calls)
+	}
+	if got := calls[0].Metadata.Get("x-request-id"); len(got) != 1 || got[0] != "r1" {
+		t.Errorf("recorded metadata = %v, want x-request-id r1", calls[0].Metadata)
+	}
+	if got := fake.Requests(routeguidetest.GetFeature); len(got) != 1 || !proto.Equal(got[0], home) {
+		t.Errorf("Requests(GetFeature) = %v, want [home]", got)
+	}
+}
+
+func TestScriptedResponses(t *testing.T) {
+	fake := newFake()
+	fake.OnGetFeature(func(ctx context.Context, p *pb.Point) (*pb.Feature, error) {
+		return nil, status.Error(codes.NotFound, "nothing here")
+	})
+	fake.SetRouteSummary(&pb.RouteSummary{PointCount: 42})
+	fake.OnRouteChat(func(note *pb.RouteNode) []*pb.RouteNode {
+		return []*pb.RouteNode{note, {Location: note.Location, Message: "re: " + note.Message}}
+	})
+	c := client.New(routeguidetest.Dial(t, fake))
+	ctx := context.Background()
+
+	if _, err := c.GetFeature(ctx, home); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetFeature() = %v, want ErrNotFound", err)
+	}
+	summary, err := c.RecordRoute(ctx, []*pb.Point{home, office})
+	if err != nil || summary.GetPointCount() != 42 {
+		t.Errorf("RecordRoute() = %v, %v, want the scripted summary", summary, err)
+	}
+	if got := fake.Requests(routeguidetest.RecordRoute); len(got) != 2 {
+		t.Errorf("RecordRoute received %d points, want 2", len(got))
+	}
+
+	chat, err := c.RouteChat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chat.Close()
+	if err := chat.Send(&pb.RouteNode{Location: home, Message: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	chat.CloseSend()
+	var got []string
+	for note := range chat.Notes() {
+		got = append(got, note.GetMessage())
+	}
+	if len(got) != 2 || got[1] != "re: hi" {
+		t.Errorf("RouteChat replies = %q, want [hi re: hi]", got)
+	}
+}
+
+func TestComputedRouteSummary(t *testing.T) {
+	fake := newFake()
+	c := client.New(routeguidetest.Dial(t, fake))
+	summary, err := c.RecordRoute(context.Background(), []*pb.Point{home, {Latitude: 30000000}, office})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.PointCount != 3 || summary.FeatureCount != 2 || summary.Distance <= 0 {
+		t.Errorf("RecordRoute() = %v, want 3 points, 2 features and a distance", summary)
+	}
+}
+
+func TestFailAtNthMessage(t *testing.T) {
+	fake := newFake()
+	fake.FailAt(routeguidetest.ListFeatures, 2, status.Error(codes.Internal, "boom"))
+	fake.FailAt(routeguidetest.RecordRoute, 1, status.Error(codes.ResourceExhausted, "full"))
+	fake.FailAt(routeguidetest.GetFeature, 2, status.Error(codes.Unavailable, "flaky"))
+	c := client.New(routeguidetest.Dial(t, fake))
+	ctx := context.Background()
+
+	var got []string
+	err := c.ListFeatures(ctx, world, func(f *pb.Feature) error {
+		got = append(got, f.GetName())
+		return nil
+	})
+	if !errors.Is(err, client.ErrInternal) || len(got) != 1 {
+		t.Errorf("ListFeatures() = %v after %v, want Internal after one feature", err, got)
+	}
+
+	if _, err := c.RecordRoute(ctx, []*pb.Point{home}); !errors.Is(err, client.ErrResourceExhausted) {
+		t.Errorf("RecordRoute() = %v, want ResourceExhausted", err)
+	}
+
+	// 只有第二次 GetFeature 调用失败
+	for i, want := range []error{nil, client.ErrUnavailable, nil} {
+		if _, err := c.GetFeature(ctx, home); (want == nil && err != nil) || (want != nil && !errors.Is(err, want)) {
+			t.Errorf("GetFeature() call %d = %v, want %v", i+1, err, want)
+		}
+	}
+
+	calls := fake.Calls()
+	if code := status.Code(calls[0].Err); code != codes.Internal {
+		t.Errorf("recorded error = %v, want Internal", calls[0].Err)
+	}
+
+	fake.FailAt(routeguidetest.ListFeatures, 0, nil)
+	if err := c.ListFeatures(ctx, world, func(*pb.Feature) error { return nil }); err != nil {
+		t.Errorf("ListFeatures() after clearing the fault = %v", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	fake := newFake()
+	fake.SetLatency(routeguidetest.GetFeature, 200*time.Millisecond)
+	c := routeguidetest.NewClient(t, fake)
+
+	start := time.Now()
+	if _, err := c.GetFeature(context.Background(), home); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("GetFeature() took %v, want at least 200ms", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetFeature(ctx, home); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("GetFeature() with a short deadline = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestQueryFeatures(t *testing.T) {
+	fake := newFake()
+	fake.FailAt(routeguidetest.ListFeaturesPage, 2, status.Error(codes.Unavailable, "flaky"))
+	c := client.New(routeguidetest.Dial(t, fake))
+	ctx := context.Background()
+
+	req := &pb.ListFeaturesRequest{
+		Area:     &pb.ListFeaturesRequest_Rect{Rect: world},
+		PageSize: 1,
+		OrderBy:  pb.ListFeaturesRequest_DISTANCE,
+		Origin:   office,
+	}
+	it := c.QueryFeatures(ctx, req)
+	defer it.Close()
+	var names []string
+	for it.Next() {
+		names = append(names, it.Feature().GetName())
+	}
+	if it.Err() != nil || len(names) != 1 || names[0] != "office" || it.TotalCount() != 2 || it.NextPageToken() == "" {
+		t.Fatalf("QueryFeatures() = %v, %v, total %d, want office and a next page", names, it.Err(), it.TotalCount())
+	}
+
+	req = proto.Clone(req).(*pb.ListFeaturesRequest)
+	req.PageToken = it.NextPageToken()
+	page, err := c.ListFeaturesPage(ctx, req)
+	if err != nil || len(page.Features) != 1 || page.Features[0].GetName() != "home" || page.NextPageToken != "" {
+		t.Errorf("ListFeaturesPage() = %v, %v, want the last page with home", page, err)
+	}
+	if _, err := c.ListFeaturesPage(ctx, req); !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("second ListFeaturesPage() = %v, want ErrUnavailable", err)
+	}
+
+	fake.OnQueryFeatures(func(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error) {
+		return &pb.ListFeaturesResponse{Features: []*pb.Feature{{Name: "scripted", Location: home}}, TotalSize: 7}, nil
+	})
+	it = c.QueryFeatures(ctx, &pb.ListFeaturesRequest{})
+	defer it.Close()
+	if !it.Next() || it.Feature().GetName() != "scripted" || it.TotalCount() != 7 {
+		t.Errorf("scripted QueryFeatures() = %v, total %d, want the scripted feature", it.Feature(), it.TotalCount())
+	}
+
+	if got := fake.Requests(routeguidetest.QueryFeatures); len(got) != 2 {
+		t.Errorf("Requests(QueryFeatures) = %v, want 2 requests", got)
+	}
+	if got := fake.Requests(routeguidetest.ListFeaturesPage); len(got) != 2 || !proto.Equal(got[0], req) {
+		t.Errorf("Requests(ListFeaturesPage) = %v, want the second page request twice", got)
+	}
+}
+
+func TestScriptedGeofences(t *testing.T) {
+	fake := newFake()
+	fake.OnMonitorGeofences(func(pos *pb.GeofencePosition) []*pb.GeofenceEvent {
+		return []*pb.GeofenceEvent{{Type: pb.GeofenceEvent_ENTER, Feature: &pb.Feature{Name: "scripted", Location: pos.Location}}}
+	})
+	c := client.New(routeguidetest.Dial(t, fake))
+
+	s, err := c.MonitorGeofences(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Send(&pb.GeofencePosition{Location: office, Options: &pb.GeofenceOptions{RadiusMeters: 10}})
+	s.CloseSend()
+	var got []string
+	for ev := range s.Events() {
+		got = append(got, ev.Feature.GetName())
+	}
+	if len(got) != 1 || got[0] != "scripted" || s.Err() != nil {
+		t.Errorf("events = %v, Err() = %v, want the scripted event", got, s.Err())
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Method != routeguidetest.MonitorGeofences {
+		t.Fatalf("Calls() = %v, want one MonitorGeofences call", calls)
+	}
+	if got := fake.Requests(routeguidetest.MonitorGeofences); len(got) != 1 || got[0].(*pb.GeofencePosition).GetOptions().GetRadiusMeters() != 10 {
+		t.Errorf("Requests(MonitorGeofences) = %v, want the position with its options", got)
+	}
+}
+
+func TestSearchFeatures(t *testing.T) {
+	fake := newFake()
+	fake.FailAt(routeguidetest.SearchFeatures, 2, status.Error(codes.Unavailable, "flaky"))
+	c := client.New(routeguidetest.Dial(t, fake))
+	ctx := context.Background()
+
+	req := &pb.SearchFeaturesRequest{Query: "off"}
+	resp, err := c.SearchFeatures(ctx, req)
+	if err != nil || len(resp.Results) != 1 || resp.Results[0].Feature.GetName() != "office" {
+		t.Fatalf("SearchFeatures(off) = %v, %v, want office", resp, err)
+	}
+	if _, err := c.SearchFeatures(ctx, req); !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("second SearchFeatures() = %v, want ErrUnavailable", err)
+	}
+
+	fake.OnSearchFeatures(func(ctx context.Context, req *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error) {
+		return &pb.SearchFeaturesResponse{Completions: []string{req.Query + "ice"}}, nil
+	})
+	if resp, err := c.SearchFeatures(ctx, req); err != nil || len(resp.Completions) != 1 || resp.Completions[0] != "office" {
+		t.Errorf("scripted SearchFeatures() = %v, %v, want the scripted completion", resp, err)
+	}
+	if got := fake.Requests(routeguidetest.SearchFeatures); len(got) != 3 || !proto.Equal(got[2], req) {
+		t.Errorf("Requests(SearchFeatures) = %v, want 3 requests", got)
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	fake := routeguidetest.NewServer()
+	fake.SetFeatures(&pb.Feature{Name: "1 Main St, Trenton, NJ 08608", Location: home})
+	c := client.New(routeguidetest.Dial(t, fake))
+	ctx := context.Background()
+
+	resp, err := c.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: &pb.Point{Latitude: home.Latitude + 1000, Longitude: home.Longitude}})
+	if err != nil || resp.Address.GetCity() != "Trenton" || resp.Address.GetPostalCode() != "08608" || resp.DistanceMeters == 0 {
+		t.Fatalf("ReverseGeocode() = %v, %v, want the address of the feature", resp, err)
+	}
+	if _, err := c.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: office}); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("ReverseGeocode() far away = %v, want ErrNotFound", err)
+	}
+
+	fake.OnReverseGeocode(func(ctx context.Context, req *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error) {
+		return &pb.ReverseGeocodeResponse{Address: &pb.Address{Country: "US"}}, nil
+	})
+	fake.FailAt(routeguidetest.ReverseGeocode, 4, status.Error(codes.Unavailable, "flaky"))
+	if resp, err := c.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: office}); err != nil || resp.Address.GetCountry() != "US" {
+		t.Errorf("scripted ReverseGeocode() = %v, %v, want the scripted address", resp, err)
+	}
+	if _, err := c.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: office}); !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("fourth ReverseGeocode() = %v, want ErrUnavailable", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 4 || calls[1].Method != routeguidetest.ReverseGeocode || status.Code(calls[1].Err) != codes.NotFound {
+		t.Errorf("Calls() = %v, want 4 ReverseGeocode calls with NotFound recorded", calls)
+	}
+}
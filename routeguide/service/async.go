@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// async 模式使用的 metadata
+const (
+	// AsyncKey 为 true 时服务端独立于客户端发送消息：回答可以延迟、一个问题可以有多个回答，还会定时发送心跳。
+	// 回答的内容仍然由 ResponderKey 选择的方式生成，DelayKey 对所有的回答方式都生效
+	AsyncKey = "x-echo-async"
+	// AnswersKey 是每个问题的回答数量，默认是 1，第 k 个回答在收到问题 k 个 DelayKey 之后发送
+	AnswersKey = "x-echo-answers"
+	// HeartbeatKey 是心跳的间隔，例如 1s，为空时不发送心跳
+	HeartbeatKey = "x-echo-heartbeat"
+)
+
+const (
+	maxAnswers   = 100
+	minHeartbeat = 10 * time.Millisecond
+	// maxPendingAnswers 是还没有发送的回答的数量上限，超过之后服务端停止接收问题，
+	// 直到客户端读取了回答，这样不读取回答的客户端会被流控限制住，而不是让服务端的内存无限增长
+	maxPendingAnswers = 256
+)
+
+// asyncOptions 是 async 模式的参数
+type asyncOptions struct {
+	answers   int
+	delay     time.Duration
+	heartbeat time.Duration
+}
+
+func parseAsyncOptions(md metadata.MD) (asyncOptions, error) {
+	o := asyncOptions{answers: 1}
+	if v := firstValue(md, AnswersKey); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAnswers {
+			return o, status.Errorf(codes.InvalidArgument, "%s: want 1 to %d, got %q", AnswersKey, maxAnswers, v)
+		}
+		o.answers = n
+	}
+	if v := firstValue(md, DelayKey); v != "" {
+		d, err := parseDelay(v)
+		if err != nil {
+			return o, status.Errorf(codes.InvalidArgument, "%s: %v", DelayKey, err)
+		}
+		o.delay = d
+	}
+	if v := firstValue(md, HeartbeatKey); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < minHeartbeat {
+			return o, status.Errorf(codes.InvalidArgument, "%s: want a duration of at least %v, got %q", HeartbeatKey, minHeartbeat, v)
+		}
+		o.heartbeat = d
+	}
+	return o, nil
+}
+
+// asyncConversation 在单独的 goroutine 中接收问题，每个问题的回答由 sendParts 按时放入 out，
+// 处理函数的 goroutine 负责发送回答和心跳。客户端结束发送之后，等所有的回答都发送完再结束调用
+func (s *Echo) asyncConversation(stream pb.Echo_ConversationsServer, md metadata.MD, r *responder, conv *conversation) error {
+	o, err := parseAsyncOptions(md)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	out := make(chan *pb.StreamResponse)
+	// slots 中的每个元素是一个还没有发送的回答
+	slots := make(chan struct{}, maxPendingAnswers)
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- s.receiveAsync(ctx, stream, r, conv, o, out, slots)
+	}()
+
+	var heartbeat <-chan time.Time
+	if o.heartbeat > 0 {
+		ticker := time.NewTicker(o.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	received := false
+	for !received || len(slots) > 0 {
+		select {
+		case resp := <-out:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			<-slots
+		case t := <-heartbeat:
+			if err := stream.Send(&pb.StreamResponse{Heartbeat: timestamppb.New(t)}); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != nil {
+				return err
+			}
+			received = true
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	return nil
+}
+
+// receiveAsync 接收所有的问题并安排回答，客户端结束发送或者脚本结束时返回 nil
+func (s *Echo) receiveAsync(ctx context.Context, stream pb.Echo_ConversationsServer, r *responder, conv *conversation,
+	o asyncOptions, out chan<- *pb.StreamResponse, slots chan struct{}) error {
+	for n := int64(1); ; n++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.Room != "" {
+			return status.Errorf(codes.InvalidArgument, "rooms can not be used with %s", AsyncKey)
+		}
+
+		conv.mu.Lock()
+		conv.n++
+		answer, done, err := r.reply(conv, req.Question)
+		conv.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		for part := 1; part <= o.answers; part++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		go sendParts(ctx, out, answer, n, o, time.Now())
+		LoggerFrom(ctx).Debug("from stream client question", "question", req.Question, "answers", o.answers)
+		if done {
+			return nil
+		}
+	}
+}
+
+// sendParts 按顺序发送一个问题的所有回答，第 k 个回答在 received 之后 k 个 delay 发送。
+// 同一个问题的回答只由一个 goroutine 发送，延迟为 0 或者相同时也不会乱序
+func sendParts(ctx context.Context, out chan<- *pb.StreamResponse, answer string, n int64, o asyncOptions, received time.Time) {
+	for part := 1; part <= o.answers; part++ {
+		timer := time.NewTimer(time.Until(received.Add(time.Duration(part) * o.delay)))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		select {
+		case out <- &pb.StreamResponse{Answer: answer, ReplyTo: n, Part: int32(part)}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAsyncMultipleAnswers(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	md := metadata.Pairs(service.AsyncKey, "true", service.AnswersKey, "3", service.ResponderKey, service.ResponderReverse)
+	stream, err := env.Echo.Conversations(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	for _, q := range []string{"ab", "cd"} {
+		if err := stream.Send(&pb.StreamRequest{Question: q}); err != nil {
+			t.Fatalf("Send() = %v", err)
+		}
+	}
+	stream.CloseSend()
+
+	// 不同问题的回答之间没有顺序保证，同一个问题的回答按 Part 的顺序发送
+	got := make(map[int64][]string)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		got[resp.ReplyTo] = append(got[resp.ReplyTo], fmt.Sprintf("%d %s", resp.Part, resp.Answer))
+	}
+	want := map[int64][]string{1: {"1 ba", "2 ba", "3 ba"}, 2: {"1 dc", "2 dc", "3 dc"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("answers = %q, want %q", got, want)
+	}
+}
+
+// 延迟的回答不影响服务端接收后面的问题，后发的问题可以先得到回答
+func TestAsyncDelayedAnswers(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	md := metadata.Pairs(service.AsyncKey, "true", service.ResponderKey, service.ResponderEcho, service.DelayKey, "300ms")
+	stream, err := env.Echo.Conversations(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	start := time.Now()
+	for _, q := range []string{"a", "b", "c"} {
+		if err := stream.Send(&pb.StreamRequest{Question: q}); err != nil {
+			t.Fatalf("Send() = %v", err)
+		}
+	}
+	stream.CloseSend()
+
+	n := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		n++
+	}
+	elapsed := time.Since(start)
+	if n != 3 {
+		t.Errorf("got %d answers, want 3", n)
+	}
+	if elapsed < 300*time.Millisecond || elapsed > 800*time.Millisecond {
+		t.Errorf("call took %v, want the delays to overlap", elapsed)
+	}
+}
+
+func TestAsyncHeartbeat(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	md := metadata.Pairs(service.AsyncKey, "true", service.HeartbeatKey, "20ms")
+	stream, err := env.Echo.Conversations(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	// 客户端不发送问题，服务端也会主动发送消息
+	for i := 0; i < 3; i++ {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		if resp.Heartbeat == nil || resp.Answer != "" {
+			t.Errorf("Recv() = %v, want a heartbeat", resp)
+		}
+	}
+	if err := stream.Send(&pb.StreamRequest{Question: "q"}); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+	stream.CloseSend()
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		if resp.Heartbeat == nil && resp.Answer != "Answer: 1, Question: q" {
+			t.Errorf("answer = %q", resp.Answer)
+		}
+	}
+}
+
+func TestAsyncInvalidMetadata(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	for _, md := range []metadata.MD{
+		metadata.Pairs(service.AsyncKey, "true", service.AnswersKey, "0"),
+		metadata.Pairs(service.AsyncKey, "true", service.AnswersKey, "1000"),
+		metadata.Pairs(service.AsyncKey, "true", service.HeartbeatKey, "1ms"),
+		metadata.Pairs(service.AsyncKey, "true", service.HeartbeatKey, "soon"),
+		metadata.Pairs(service.AsyncKey, "true", service.DelayKey, "-1"),
+	} {
+		_, err := converse(ctx, t, env.Echo, md, "q")
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Conversations(%v) = %v, want InvalidArgument", md, err)
+		}
+	}
+
+	stream, err := env.Echo.Conversations(metadata.NewOutgoingContext(ctx, metadata.Pairs(service.AsyncKey, "true")))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	stream.Send(&pb.StreamRequest{Question: "q", Room: "r"})
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Recv() with a room = %v, want InvalidArgument", err)
+	}
+}
+
+// 脚本结束之后服务端不再接收问题，但已经安排的回答仍然会发送
+func TestAsyncScriptEnd(t *testing.T) {
+	env := servicetest.Start(t)
+	env.EchoService.SetScripts(loadTestScripts(t))
+	ctx := testContext(t)
+
+	md := metadata.Pairs(service.AsyncKey, "true", service.AnswersKey, "2", service.ResponderKey, service.ResponderScript, service.ScriptKey, "greeting")
+	answers, err := converse(ctx, t, env.Echo, md, "hello", "bye")
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	if len(answers) != 4 {
+		t.Errorf("answers = %q, want 2 answers to each question", answers)
+	}
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Conversations 使用的 metadata，客户端在调用开始时设置
+const (
+	// ResponderKey 选择回答问题的方式，见下面的 Responder 常量，默认是 ResponderDefault
+	ResponderKey = "x-echo-responder"
+	// ConversationIDKey 是会话 ID，ID 相同的调用共享计数器和脚本的状态，没有设置时每个调用是一个新的会话
+	ConversationIDKey = "x-conversation-id"
+	// DelayKey 是 ResponderDelay 的延迟，例如 250ms，只有数字时单位是毫秒
+	DelayKey = "x-echo-delay"
+	// TemplateKey 是 ResponderTemplate 使用的 text/template 模板，字段见 replyData
+	TemplateKey = "x-echo-template"
+	// ScriptKey 是 ResponderScript 使用的脚本的名字，脚本由 Echo.SetScripts 设置
+	ScriptKey = "x-echo-script"
+)
+
+// Conversations 支持的回答方式
+const (
+	// ResponderDefault 回答 Answer: n, Question: q
+	ResponderDefault = "default"
+	// ResponderEcho 原样返回问题
+	ResponderEcho = "echo"
+	// ResponderReverse 返回倒序的问题
+	ResponderReverse = "reverse"
+	// ResponderDelay 等待 DelayKey 指定的时间之后原样返回问题
+	ResponderDelay = "delay"
+	// ResponderTemplate 使用 TemplateKey 指定的模板生成回答
+	ResponderTemplate = "template"
+	// ResponderScript 按照 ScriptKey 指定的脚本回答，见 Script
+	ResponderScript = "script"
+	// ResponderCounter 返回会话中问题的序号，需要设置 ConversationIDKey，序号在同一个会话的多次调用之间累加
+	ResponderCounter = "counter"
+)
+
+// maxDelay 是 ResponderDelay 允许的最长延迟
+const maxDelay = time.Minute
+
+// 会话的默认限制，见 Echo.SetConversationLimits
+const (
+	DefaultMaxConversations = 10000
+	DefaultConversationTTL  = time.Hour
+)
+
+// Echo 实现了 Echo 服务
+type Echo struct {
+	pb.UnimplementedEchoServer
+
+	scriptsMu sync.RWMutex
+	scripts   map[string]*Script
+	// mu 保护 conversations 和会话的限制，会话保存在内存中，服务重启之后就没有了。
+	// lru 按最近使用的时间排列会话，最前面的是最近使用的
+	mu               sync.Mutex
+	conversations    map[string]*list.Element
+	lru              *list.List
+	maxConversations int
+	conversationTTL  time.Duration
+
+	// roomsMu 保护 rooms 和房间的限制，见 room.go
+	roomsMu         sync.Mutex
+	rooms           map[string]*room
+	maxParticipants int
+	roomHistory     int
+	// guests 用来给没有名字的参与者生成名字
+	guests int
+}
+
+// NewEcho 返回没有脚本的 Echo 服务，脚本通过 SetScripts 设置
+func NewEcho() *Echo {
+	return &Echo{
+		conversations:    make(map[string]*list.Element),
+		lru:              list.New(),
+		maxConversations: DefaultMaxConversations,
+		conversationTTL:  DefaultConversationTTL,
+		rooms:            make(map[string]*room),
+		maxParticipants:  DefaultMaxRoomParticipants,
+		roomHistory:      DefaultRoomHistory,
+	}
+}
+
+// SetScripts 替换 ResponderScript 使用的脚本
+func (s *Echo) SetScripts(scripts map[string]*Script) {
+	s.scriptsMu.Lock()
+	s.scripts = scripts
+	s.scriptsMu.Unlock()
+}
+
+func (s *Echo) script(name string) *Script {
+	s.scriptsMu.RLock()
+	defer s.scriptsMu.RUnlock()
+	return s.scripts[name]
+}
+
+// conversation 是一个会话的状态，同一个会话的多个调用可能并发使用它
+type conversation struct {
+	mu sync.Mutex
+	id string
+	// n 是会话中已经回答的问题的数量
+	n int
+	// script 和 state 是 ResponderScript 当前所在的脚本和状态
+	script string
+	state  string
+
+	// lastUsed 是最后一次取出会话的时间，由 Echo.mu 保护
+	lastUsed time.Time
+}
+
+// SetConversationLimits 设置保存的会话数量上限和会话的空闲时间上限，ttl 为 0 表示会话不会过期。
+// 超过上限时删除最久没有使用的会话，被删除的会话之后再使用时从头开始
+func (s *Echo) SetConversationLimits(max int, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxConversations, s.conversationTTL = max, ttl
+	s.evict(time.Now())
+}
+
+func (s *Echo) conversation(id string) *conversation {
+	if id == "" {
+		return &conversation{}
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict(now)
+	if e, ok := s.conversations[id]; ok {
+		s.lru.MoveToFront(e)
+		c := e.Value.(*conversation)
+		c.lastUsed = now
+		return c
+	}
+	c := &conversation{id: id, lastUsed: now}
+	s.conversations[id] = s.lru.PushFront(c)
+	s.evict(now)
+	return c
+}
+
+// evict 删除过期的会话和超过数量上限的会话，调用时需要持有 s.mu
+func (s *Echo) evict(now time.Time) {
+	for e := s.lru.Back(); e != nil; e = s.lru.Back() {
+		c := e.Value.(*conversation)
+		expired := s.conversationTTL > 0 && now.Sub(c.lastUsed) >= s.conversationTTL
+		if !expired && s.lru.Len() <= s.maxConversations {
+			return
+		}
+		s.lru.Remove(e)
+		delete(s.conversations, c.id)
+	}
+}
+
+// replyData 是 ResponderTemplate 和脚本中回答模板的数据
+type replyData struct {
+	Question       string
+	N              int
+	ConversationID string
+	State          string
+	// Groups 是脚本中 match 正则表达式匹配到的分组，Groups[0] 是整个问题
+	Groups []string
+}
+
+// responder 回答会话中的问题。reply 在持有 conversation.mu 时调用，done 为 true 时调用在发送回答之后结束；
+// delay 是每个回答之前的等待时间，等待时不持有锁
+type responder struct {
+	delay time.Duration
+	reply func(c *conversation, question string) (answer string, done bool, err error)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func echoReply(_ *conversation, q string) (string, bool, error) {
+	return q, false, nil
+}
+
+// newResponder 根据调用的 metadata 选择回答方式
+func (s *Echo) newResponder(md metadata.MD) (*responder, error) {
+	switch name := firstValue(md, ResponderKey); name {
+	case "", ResponderDefault:
+		return &responder{reply: func(c *conversation, q string) (string, bool, error) {
+			return fmt.Sprintf("Answer: %d, Question: %s", c.n, q), false, nil
+		}}, nil
+	case ResponderEcho:
+		return &responder{reply: echoReply}, nil
+	case ResponderReverse:
+		return &responder{reply: func(_ *conversation, q string) (string, bool, error) {
+			return reverse(q), false, nil
+		}}, nil
+	case ResponderDelay:
+		d, err := parseDelay(firstValue(md, DelayKey))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", DelayKey, err)
+		}
+		return &responder{delay: d, reply: echoReply}, nil
+	case ResponderTemplate:
+		tmpl, err := template.New("reply").Option("missingkey=error").Parse(firstValue(md, TemplateKey))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", TemplateKey, err)
+		}
+		return &responder{reply: func(c *conversation, q string) (string, bool, error) {
+			answer, err := execute(tmpl, replyData{Question: q, N: c.n, ConversationID: c.id})
+			if err != nil {
+				return "", false, status.Errorf(codes.InvalidArgument, "%s: %v", TemplateKey, err)
+			}
+			return answer, false, nil
+		}}, nil
+	case ResponderScript:
+		scriptName := firstValue(md, ScriptKey)
+		script := s.script(scriptName)
+		if script == nil {
+			return nil, status.Errorf(codes.NotFound, "script %q not found", scriptName)
+		}
+		return &responder{reply: func(c *conversation, q string) (string, bool, error) {
+			return script.respond(scriptName, c, q)
+		}}, nil
+	case ResponderCounter:
+		if firstValue(md, ConversationIDKey) == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "the %s responder requires %s", ResponderCounter, ConversationIDKey)
+		}
+		return &responder{reply: func(c *conversation, _ string) (string, bool, error) {
+			return strconv.Itoa(c.n), false, nil
+		}}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown responder %q", name)
+	}
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func parseDelay(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		ms, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid delay %q", s)
+		}
+		d = time.Duration(ms) * time.Millisecond
+	}
+	if d < 0 || d > maxDelay {
+		return 0, fmt.Errorf("delay %v out of range [0, %v]", d, maxDelay)
+	}
+	return d, nil
+}
+
+func execute(tmpl *template.Template, data replyData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Conversations 按照 metadata 选择的回答方式逐个回答客户端的问题，见 ResponderKey，AsyncKey 为 true 时回答是异步发送的，见 async.go。
+// 通过 RoomKey 或者第一条消息的 room 加入房间之后，问题会广播给房间中的所有参与者，见 room.go
+func (s *Echo) Conversations(stream pb.Echo_ConversationsServer) error {
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+	if id := firstValue(md, RoomKey); id != "" {
+		return s.roomConversation(stream, md, id, nil)
+	}
+	r, err := s.newResponder(md)
+	if err != nil {
+		return err
+	}
+	conv := s.conversation(firstValue(md, ConversationIDKey))
+	if async, _ := strconv.ParseBool(firstValue(md, AsyncKey)); async {
+		return s.asyncConversation(stream, md, r, conv)
+	}
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.Room != "" {
+			if !first {
+				return status.Error(codes.InvalidArgument, "room can only be set in the first message")
+			}
+			return s.roomConversation(stream, md, req.Room, req)
+		}
+
+		if err := sleep(ctx, r.delay); err != nil {
+			return err
+		}
+		conv.mu.Lock()
+		conv.n++
+		answer, done, err := r.reply(conv, req.Question)
+		conv.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.StreamResponse{Answer: answer}); err != nil {
+			return err
+		}
+		LoggerFrom(ctx).Debug("from stream client question", "question", req.Question)
+		if done {
+			return nil
+		}
+	}
+}
+
+// sleep 等待 d，ctx 先结束时返回对应的状态
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
@@ -0,0 +1,249 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// converse 在一个 Conversations 调用中依次发送 questions，返回收到的回答和调用结束的错误
+func converse(ctx context.Context, t *testing.T, client pb.EchoClient, md metadata.MD, questions ...string) ([]string, error) {
+	t.Helper()
+	stream, err := client.Conversations(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	var answers []string
+	for _, q := range questions {
+		if err := stream.Send(&pb.StreamRequest{Question: q}); err != nil {
+			// 服务端已经结束了调用，错误由 Recv 返回
+			break
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return answers, err
+		}
+		answers = append(answers, resp.Answer)
+	}
+	stream.CloseSend()
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return answers, nil
+		}
+		if err != nil {
+			return answers, err
+		}
+		answers = append(answers, resp.Answer)
+	}
+}
+
+func loadTestScripts(t *testing.T) map[string]*service.Script {
+	t.Helper()
+	scripts, err := service.LoadScripts("../../testdata/echo_scripts.yaml")
+	if err != nil {
+		t.Fatalf("LoadScripts() = %v", err)
+	}
+	return scripts
+}
+
+func TestEchoResponders(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	tests := []struct {
+		md   metadata.MD
+		want []string
+	}{
+		{metadata.Pairs(), []string{"Answer: 1, Question: ab", "Answer: 2, Question: 你好"}},
+		{metadata.Pairs(service.ResponderKey, service.ResponderDefault), []string{"Answer: 1, Question: ab", "Answer: 2, Question: 你好"}},
+		{metadata.Pairs(service.ResponderKey, service.ResponderEcho), []string{"ab", "你好"}},
+		{metadata.Pairs(service.ResponderKey, service.ResponderReverse), []string{"ba", "好你"}},
+		{metadata.Pairs(service.ResponderKey, service.ResponderDelay, service.DelayKey, "1"), []string{"ab", "你好"}},
+		{
+			metadata.Pairs(service.ResponderKey, service.ResponderTemplate, service.TemplateKey, "{{.N}}:{{.Question}}@{{.ConversationID}}", service.ConversationIDKey, "t1"),
+			[]string{"1:ab@t1", "2:你好@t1"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := converse(ctx, t, env.Echo, tt.md, "ab", "你好")
+		if err != nil {
+			t.Errorf("Conversations(%v) = %v", tt.md, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Conversations(%v) = %q, want %q", tt.md, got, tt.want)
+		}
+	}
+}
+
+func TestEchoDelay(t *testing.T) {
+	env := servicetest.Start(t)
+	md := metadata.Pairs(service.ResponderKey, service.ResponderDelay, service.DelayKey, "50ms")
+
+	start := time.Now()
+	if _, err := converse(testContext(t), t, env.Echo, md, "a", "b"); err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("2 delayed answers took %v, want >= 100ms", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := converse(ctx, t, env.Echo, md, "a"); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Conversations() with a short deadline = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestEchoCounterPersistsPerConversation(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+	counter := func(id string) metadata.MD {
+		return metadata.Pairs(service.ResponderKey, service.ResponderCounter, service.ConversationIDKey, id)
+	}
+
+	if got, _ := converse(ctx, t, env.Echo, counter("a"), "x", "y"); strings.Join(got, ",") != "1,2" {
+		t.Errorf("first call in a = %v, want [1 2]", got)
+	}
+	if got, _ := converse(ctx, t, env.Echo, counter("a"), "z"); strings.Join(got, ",") != "3" {
+		t.Errorf("second call in a = %v, want [3]", got)
+	}
+	if got, _ := converse(ctx, t, env.Echo, counter("b"), "z"); strings.Join(got, ",") != "1" {
+		t.Errorf("first call in b = %v, want [1]", got)
+	}
+
+	_, err := converse(ctx, t, env.Echo, metadata.Pairs(service.ResponderKey, service.ResponderCounter), "x")
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("counter without a conversation ID = %v, want InvalidArgument", err)
+	}
+}
+
+func TestEchoConversationEviction(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+	counter := func(id string) metadata.MD {
+		return metadata.Pairs(service.ResponderKey, service.ResponderCounter, service.ConversationIDKey, id)
+	}
+	count := func(id string) string {
+		t.Helper()
+		got, err := converse(ctx, t, env.Echo, counter(id), "x")
+		if err != nil {
+			t.Fatalf("converse(%s) = %v", id, err)
+		}
+		return strings.Join(got, ",")
+	}
+
+	// 最多保存两个会话，c 加入后最久没有使用的 b 被删除，a 刚刚用过所以保留
+	env.EchoService.SetConversationLimits(2, 0)
+	count("a")
+	count("b")
+	count("a")
+	count("c")
+	if got := count("a"); got != "3" {
+		t.Errorf("a after evicting b = %s, want 3", got)
+	}
+	if got := count("b"); got != "1" {
+		t.Errorf("evicted b = %s, want it to start over at 1", got)
+	}
+
+	env.EchoService.SetConversationLimits(2, 50*time.Millisecond)
+	if got := count("b"); got != "2" {
+		t.Errorf("b before expiring = %s, want 2", got)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := count("b"); got != "1" {
+		t.Errorf("expired b = %s, want it to start over at 1", got)
+	}
+}
+
+func TestEchoScript(t *testing.T) {
+	env := servicetest.Start(t)
+	env.EchoService.SetScripts(loadTestScripts(t))
+	ctx := testContext(t)
+	md := metadata.Pairs(service.ResponderKey, service.ResponderScript, service.ScriptKey, "greeting")
+
+	// end 之后调用结束，后面的问题不会被回答
+	got, err := converse(ctx, t, env.Echo, md, "yo", "Hi there", "Gopher", "how are you", "bye", "ignored")
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	want := []string{
+		"say hi first",
+		"hello, what is your name?",
+		"nice to meet you, Gopher",
+		"you said: how are you",
+		"bye after 5 messages",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Conversations() = %q, want %q", got, want)
+	}
+
+	// 有会话 ID 时状态在调用之间保留
+	md.Set(service.ConversationIDKey, "s1")
+	if _, err := converse(ctx, t, env.Echo, md, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = converse(ctx, t, env.Echo, md, "Ada")
+	if len(got) != 1 || got[0] != "nice to meet you, Ada" {
+		t.Errorf("resumed conversation = %q, want the name state", got)
+	}
+
+	// name 状态中空白的回答没有匹配的 transition
+	md.Set(service.ConversationIDKey, "s2")
+	_, err = converse(ctx, t, env.Echo, md, "hello", "   ")
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("unmatched question = %v, want FailedPrecondition", err)
+	}
+
+	md.Set(service.ScriptKey, "nope")
+	if _, err := converse(ctx, t, env.Echo, md, "hi"); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown script = %v, want NotFound", err)
+	}
+}
+
+func TestEchoInvalidMetadata(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+	for _, md := range []metadata.MD{
+		metadata.Pairs(service.ResponderKey, "nope"),
+		metadata.Pairs(service.ResponderKey, service.ResponderDelay, service.DelayKey, "soon"),
+		metadata.Pairs(service.ResponderKey, service.ResponderDelay, service.DelayKey, "2h"),
+		metadata.Pairs(service.ResponderKey, service.ResponderTemplate, service.TemplateKey, "{{.Question"),
+		metadata.Pairs(service.ResponderKey, service.ResponderTemplate, service.TemplateKey, "{{.Nope}}"),
+	} {
+		if _, err := converse(ctx, t, env.Echo, md, "q"); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Conversations(%v) = %v, want InvalidArgument", md, err)
+		}
+	}
+}
+
+func TestLoadScriptsErrors(t *testing.T) {
+	tests := map[string]string{
+		"start state":     "scripts:\n  s:\n    start: nope\n    states:\n      a: []\n",
+		"next state":      "scripts:\n  s:\n    start: a\n    states:\n      a:\n        - next: nope\n",
+		"error parsing":   "scripts:\n  s:\n    start: a\n    states:\n      a:\n        - match: \"(\"\n",
+		"unclosed action": "scripts:\n  s:\n    start: a\n    states:\n      a:\n        - reply: \"{{.N\"\n",
+	}
+	for want, content := range tests {
+		path := filepath.Join(t.TempDir(), "scripts.yaml")
+		if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.LoadScripts(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadScripts(%q) = %v, want an error containing %q", content, err, want)
+		}
+	}
+}
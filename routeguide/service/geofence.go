@@ -0,0 +1,155 @@
+package service
+
+import (
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MonitorGeofences 的默认参数，见 pb.GeofenceOptions
+const (
+	DefaultGeofenceRadius = 100.0
+	DefaultGeofenceDwell  = time.Minute
+	// exitRadiusFactor 是没有设置 exit_radius_meters 时离开范围的距离和进入范围的距离的比例
+	exitRadiusFactor = 1.2
+	// metersPerLatitude 是经线上 1 个 E7 单位的长度的下限，用来跳过纬度相差太远的特征
+	metersPerLatitude = 0.0111
+)
+
+type geofenceOptions struct {
+	radius     float64
+	exitRadius float64
+	dwell      time.Duration
+}
+
+func parseGeofenceOptions(o *pb.GeofenceOptions) (geofenceOptions, error) {
+	opts := geofenceOptions{radius: o.GetRadiusMeters(), exitRadius: o.GetExitRadiusMeters(), dwell: DefaultGeofenceDwell}
+	if opts.radius == 0 {
+		opts.radius = DefaultGeofenceRadius
+	}
+	if opts.exitRadius == 0 {
+		opts.exitRadius = opts.radius * exitRadiusFactor
+	}
+	if opts.exitRadius < opts.radius {
+		return opts, status.Errorf(codes.InvalidArgument, "exit_radius_meters %v is less than radius_meters %v", opts.exitRadius, opts.radius)
+	}
+	if d := o.GetDwell(); d != nil {
+		if err := d.CheckValid(); err != nil || d.AsDuration() < 0 {
+			return opts, status.Errorf(codes.InvalidArgument, "invalid dwell %v", d)
+		}
+		if d.AsDuration() > 0 {
+			opts.dwell = d.AsDuration()
+		}
+	}
+	return opts, nil
+}
+
+// fence 是当前位置所在的一个特征的范围
+type fence struct {
+	feature *pb.Feature
+	entered time.Time
+	dwelled bool
+}
+
+// MonitorGeofences 在收到每个位置之后发送这个位置触发的事件，客户端结束发送时调用结束
+func (s *RouteGuide) MonitorGeofences(stream pb.RouteGuide_MonitorGeofencesServer) error {
+	var opts geofenceOptions
+	fences := make(map[string]*fence)
+	for first := true; ; first = false {
+		pos, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			if opts, err = parseGeofenceOptions(pos.Options); err != nil {
+				return err
+			}
+		}
+		t := time.Now()
+		if pos.Time != nil {
+			t = pos.Time.AsTime()
+		}
+		for _, event := range updateFences(fences, s.features(), pos.Location, t, opts) {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// updateFences 用新的位置 loc 更新 fences，返回需要发送的事件，同一个位置的事件按特征数据库的顺序排列。
+// 位置在 fences 中的特征被从特征数据库中删除时也会发送 EXIT 事件
+func updateFences(fences map[string]*fence, features []*pb.Feature, loc *pb.Point, t time.Time, opts geofenceOptions) []*pb.GeofenceEvent {
+	var events []*pb.GeofenceEvent
+	event := func(typ pb.GeofenceEvent_Type, f *fence, distance int32) {
+		dwell := t.Sub(f.entered)
+		if dwell < 0 {
+			dwell = 0
+		}
+		events = append(events, &pb.GeofenceEvent{
+			Type:           typ,
+			Feature:        f.feature,
+			Location:       loc,
+			Time:           timestamppb.New(t),
+			DistanceMeters: distance,
+			Dwell:          durationpb.New(dwell),
+		})
+	}
+
+	seen := make(map[string]bool, len(fences))
+	for _, feature := range features {
+		key := fenceKey(feature)
+		f, inside := fences[key]
+		if !inside && math.Abs(float64(loc.GetLatitude())-float64(feature.GetLocation().GetLatitude()))*metersPerLatitude > opts.exitRadius {
+			continue
+		}
+		distance := geo.Distance(loc, feature.Location)
+		switch {
+		case inside:
+			seen[key] = true
+			if float64(distance) > opts.exitRadius {
+				event(pb.GeofenceEvent_EXIT, f, distance)
+				delete(fences, key)
+			} else if !f.dwelled && t.Sub(f.entered) >= opts.dwell {
+				f.dwelled = true
+				event(pb.GeofenceEvent_DWELL, f, distance)
+			}
+		case float64(distance) <= opts.radius:
+			f := &fence{feature: feature, entered: t}
+			fences[key] = f
+			seen[key] = true
+			event(pb.GeofenceEvent_ENTER, f, distance)
+		}
+	}
+
+	var removed []string
+	for key := range fences {
+		if !seen[key] {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		f := fences[key]
+		event(pb.GeofenceEvent_EXIT, f, geo.Distance(loc, f.feature.Location))
+		delete(fences, key)
+	}
+	return events
+}
+
+// fenceKey 区分同一个位置上名字不同的特征
+func fenceKey(feature *pb.Feature) string {
+	return geo.Serialize(feature.Location) + " " + feature.Name
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// north 返回 p 以北 meters 米的点
+func north(p *pb.Point, meters float64) *pb.Point {
+	return &pb.Point{Latitude: p.Latitude + int32(meters/0.011119), Longitude: p.Longitude}
+}
+
+type geofenceStep struct {
+	location *pb.Point
+	// after 是这个位置和第一个位置之间的时间
+	after time.Duration
+	// want 是这个位置触发的事件，例如 "ENTER Patriots Path"
+	want []string
+}
+
+// monitor 依次发送 steps 中的位置，检查每个位置触发的事件
+func monitor(t *testing.T, env *servicetest.Env, options *pb.GeofenceOptions, steps []geofenceStep) {
+	t.Helper()
+	stream, err := env.RouteGuide.MonitorGeofences(testContext(t))
+	if err != nil {
+		t.Fatalf("MonitorGeofences() = %v", err)
+	}
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i, step := range steps {
+		pos := &pb.GeofencePosition{Location: step.location, Time: timestamppb.New(start.Add(step.after))}
+		if i == 0 {
+			pos.Options = options
+		}
+		if err := stream.Send(pos); err != nil {
+			t.Fatalf("Send() = %v", err)
+		}
+		// 多余的事件会在下一步或者流结束时被发现
+		var got []string
+		for range step.want {
+			ev, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("step %d: Recv() = %v", i, err)
+			}
+			got = append(got, fmt.Sprintf("%s %s", ev.Type, ev.Feature.GetName()))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(step.want) {
+			t.Errorf("step %d: events = %q, want %q", i, got, step.want)
+		}
+	}
+	stream.CloseSend()
+	if ev, err := stream.Recv(); err != io.EOF {
+		t.Errorf("unexpected event %v, %v at the end of the stream", ev, err)
+	}
+}
+
+func TestMonitorGeofences(t *testing.T) {
+	mendham := &pb.Feature{Name: "Mendham", Location: servicetest.Features()[0].Location}
+	env := servicetest.Start(t, servicetest.WithFeatures([]*pb.Feature{mendham}))
+	p := mendham.Location
+
+	monitor(t, env, nil, []geofenceStep{
+		{location: north(p, 1000)},
+		{location: north(p, 50), after: time.Second, want: []string{"ENTER Mendham"}},
+		// 进入和离开的距离之间的位置不改变状态
+		{location: north(p, 110), after: 2 * time.Second},
+		{location: north(p, 90), after: 3 * time.Second},
+		{location: north(p, 115), after: 4 * time.Second},
+		{location: north(p, 130), after: 5 * time.Second, want: []string{"EXIT Mendham"}},
+		{location: north(p, 110), after: 6 * time.Second},
+		{location: p, after: 7 * time.Second, want: []string{"ENTER Mendham"}},
+	})
+}
+
+func TestMonitorGeofencesDwell(t *testing.T) {
+	mendham := &pb.Feature{Name: "Mendham", Location: servicetest.Features()[0].Location}
+	env := servicetest.Start(t, servicetest.WithFeatures([]*pb.Feature{mendham}))
+	p := mendham.Location
+
+	options := &pb.GeofenceOptions{RadiusMeters: 500, ExitRadiusMeters: 1000, Dwell: durationpb.New(time.Minute)}
+	monitor(t, env, options, []geofenceStep{
+		{location: north(p, 400), want: []string{"ENTER Mendham"}},
+		{location: north(p, 900), after: 30 * time.Second},
+		{location: north(p, 10), after: 61 * time.Second, want: []string{"DWELL Mendham"}},
+		{location: north(p, 10), after: 90 * time.Second},
+		{location: north(p, 1100), after: 120 * time.Second, want: []string{"EXIT Mendham"}},
+	})
+}
+
+func TestMonitorGeofencesFeatureRemoved(t *testing.T) {
+	features := servicetest.Features()
+	env := servicetest.Start(t)
+	p := features[0].Location
+
+	stream, err := env.RouteGuide.MonitorGeofences(testContext(t))
+	if err != nil {
+		t.Fatalf("MonitorGeofences() = %v", err)
+	}
+	stream.Send(&pb.GeofencePosition{Location: p})
+	if ev, err := stream.Recv(); err != nil || ev.Type != pb.GeofenceEvent_ENTER || ev.DistanceMeters != 0 {
+		t.Fatalf("Recv() = %v, %v, want ENTER", ev, err)
+	}
+	env.Service.SetFeatures(features[1:])
+	stream.Send(&pb.GeofencePosition{Location: p})
+	ev, err := stream.Recv()
+	if err != nil || ev.Type != pb.GeofenceEvent_EXIT || ev.Feature.GetName() != features[0].Name {
+		t.Fatalf("Recv() = %v, %v, want EXIT %s", ev, err, features[0].Name)
+	}
+}
+
+func TestMonitorGeofencesInvalidOptions(t *testing.T) {
+	env := servicetest.Start(t)
+
+	for _, options := range []*pb.GeofenceOptions{
+		{RadiusMeters: 100, ExitRadiusMeters: 50},
+		{ExitRadiusMeters: 50},
+		{Dwell: durationpb.New(-time.Second)},
+	} {
+		stream, err := env.RouteGuide.MonitorGeofences(testContext(t))
+		if err != nil {
+			t.Fatalf("MonitorGeofences() = %v", err)
+		}
+		stream.Send(&pb.GeofencePosition{Location: &pb.Point{}, Options: options})
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("MonitorGeofences(%v) = %v, want InvalidArgument", options, err)
+		}
+	}
+}
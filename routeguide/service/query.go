@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// 分页的默认值和上限，见 pb.ListFeaturesRequest.PageSize
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// QueryFeatures 通过 metadata 返回分页信息
+const (
+	// TotalCountKey 是 header 中匹配的特征总数
+	TotalCountKey = "x-total-count"
+	// NextPageTokenKey 是 trailer 中下一页的 page_token，没有下一页时不设置
+	NextPageTokenKey = "x-next-page-token"
+)
+
+// featurePage 是一次查询的结果
+type featurePage struct {
+	features      []*pb.Feature
+	nextPageToken string
+	total         int
+}
+
+// QueryFeatures 按照 req 的顺序发送一页特征
+func (s *RouteGuide) QueryFeatures(req *pb.ListFeaturesRequest, stream pb.RouteGuide_QueryFeaturesServer) error {
+	page, err := s.query(req, 0)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendHeader(metadata.Pairs(TotalCountKey, strconv.Itoa(page.total))); err != nil {
+		return err
+	}
+	for _, feature := range page.features {
+		if err := stream.Send(feature); err != nil {
+			return err
+		}
+	}
+	if page.nextPageToken != "" {
+		stream.SetTrailer(metadata.Pairs(NextPageTokenKey, page.nextPageToken))
+	}
+	return nil
+}
+
+// ListFeaturesPage 是 QueryFeatures 的一元版本，适合 REST 客户端
+func (s *RouteGuide) ListFeaturesPage(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error) {
+	page, err := s.query(req, DefaultPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListFeaturesResponse{
+		Features:      page.features,
+		NextPageToken: page.nextPageToken,
+		TotalSize:     int32(page.total),
+	}, nil
+}
+
+// query 返回 req 请求的一页特征，page_size 为 0 时一页有 defaultSize 个，defaultSize 为 0 表示不限制
+func (s *RouteGuide) query(req *pb.ListFeaturesRequest, defaultSize int) (*featurePage, error) {
+	if req.OrderBy == pb.ListFeaturesRequest_DISTANCE && req.Origin == nil {
+		return nil, status.Error(codes.InvalidArgument, "origin is required when ordering by distance")
+	}
+	fingerprint := queryFingerprint(req)
+	offset, err := decodePageToken(req.PageToken, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := sortFeatures(req, filterFeatures(req, s.features()))
+	page := &featurePage{total: len(matches)}
+	// 特征数据库在翻页期间变小时，offset 可能已经超过了末尾
+	if offset >= len(matches) {
+		return page, nil
+	}
+	size := int(req.PageSize)
+	if size == 0 {
+		size = defaultSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	end := len(matches)
+	if size > 0 && offset+size < end {
+		end = offset + size
+		page.nextPageToken = encodePageToken(end, fingerprint)
+	}
+	page.features = matches[offset:end]
+	return page, nil
+}
+
+func filterFeatures(req *pb.ListFeaturesRequest, features []*pb.Feature) []*pb.Feature {
+	in := areaMatcher(req)
+	var matches []*pb.Feature
+	for _, feature := range features {
+		if in(feature.Location) {
+			matches = append(matches, feature)
+		}
+	}
+	return matches
+}
+
+// areaMatcher 返回判断一个点是否在 req.Area 中的函数
+func areaMatcher(req *pb.ListFeaturesRequest) func(*pb.Point) bool {
+	switch area := req.Area.(type) {
+	case *pb.ListFeaturesRequest_Rect:
+		return func(p *pb.Point) bool { return geo.InRange(p, area.Rect) }
+	case *pb.ListFeaturesRequest_Polygon:
+		return func(p *pb.Point) bool { return geo.InPolygon(p, area.Polygon) }
+	case *pb.ListFeaturesRequest_Circle:
+		return func(p *pb.Point) bool { return geo.InCircle(p, area.Circle) }
+	case *pb.ListFeaturesRequest_Corridor:
+		return func(p *pb.Point) bool { return geo.InCorridor(p, area.Corridor) }
+	}
+	return func(*pb.Point) bool { return true }
+}
+
+// sortFeatures 按照 req.OrderBy 排序，相等的特征保持在特征数据库中的顺序
+func sortFeatures(req *pb.ListFeaturesRequest, features []*pb.Feature) []*pb.Feature {
+	switch req.OrderBy {
+	case pb.ListFeaturesRequest_NAME:
+		sort.SliceStable(features, func(i, j int) bool {
+			a, b := features[i].Name, features[j].Name
+			if a == "" || b == "" {
+				return b == "" && a != ""
+			}
+			return a < b
+		})
+	case pb.ListFeaturesRequest_DISTANCE:
+		distances := make(map[*pb.Feature]int32, len(features))
+		for _, feature := range features {
+			distances[feature] = geo.Distance(req.Origin, feature.Location)
+		}
+		sort.SliceStable(features, func(i, j int) bool {
+			return distances[features[i]] < distances[features[j]]
+		})
+	}
+	return features
+}
+
+// queryFingerprint 是 page_size 和 page_token 之外的请求字段的摘要，
+// 用来拒绝在不同的查询之间使用的 page_token
+func queryFingerprint(req *pb.ListFeaturesRequest) uint64 {
+	q := proto.Clone(req).(*pb.ListFeaturesRequest)
+	q.PageSize, q.PageToken = 0, ""
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(q)
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+func encodePageToken(offset int, fingerprint uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%016x", offset, fingerprint)))
+}
+
+func decodePageToken(token string, fingerprint uint64) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+	}
+	var offset int
+	var fp uint64
+	if n, err := fmt.Sscanf(string(b), "%d:%x", &offset, &fp); err != nil || n != 2 || offset < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+	}
+	if fp != fingerprint {
+		return 0, status.Error(codes.InvalidArgument, "page_token was returned for a different request")
+	}
+	return offset, nil
+}
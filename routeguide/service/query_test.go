@@ -0,0 +1,217 @@
+package service_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// allPages 用 ListFeaturesPage 翻完 req 的所有页，返回特征的名字
+func allPages(t *testing.T, c pb.RouteGuideClient, req *pb.ListFeaturesRequest) []string {
+	t.Helper()
+	var names []string
+	for pages := 0; ; pages++ {
+		if pages > len(servicetest.Features()) {
+			t.Fatalf("ListFeaturesPage() did not stop after %d pages", pages)
+		}
+		resp, err := c.ListFeaturesPage(testContext(t), req)
+		if err != nil {
+			t.Fatalf("ListFeaturesPage(%v) = %v", req, err)
+		}
+		if req.PageSize > 0 && len(resp.Features) > int(req.PageSize) {
+			t.Errorf("ListFeaturesPage(%v) returned %d features", req, len(resp.Features))
+		}
+		for _, f := range resp.Features {
+			names = append(names, f.GetName())
+		}
+		if resp.NextPageToken == "" {
+			return names
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+func TestListFeaturesPage(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	resp, err := env.RouteGuide.ListFeaturesPage(ctx, &pb.ListFeaturesRequest{})
+	if err != nil {
+		t.Fatalf("ListFeaturesPage() = %v", err)
+	}
+	if len(resp.Features) != len(servicetest.Features()) || resp.TotalSize != int32(len(servicetest.Features())) || resp.NextPageToken != "" {
+		t.Errorf("ListFeaturesPage() = %d features, total %d, next %q, want all features in one page", len(resp.Features), resp.TotalSize, resp.NextPageToken)
+	}
+
+	// 没有名字的特征排在最后
+	want := []string{
+		"101 New Jersey 10, Whippany, NJ 07981, USA",
+		"5 Conners Road, Kingston, NY 12401, USA",
+		"Mid Hudson Psychiatric Center, New Hampton, NY 10958, USA",
+		"Patriots Path, Mendham, NJ 07945, USA",
+		"U.S. 6, Shohola, PA 18458, USA",
+		"",
+	}
+	got := allPages(t, env.RouteGuide, &pb.ListFeaturesRequest{PageSize: 4, OrderBy: pb.ListFeaturesRequest_NAME})
+	if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", want) {
+		t.Errorf("ordered by name = %q, want %q", got, want)
+	}
+
+	// 从 Mendham 出发由近到远
+	mendham := servicetest.Features()[0].Location
+	want = []string{
+		"Patriots Path, Mendham, NJ 07945, USA",
+		"101 New Jersey 10, Whippany, NJ 07981, USA",
+		"",
+		"U.S. 6, Shohola, PA 18458, USA",
+		"Mid Hudson Psychiatric Center, New Hampton, NY 10958, USA",
+		"5 Conners Road, Kingston, NY 12401, USA",
+	}
+	got = allPages(t, env.RouteGuide, &pb.ListFeaturesRequest{PageSize: 1, OrderBy: pb.ListFeaturesRequest_DISTANCE, Origin: mendham})
+	if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", want) {
+		t.Errorf("ordered by distance = %q, want %q", got, want)
+	}
+
+	rect := &pb.Rectangle{
+		Lo: &pb.Point{Latitude: 407000000, Longitude: -747000000},
+		Hi: &pb.Point{Latitude: 409000000, Longitude: -743000000},
+	}
+	resp, err = env.RouteGuide.ListFeaturesPage(ctx, &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Rect{Rect: rect}, PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListFeaturesPage(rect) = %v", err)
+	}
+	if resp.TotalSize != 2 || len(resp.Features) != 1 || resp.NextPageToken == "" {
+		t.Errorf("ListFeaturesPage(rect) = %v, want 1 of 2 features", resp)
+	}
+}
+
+func TestListFeaturesPageErrors(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	first, err := env.RouteGuide.ListFeaturesPage(ctx, &pb.ListFeaturesRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListFeaturesPage() = %v", err)
+	}
+	for _, req := range []*pb.ListFeaturesRequest{
+		{OrderBy: pb.ListFeaturesRequest_DISTANCE},
+		{PageToken: "not a token"},
+		{PageToken: "MTI"},
+		// page_token 只能用于相同的查询
+		{PageToken: first.NextPageToken, OrderBy: pb.ListFeaturesRequest_NAME},
+	} {
+		_, err := env.RouteGuide.ListFeaturesPage(ctx, req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListFeaturesPage(%v) = %v, want InvalidArgument", req, err)
+		}
+	}
+
+	// page_size 可以在翻页时改变
+	resp, err := env.RouteGuide.ListFeaturesPage(ctx, &pb.ListFeaturesRequest{PageToken: first.NextPageToken, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListFeaturesPage(next page) = %v", err)
+	}
+	if len(resp.Features) != len(servicetest.Features())-1 || resp.NextPageToken != "" {
+		t.Errorf("ListFeaturesPage(next page) = %d features, next %q", len(resp.Features), resp.NextPageToken)
+	}
+}
+
+func TestQueryFeatures(t *testing.T) {
+	env := servicetest.Start(t)
+
+	var header, trailer metadata.MD
+	stream, err := env.RouteGuide.QueryFeatures(testContext(t), &pb.ListFeaturesRequest{PageSize: 4, OrderBy: pb.ListFeaturesRequest_NAME},
+		grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		t.Fatalf("QueryFeatures() = %v", err)
+	}
+	n := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("QueryFeatures().Recv() = %v", err)
+		}
+		n++
+	}
+	if n != 4 {
+		t.Errorf("QueryFeatures() returned %d features, want 4", n)
+	}
+	if got := header.Get(service.TotalCountKey); fmt.Sprint(got) != "[6]" {
+		t.Errorf("header %s = %q, want 6", service.TotalCountKey, got)
+	}
+	tokens := trailer.Get(service.NextPageTokenKey)
+	if len(tokens) != 1 {
+		t.Fatalf("trailer %s = %q, want a page token", service.NextPageTokenKey, tokens)
+	}
+
+	// QueryFeatures 和 ListFeaturesPage 的 page_token 可以互换，page_size 为 0 时返回所有剩下的特征
+	resp, err := env.RouteGuide.ListFeaturesPage(testContext(t), &pb.ListFeaturesRequest{PageToken: tokens[0], OrderBy: pb.ListFeaturesRequest_NAME})
+	if err != nil {
+		t.Fatalf("ListFeaturesPage(next page) = %v", err)
+	}
+	if len(resp.Features) != 2 || resp.NextPageToken != "" {
+		t.Errorf("ListFeaturesPage(next page) = %v, want the last 2 features", resp)
+	}
+}
+
+func TestListFeaturesPageAreas(t *testing.T) {
+	env := servicetest.Start(t)
+	features := servicetest.Features()
+	mendham := features[0].Location
+
+	// 覆盖 Mendham 和 Whippany 的三角形，以及挖去 Whippany 周围的洞之后的多边形
+	triangle := &pb.LinearRing{Points: []*pb.Point{
+		{Latitude: 407000000, Longitude: -748000000},
+		{Latitude: 407000000, Longitude: -742000000},
+		{Latitude: 412000000, Longitude: -745000000},
+	}}
+	hole := &pb.LinearRing{Points: []*pb.Point{
+		{Latitude: 408000000, Longitude: -744100000},
+		{Latitude: 408000000, Longitude: -743900000},
+		{Latitude: 408200000, Longitude: -743900000},
+		{Latitude: 408200000, Longitude: -744100000},
+	}}
+	tests := []struct {
+		name string
+		req  *pb.ListFeaturesRequest
+		want []string
+	}{
+		{"polygon", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Polygon{Polygon: &pb.Polygon{Exterior: triangle}}},
+			[]string{features[0].Name, features[1].Name}},
+		{"polygon with a hole", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Polygon{Polygon: &pb.Polygon{Exterior: triangle, Holes: []*pb.LinearRing{hole}}}},
+			[]string{features[0].Name}},
+		{"circle", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Circle{Circle: &pb.Circle{Center: mendham, RadiusMeters: 20000}}},
+			[]string{features[0].Name, features[1].Name}},
+		{"small circle", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Circle{Circle: &pb.Circle{Center: mendham, RadiusMeters: 1}}},
+			[]string{features[0].Name}},
+		// 从 Mendham 向北的折线，Whippany 在东边 18km 之外
+		{"corridor", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Corridor{Corridor: &pb.Corridor{
+			Path:         []*pb.Point{mendham, {Latitude: 413000000, Longitude: mendham.Longitude}},
+			BufferMeters: 10000,
+		}}}, []string{features[0].Name}},
+		{"wide corridor", &pb.ListFeaturesRequest{Area: &pb.ListFeaturesRequest_Corridor{Corridor: &pb.Corridor{
+			Path:         []*pb.Point{mendham, {Latitude: 413000000, Longitude: mendham.Longitude}},
+			BufferMeters: 20000,
+		}}}, []string{features[0].Name, features[1].Name}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.OrderBy, tt.req.Origin = pb.ListFeaturesRequest_DISTANCE, mendham
+			if got := allPages(t, env.RouteGuide, tt.req); fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("ListFeaturesPage(%s) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"gRPCDemo/address"
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultReverseGeocodeRadius 是 ReverseGeocode 没有设置 radius_meters 时使用的范围，单位是米
+const DefaultReverseGeocodeRadius = 1000.0
+
+// ReverseGeocode 返回 req.RadiusMeters 范围内离 req.Location 最近的有名字的特征，距离相同时取数据库中靠前的。
+// confidence 是地址的完整程度乘以距离的系数，距离的系数在 location 处是 1，在范围的边界上是 0.5
+func (s *RouteGuide) ReverseGeocode(ctx context.Context, req *pb.ReverseGeocodeRequest) (*pb.ReverseGeocodeResponse, error) {
+	radius := req.RadiusMeters
+	if radius == 0 {
+		radius = DefaultReverseGeocodeRadius
+	}
+
+	var nearest *pb.Feature
+	distance := int32(math.MaxInt32)
+	for _, feature := range s.features() {
+		if feature.Name == "" {
+			continue
+		}
+		if d := geo.Distance(req.Location, feature.Location); d < distance {
+			nearest, distance = feature, d
+		}
+	}
+	if nearest == nil || float64(distance) > radius {
+		return nil, status.Errorf(codes.NotFound, "no named feature within %vm", radius)
+	}
+
+	a := address.Parse(nearest.Name)
+	return &pb.ReverseGeocodeResponse{
+		Feature: nearest,
+		Address: &pb.Address{
+			Street:     a.Street,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		},
+		DistanceMeters: distance,
+		Confidence:     a.Completeness() * (1 - float64(distance)/(2*radius)),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"github.com/golang/protobuf/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReverseGeocode(t *testing.T) {
+	env := servicetest.Start(t, servicetest.WithServerOptions(grpc.UnaryInterceptor(validate.UnaryServerInterceptor)))
+	ctx := testContext(t)
+	mendham := servicetest.Features()[0].Location
+
+	resp, err := env.RouteGuide.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: mendham})
+	if err != nil {
+		t.Fatalf("ReverseGeocode() = %v", err)
+	}
+	want := &pb.Address{Street: "Patriots Path", City: "Mendham", State: "NJ", PostalCode: "07945", Country: "USA"}
+	if resp.DistanceMeters != 0 || resp.Confidence != 1 || !proto.Equal(resp.Address, want) {
+		t.Errorf("ReverseGeocode(Mendham) = %v", resp)
+	}
+
+	// 往北大约 500m，在默认的范围内但是置信度降低
+	near := &pb.Point{Latitude: mendham.Latitude + 45000, Longitude: mendham.Longitude}
+	resp, err = env.RouteGuide.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: near})
+	if err != nil {
+		t.Fatalf("ReverseGeocode() = %v", err)
+	}
+	if resp.Address.GetCity() != "Mendham" || resp.DistanceMeters < 490 || resp.DistanceMeters > 510 || resp.Confidence < 0.7 || resp.Confidence > 0.8 {
+		t.Errorf("ReverseGeocode(500m north of Mendham) = %v", resp)
+	}
+
+	if _, err := env.RouteGuide.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: near, RadiusMeters: 100}); status.Code(err) != codes.NotFound {
+		t.Errorf("ReverseGeocode(radius 100m) = %v, want NotFound", err)
+	}
+
+	// 没有名字的特征被跳过
+	unnamed := servicetest.Features()[5].Location
+	resp, err = env.RouteGuide.ReverseGeocode(ctx, &pb.ReverseGeocodeRequest{Location: unnamed, RadiusMeters: 100000})
+	if err != nil {
+		t.Fatalf("ReverseGeocode() = %v", err)
+	}
+	if resp.Feature.GetName() == "" || resp.DistanceMeters == 0 {
+		t.Errorf("ReverseGeocode(unnamed feature) = %v, want the nearest named feature", resp)
+	}
+
+	for _, req := range []*pb.ReverseGeocodeRequest{{}, {Location: mendham, RadiusMeters: -1}} {
+		if _, err := env.RouteGuide.ReverseGeocode(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ReverseGeocode(%v) = %v, want InvalidArgument", req, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"gRPCDemo/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 房间使用的 metadata
+const (
+	// RoomKey 是要加入的房间，和 StreamRequest.room 的作用相同，但是在发送问题之前就会加入
+	RoomKey = "x-echo-room"
+	// ParticipantKey 是参与者在房间中的名字，为空时自动生成，同一个房间中的名字不能重复
+	ParticipantKey = "x-echo-participant"
+)
+
+// 房间的默认限制，见 Echo.SetRoomLimits
+const (
+	DefaultMaxRoomParticipants = 16
+	DefaultRoomHistory         = 100
+)
+
+// participantBuffer 是每个参与者等待发送的事件的数量，
+// 参与者接收得太慢，缓冲区满了之后会被移出房间，不会阻塞其他参与者
+const participantBuffer = 64
+
+// room 把一个房间中的事件广播给所有的参与者，房间在最后一个参与者离开之后被删除
+type room struct {
+	id string
+	// maxParticipants 和 historyLimit 是创建房间时的限制
+	maxParticipants int
+	historyLimit    int
+
+	mu           sync.Mutex
+	participants map[string]*participant
+	// history 是最近的 MESSAGE 事件，加入房间时重放
+	history []*pb.RoomEvent
+	seq     int64
+}
+
+type participant struct {
+	name   string
+	events chan *pb.StreamResponse
+	// err 在参与者被移出房间时设置，events 随后被关闭
+	err error
+}
+
+// SetRoomLimits 设置每个房间的参与者数量上限和重放的历史消息数量，只影响之后创建的房间
+func (s *Echo) SetRoomLimits(maxParticipants, history int) {
+	s.roomsMu.Lock()
+	s.maxParticipants, s.roomHistory = maxParticipants, history
+	s.roomsMu.Unlock()
+}
+
+// join 把 name 加入房间 id，返回的 events 中先是历史消息，然后是所有参与者（包括自己）的 JOIN 事件
+func (s *Echo) join(id, name string) (*room, *participant, error) {
+	s.roomsMu.Lock()
+	r, ok := s.rooms[id]
+	if !ok {
+		r = &room{
+			id:              id,
+			maxParticipants: s.maxParticipants,
+			historyLimit:    s.roomHistory,
+			participants:    make(map[string]*participant),
+		}
+		s.rooms[id] = r
+	}
+	if name == "" {
+		s.guests++
+		name = fmt.Sprintf("guest-%d", s.guests)
+	}
+	// 持有 roomsMu 时加入房间，这样 leave 不会在加入之前删除这个房间
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkJoinLocked(name); err != nil {
+		// 没能加入时不留下空房间
+		if len(r.participants) == 0 {
+			delete(s.rooms, id)
+		}
+		s.roomsMu.Unlock()
+		return nil, nil, err
+	}
+	s.roomsMu.Unlock()
+
+	// 历史消息不占用事件的缓冲区
+	p := &participant{name: name, events: make(chan *pb.StreamResponse, participantBuffer+len(r.history))}
+	for _, ev := range r.history {
+		ev = proto.Clone(ev).(*pb.RoomEvent)
+		ev.History = true
+		p.events <- eventResponse(ev)
+	}
+	r.participants[name] = p
+	r.broadcastLocked(&pb.RoomEvent{Type: pb.RoomEvent_JOIN, Participant: name})
+	return r, p, nil
+}
+
+// checkJoinLocked 检查 name 能否加入房间，调用方需要持有 r.mu
+func (r *room) checkJoinLocked(name string) error {
+	if _, ok := r.participants[name]; ok {
+		return status.Errorf(codes.AlreadyExists, "participant %q is already in room %q", name, r.id)
+	}
+	if len(r.participants) >= r.maxParticipants {
+		return status.Errorf(codes.ResourceExhausted, "room %q is full (%d participants)", r.id, r.maxParticipants)
+	}
+	return nil
+}
+
+// leave 把 p 移出房间并通知其他参与者，房间空了之后被删除
+func (s *Echo) leave(r *room, p *participant) {
+	s.roomsMu.Lock()
+	defer s.roomsMu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.participants[p.name] == p {
+		delete(r.participants, p.name)
+		close(p.events)
+	}
+	r.broadcastLocked(&pb.RoomEvent{Type: pb.RoomEvent_LEAVE, Participant: p.name})
+	if len(r.participants) == 0 {
+		delete(s.rooms, r.id)
+	}
+}
+
+// say 把 p 的问题广播给房间中的所有参与者
+func (r *room) say(p *participant, question string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broadcastLocked(&pb.RoomEvent{Type: pb.RoomEvent_MESSAGE, Participant: p.name, Question: question})
+}
+
+// broadcastLocked 给事件编号并发送给所有参与者，调用方需要持有 r.mu
+func (r *room) broadcastLocked(ev *pb.RoomEvent) {
+	r.seq++
+	ev.Room = r.id
+	ev.Sequence = r.seq
+	ev.Time = timestamppb.Now()
+	if ev.Type == pb.RoomEvent_MESSAGE && r.historyLimit > 0 {
+		r.history = append(r.history, ev)
+		if len(r.history) > r.historyLimit {
+			r.history = append(r.history[:0:0], r.history[len(r.history)-r.historyLimit:]...)
+		}
+	}
+
+	resp := eventResponse(ev)
+	for name, p := range r.participants {
+		select {
+		case p.events <- resp:
+		default:
+			p.err = status.Errorf(codes.ResourceExhausted, "participant %q is too slow to receive room events", name)
+			delete(r.participants, name)
+			close(p.events)
+		}
+	}
+}
+
+// eventResponse 返回事件对应的响应，Answer 是给只打印 Answer 的客户端看的
+func eventResponse(ev *pb.RoomEvent) *pb.StreamResponse {
+	var answer string
+	switch ev.Type {
+	case pb.RoomEvent_JOIN:
+		answer = ev.Participant + " joined"
+	case pb.RoomEvent_LEAVE:
+		answer = ev.Participant + " left"
+	default:
+		answer = ev.Participant + ": " + ev.Question
+	}
+	return &pb.StreamResponse{Answer: answer, Event: ev}
+}
+
+// roomConversation 把流加入房间 id，first 是流中已经收到的第一条消息，没有的话为 nil
+func (s *Echo) roomConversation(stream pb.Echo_ConversationsServer, md metadata.MD, id string, first *pb.StreamRequest) error {
+	if firstValue(md, ResponderKey) != "" {
+		return status.Errorf(codes.InvalidArgument, "%s can not be used in a room", ResponderKey)
+	}
+	r, p, err := s.join(id, firstValue(md, ParticipantKey))
+	if err != nil {
+		return err
+	}
+	defer s.leave(r, p)
+
+	if first != nil {
+		r.say(p, first.Question)
+	}
+	// 接收和发送在不同的 goroutine 中进行，参与者不需要说话也能收到其他人的消息
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if req.Room != "" && req.Room != id {
+				recvErr <- status.Errorf(codes.InvalidArgument, "already in room %q", id)
+				return
+			}
+			r.say(p, req.Question)
+		}
+	}()
+
+	for {
+		select {
+		case resp, ok := <-p.events:
+			if !ok {
+				return p.err
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			// 客户端结束发送就是离开房间
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoomDropsSlowParticipant(t *testing.T) {
+	s := NewEcho()
+	r, fast, err := s.join("r", "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, slow, err := s.join("r", "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fast 一直在接收，slow 从不接收
+	for i := 0; i < participantBuffer+1; i++ {
+		r.say(fast, "spam")
+		for len(fast.events) > 0 {
+			<-fast.events
+		}
+	}
+
+	n := 0
+	for range slow.events {
+		n++
+	}
+	if n != participantBuffer {
+		t.Errorf("slow received %d events before being dropped, want %d", n, participantBuffer)
+	}
+	if status.Code(slow.err) != codes.ResourceExhausted {
+		t.Errorf("slow.err = %v, want ResourceExhausted", slow.err)
+	}
+
+	// 被移出的参与者离开时其他人会收到 LEAVE 事件，房间在所有人离开之后被删除
+	s.leave(r, slow)
+	if ev := (<-fast.events).Event; ev.Participant != "slow" || ev.Type.String() != "LEAVE" {
+		t.Errorf("event after dropping slow = %v, want its LEAVE", ev)
+	}
+	s.leave(r, fast)
+	if len(s.rooms) != 0 {
+		t.Errorf("rooms = %v, want the empty room deleted", s.rooms)
+	}
+}
+
+func TestRoomFailedJoinDoesNotLeaveEmptyRoom(t *testing.T) {
+	s := NewEcho()
+	s.SetRoomLimits(0, DefaultRoomHistory)
+	if _, _, err := s.join("r", "alice"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("join() into a full room = %v, want ResourceExhausted", err)
+	}
+	if len(s.rooms) != 0 {
+		t.Errorf("rooms = %v, want the room created by the failed join deleted", s.rooms)
+	}
+
+	// 房间中还有参与者时，加入失败不会删除房间
+	s.SetRoomLimits(1, DefaultRoomHistory)
+	r, alice, err := s.join("r", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.join("r", "bob"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("join() into a full room = %v, want ResourceExhausted", err)
+	}
+	if s.rooms["r"] != r {
+		t.Errorf("rooms = %v, want the room of alice kept", s.rooms)
+	}
+	s.leave(r, alice)
+}
@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+	"gRPCDemo/routeguide/service/servicetest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// member 是房间中的一个参与者，收到的事件在 events 中
+type member struct {
+	t      *testing.T
+	stream pb.Echo_ConversationsClient
+	events chan *pb.StreamResponse
+	err    chan error
+}
+
+func joinRoom(ctx context.Context, t *testing.T, env *servicetest.Env, md metadata.MD) *member {
+	t.Helper()
+	stream, err := env.Echo.Conversations(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	m := &member{t: t, stream: stream, events: make(chan *pb.StreamResponse, 100), err: make(chan error, 1)}
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				m.err <- err
+				return
+			}
+			m.events <- resp
+		}
+	}()
+	return m
+}
+
+func (m *member) say(q string) {
+	m.t.Helper()
+	if err := m.stream.Send(&pb.StreamRequest{Question: q}); err != nil {
+		m.t.Fatalf("Send(%q) = %v", q, err)
+	}
+}
+
+// next 返回下一个事件，调用结束时返回调用的错误
+func (m *member) next() (*pb.RoomEvent, error) {
+	m.t.Helper()
+	select {
+	case resp := <-m.events:
+		return resp.Event, nil
+	case err := <-m.err:
+		return nil, err
+	case <-time.After(5 * time.Second):
+		m.t.Fatal("timed out waiting for a room event")
+		return nil, nil
+	}
+}
+
+// expect 检查接下来的事件，want 中的每一项是 "类型 参与者 问题"
+func (m *member) expect(want ...string) {
+	m.t.Helper()
+	for _, w := range want {
+		ev, err := m.next()
+		if err != nil {
+			m.t.Fatalf("next() = %v, want %q", err, w)
+		}
+		got := ev.Type.String() + " " + ev.Participant
+		if ev.Question != "" {
+			got += " " + ev.Question
+		}
+		if ev.History {
+			got = "history " + got
+		}
+		if got != w {
+			m.t.Errorf("event = %q, want %q", got, w)
+		}
+	}
+}
+
+func roomMD(room, name string) metadata.MD {
+	return metadata.Pairs(service.RoomKey, room, service.ParticipantKey, name)
+}
+
+func TestRoomBroadcast(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	alice := joinRoom(ctx, t, env, roomMD("r1", "alice"))
+	alice.expect("JOIN alice")
+	bob := joinRoom(ctx, t, env, roomMD("r1", "bob"))
+	bob.expect("JOIN bob")
+	alice.expect("JOIN bob")
+
+	alice.say("hi")
+	alice.expect("MESSAGE alice hi")
+	bob.expect("MESSAGE alice hi")
+	bob.say("hello")
+	alice.expect("MESSAGE bob hello")
+	bob.expect("MESSAGE bob hello")
+
+	// 其他房间收不到
+	other := joinRoom(ctx, t, env, roomMD("r2", "carol"))
+	other.expect("JOIN carol")
+
+	bob.stream.CloseSend()
+	if _, err := bob.next(); err == nil {
+		t.Error("bob still receives events after leaving")
+	}
+	alice.expect("LEAVE bob")
+	select {
+	case resp := <-other.events:
+		t.Errorf("room r2 received %v", resp)
+	default:
+	}
+}
+
+func TestRoomSequenceAndAnswer(t *testing.T) {
+	env := servicetest.Start(t)
+	alice := joinRoom(testContext(t), t, env, roomMD("r", "alice"))
+	alice.say("one")
+	alice.say("two")
+
+	var last int64
+	for _, want := range []string{"alice joined", "alice: one", "alice: two"} {
+		resp := <-alice.events
+		if resp.Answer != want {
+			t.Errorf("answer = %q, want %q", resp.Answer, want)
+		}
+		if resp.Event.Sequence <= last || resp.Event.Room != "r" || resp.Event.Time == nil {
+			t.Errorf("event = %v, want room r, a time and a sequence after %d", resp.Event, last)
+		}
+		last = resp.Event.Sequence
+	}
+}
+
+func TestRoomHistory(t *testing.T) {
+	env := servicetest.Start(t)
+	env.EchoService.SetRoomLimits(10, 2)
+	ctx := testContext(t)
+
+	alice := joinRoom(ctx, t, env, roomMD("r", "alice"))
+	alice.expect("JOIN alice")
+	for _, q := range []string{"1", "2", "3"} {
+		alice.say(q)
+		alice.expect("MESSAGE alice " + q)
+	}
+
+	bob := joinRoom(ctx, t, env, roomMD("r", "bob"))
+	bob.expect("history MESSAGE alice 2", "history MESSAGE alice 3", "JOIN bob")
+
+	// 房间空了之后被删除，历史消息也没有了
+	alice.stream.CloseSend()
+	bob.stream.CloseSend()
+	alice.next()
+	bob.next()
+	time.Sleep(50 * time.Millisecond)
+	carol := joinRoom(ctx, t, env, roomMD("r", "carol"))
+	carol.expect("JOIN carol")
+}
+
+func TestRoomLimits(t *testing.T) {
+	env := servicetest.Start(t)
+	env.EchoService.SetRoomLimits(2, 0)
+	ctx := testContext(t)
+
+	alice := joinRoom(ctx, t, env, roomMD("r", "alice"))
+	alice.expect("JOIN alice")
+
+	dup := joinRoom(ctx, t, env, roomMD("r", "alice"))
+	if _, err := dup.next(); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("joining with a used name = %v, want AlreadyExists", err)
+	}
+
+	bob := joinRoom(ctx, t, env, roomMD("r", "bob"))
+	bob.expect("JOIN bob")
+	carol := joinRoom(ctx, t, env, roomMD("r", "carol"))
+	if _, err := carol.next(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("joining a full room = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestRoomFromFirstMessage(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	alice := joinRoom(ctx, t, env, roomMD("r", "alice"))
+	alice.expect("JOIN alice")
+
+	// 没有名字的参与者使用自动生成的名字
+	guest := joinRoom(ctx, t, env, metadata.MD{})
+	if err := guest.stream.Send(&pb.StreamRequest{Question: "knock knock", Room: "r"}); err != nil {
+		t.Fatal(err)
+	}
+	ev, err := guest.next()
+	if err != nil || ev.Type != pb.RoomEvent_JOIN || ev.Participant == "" {
+		t.Fatalf("first event = %v, %v, want the guest's JOIN", ev, err)
+	}
+	alice.expect("JOIN "+ev.Participant, "MESSAGE "+ev.Participant+" knock knock")
+
+	// room 只能在第一条消息中设置
+	late := joinRoom(ctx, t, env, metadata.MD{})
+	late.say("hi")
+	late.next()
+	late.stream.Send(&pb.StreamRequest{Question: "join", Room: "r"})
+	if _, err := late.next(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("room in the second message = %v, want InvalidArgument", err)
+	}
+
+	md := roomMD("r", "dave")
+	md.Set(service.ResponderKey, service.ResponderEcho)
+	if _, err := joinRoom(ctx, t, env, md).next(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("responder in a room = %v, want InvalidArgument", err)
+	}
+}
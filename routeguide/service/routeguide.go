@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+	"gRPCDemo/search"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// RouteGuide 实现了 RouteGuide 服务
+type RouteGuide struct {
+	pb.UnimplementedRouteGuideServer
+
+	// featuresMu 保护 savedFeatures 和 index，特征数据库是在服务启动之后异步加载的
+	featuresMu    sync.RWMutex
+	savedFeatures []*pb.Feature
+	index         *search.Index
+	mu            sync.Mutex
+	routeNodes    map[string][]*pb.RouteNode
+}
+
+// SetFeatures 替换特征数据库并重建名字的索引，可以在服务开始处理请求之后调用
+func (s *RouteGuide) SetFeatures(features []*pb.Feature) {
+	index := search.NewIndex(features)
+	s.featuresMu.Lock()
+	s.savedFeatures = features
+	s.index = index
+	s.featuresMu.Unlock()
+}
+
+func (s *RouteGuide) features() []*pb.Feature {
+	s.featuresMu.RLock()
+	defer s.featuresMu.RUnlock()
+	return s.savedFeatures
+}
+
+func (s *RouteGuide) searchIndex() *search.Index {
+	s.featuresMu.RLock()
+	defer s.featuresMu.RUnlock()
+	return s.index
+}
+
+func (s *RouteGuide) GetFeature(ctx context.Context, point *pb.Point) (*pb.Feature, error) {
+	for _, feature := range s.features() {
+		if proto.Equal(feature.Location, point) {
+			return feature, nil
+		}
+	}
+
+	return &pb.Feature{Location: point}, nil
+}
+
+func (s *RouteGuide) ListFeatures(rect *pb.Rectangle, stream pb.RouteGuide_ListFeaturesServer) error {
+	for _, feature := range s.features() {
+		if geo.InRange(feature.Location, rect) {
+			if err := stream.Send(feature); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *RouteGuide) RecordRoute(stream pb.RouteGuide_RecordRouteServer) error {
+	var pointCount, featureCount, distance int32
+	var lastPoint *pb.Point
+
+	startTime := time.Now()
+	for {
+		point, err := stream.Recv()
+		if err == io.EOF {
+			endTime := time.Now()
+			return stream.SendAndClose(&pb.RouteSummary{
+				PointCount:   pointCount,
+				FeatureCount: featureCount,
+				Distance:     distance,
+				ElapsedTime:  int32(endTime.Sub(startTime).Milliseconds()),
+			})
+		}
+		if err != nil {
+			return err
+		}
+		pointCount++
+		for _, feature := range s.features() {
+			if proto.Equal(feature.Location, point) {
+				featureCount++
+			}
+		}
+		if lastPoint != nil {
+			distance += geo.Distance(lastPoint, point)
+		}
+		time.Sleep(time.Millisecond * 10)
+		lastPoint = point
+	}
+}
+
+// RouteChat
+// echo 服务，将客户端输入的节点返回回去
+func (s *RouteGuide) RouteChat(stream pb.RouteGuide_RouteChatServer) error {
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		key := geo.Serialize(in.Location)
+
+		s.mu.Lock()
+		s.routeNodes[key] = append(s.routeNodes[key], in)
+		// 这里复制一遍是为了防止当服务端写一个客户端的流时，另一个客户端修改了 routeNodes
+		// 这里不需要执行深拷贝的原因是，routeNodes 只会增加，不会修改
+		rn := make([]*pb.RouteNode, len(s.routeNodes[key]))
+		copy(rn, s.routeNodes[key])
+		s.mu.Unlock()
+
+		for _, note := range rn {
+			if err := stream.Send(note); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// NewRouteGuide 返回特征数据库为空的 RouteGuide 服务，特征数据库通过 SetFeatures 设置
+func NewRouteGuide() *RouteGuide {
+	return &RouteGuide{
+		index:      search.NewIndex(nil),
+		routeNodes: make(map[string][]*pb.RouteNode),
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// Script 是 ResponderScript 使用的状态机，从 YAML 文件中读取，见 LoadScripts:
+//
+//	scripts:
+//	  greeting:
+//	    start: hello
+//	    states:
+//	      hello:
+//	        - match: "^hi"
+//	          reply: "hello, what is your name?"
+//	          next: name
+//	        - reply: "say hi first"
+//	      name:
+//	        - match: "^(.+)$"
+//	          reply: "nice to meet you, {{index .Groups 1}}"
+//	          end: true
+//
+// 每个问题按顺序匹配当前状态的 transition，第一个匹配的 transition 生成回答并转移到 next 状态，
+// next 为空时停留在当前状态。end 为 true 时调用在发送回答之后结束，会话回到 start 状态。
+// 没有 transition 匹配时调用以 FailedPrecondition 结束
+type Script struct {
+	Start  string                   `yaml:"start"`
+	States map[string][]*Transition `yaml:"states"`
+}
+
+// Transition 是状态机中的一个转移
+type Transition struct {
+	// Match 是匹配问题的正则表达式，为空时匹配所有问题
+	Match string `yaml:"match"`
+	// Reply 是回答的 text/template 模板，字段见 replyData
+	Reply string `yaml:"reply"`
+	Next  string `yaml:"next"`
+	End   bool   `yaml:"end"`
+
+	match *regexp.Regexp
+	reply *template.Template
+}
+
+// LoadScripts 读取脚本文件并检查所有的状态、正则表达式和模板
+func LoadScripts(filename string) (map[string]*Script, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read scripts: %v", err)
+	}
+	var file struct {
+		Scripts map[string]*Script `yaml:"scripts"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse scripts in %s: %v", filename, err)
+	}
+	for name, script := range file.Scripts {
+		if err := script.compile(); err != nil {
+			return nil, fmt.Errorf("script %s in %s: %v", name, filename, err)
+		}
+	}
+	return file.Scripts, nil
+}
+
+func (s *Script) compile() error {
+	if _, ok := s.States[s.Start]; !ok {
+		return fmt.Errorf("start state %q is not defined", s.Start)
+	}
+	// 按状态名排序，保证错误信息是稳定的
+	names := make([]string, 0, len(s.States))
+	for name := range s.States {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for i, t := range s.States[name] {
+			if t.Next != "" {
+				if _, ok := s.States[t.Next]; !ok {
+					return fmt.Errorf("state %s: transition %d: next state %q is not defined", name, i, t.Next)
+				}
+			}
+			var err error
+			if t.match, err = regexp.Compile(t.Match); err != nil {
+				return fmt.Errorf("state %s: transition %d: %v", name, i, err)
+			}
+			if t.reply, err = template.New("reply").Option("missingkey=error").Parse(t.Reply); err != nil {
+				return fmt.Errorf("state %s: transition %d: %v", name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// respond 在会话 c 的当前状态回答问题 q，调用方需要持有 c.mu
+func (s *Script) respond(name string, c *conversation, q string) (string, bool, error) {
+	// 会话第一次使用这个脚本，或者切换了脚本时，从 start 状态开始
+	if c.script != name || c.state == "" {
+		c.script, c.state = name, s.Start
+	}
+	for _, t := range s.States[c.state] {
+		groups := t.match.FindStringSubmatch(q)
+		if groups == nil {
+			continue
+		}
+		answer, err := execute(t.reply, replyData{Question: q, N: c.n, ConversationID: c.id, State: c.state, Groups: groups})
+		if err != nil {
+			return "", false, status.Errorf(codes.Internal, "script %s: state %s: %v", name, c.state, err)
+		}
+		switch {
+		case t.End:
+			c.state = ""
+		case t.Next != "":
+			c.state = t.Next
+		}
+		return answer, t.End, nil
+	}
+	return "", false, status.Errorf(codes.FailedPrecondition, "script %s: no transition in state %s matches %q", name, c.state, q)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/search"
+)
+
+// SearchFeatures 在特征的名字中搜索 req.Query
+func (s *RouteGuide) SearchFeatures(ctx context.Context, req *pb.SearchFeaturesRequest) (*pb.SearchFeaturesResponse, error) {
+	results, completions := s.searchIndex().Search(search.Query{
+		Text:      req.Query,
+		Bias:      req.Bias,
+		BiasScale: req.BiasScaleMeters,
+		Limit:     int(req.Limit),
+	})
+	resp := &pb.SearchFeaturesResponse{Completions: completions}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &pb.SearchFeaturesResponse_Result{
+			Feature:        r.Feature,
+			Score:          r.Score,
+			DistanceMeters: r.Distance,
+		})
+	}
+	return resp, nil
+}
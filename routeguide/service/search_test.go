@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSearchFeatures(t *testing.T) {
+	env := servicetest.Start(t, servicetest.WithServerOptions(grpc.UnaryInterceptor(validate.UnaryServerInterceptor)))
+	ctx := testContext(t)
+
+	resp, err := env.RouteGuide.SearchFeatures(ctx, &pb.SearchFeaturesRequest{Query: "whipany"})
+	if err != nil {
+		t.Fatalf("SearchFeatures() = %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Feature.GetName() != "101 New Jersey 10, Whippany, NJ 07981, USA" {
+		t.Errorf("SearchFeatures(whipany) = %v, want Whippany", resp.Results)
+	}
+	if len(resp.Completions) != 0 {
+		t.Errorf("SearchFeatures(whipany) completions = %q, want none", resp.Completions)
+	}
+
+	// 特征数据库被替换之后搜索新的特征
+	env.Service.SetFeatures([]*pb.Feature{{Name: "Central Park, New York", Location: &pb.Point{Latitude: 407829000, Longitude: -739654000}}})
+	resp, err = env.RouteGuide.SearchFeatures(ctx, &pb.SearchFeaturesRequest{Query: "central pa", Bias: &pb.Point{Latitude: 407829000, Longitude: -739654000}})
+	if err != nil {
+		t.Fatalf("SearchFeatures() = %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].DistanceMeters != 0 || len(resp.Completions) != 1 || resp.Completions[0] != "central park" {
+		t.Errorf("SearchFeatures(central pa) = %v", resp)
+	}
+
+	for _, req := range []*pb.SearchFeaturesRequest{{}, {Query: "x", Limit: 1000}} {
+		if _, err := env.RouteGuide.SearchFeatures(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SearchFeatures(%v) = %v, want InvalidArgument", req, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package service 实现了 RouteGuide 和 Echo 服务，cmd/svc 把它们注册到 gRPC 服务上，
+// servicetest 包用它们在内存中启动服务做集成测试
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+
+	"gRPCDemo/pb"
+)
+
+type loggerCtxKey struct{}
+
+// WithLogger 返回携带 logger 的 ctx，服务端的拦截器用它把带有请求字段的 logger 传给处理函数
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// LoggerFrom 返回 ctx 中携带的 logger，没有的话返回 slog.Default()
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// LoadFeatures 从 JSON 文件中读取特征数据库，文件格式见 testdata/route_guide_db.json
+func LoadFeatures(filename string) ([]*pb.Feature, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read features: %v", err)
+	}
+	var features []*pb.Feature
+	if err := json.Unmarshal(data, &features); err != nil {
+		return nil, fmt.Errorf("parse features in %s: %v", filename, err)
+	}
+	return features, nil
+}
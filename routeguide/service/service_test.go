@@ -0,0 +1,367 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service/servicetest"
+	"gRPCDemo/validate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestGetFeature(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx := testContext(t)
+
+	for _, want := range servicetest.Features() {
+		got, err := env.RouteGuide.GetFeature(ctx, want.Location)
+		if err != nil {
+			t.Fatalf("GetFeature(%v) = %v", want.Location, err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("GetFeature(%v) = %v, want %v", want.Location, got, want)
+		}
+	}
+
+	// 没有特征的位置返回名字为空的 Feature
+	missing := &pb.Point{Latitude: 1, Longitude: 1}
+	got, err := env.RouteGuide.GetFeature(ctx, missing)
+	if err != nil {
+		t.Fatalf("GetFeature(%v) = %v", missing, err)
+	}
+	if got.GetName() != "" || !proto.Equal(got.GetLocation(), missing) {
+		t.Errorf("GetFeature(%v) = %v, want an unnamed feature", missing, got)
+	}
+}
+
+func listFeatures(t *testing.T, c pb.RouteGuideClient, rect *pb.Rectangle) []string {
+	t.Helper()
+	stream, err := c.ListFeatures(testContext(t), rect)
+	if err != nil {
+		t.Fatalf("ListFeatures() = %v", err)
+	}
+	var names []string
+	for {
+		f, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ListFeatures().Recv() = %v", err)
+		}
+		names = append(names, f.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestListFeatures(t *testing.T) {
+	env := servicetest.Start(t)
+
+	// 覆盖 Mendham 和 Whippany 两个特征的矩形
+	lo := &pb.Point{Latitude: 407000000, Longitude: -747000000}
+	hi := &pb.Point{Latitude: 409000000, Longitude: -743000000}
+	want := []string{"101 New Jersey 10, Whippany, NJ 07981, USA", "Patriots Path, Mendham, NJ 07945, USA"}
+
+	for _, rect := range []*pb.Rectangle{{Lo: lo, Hi: hi}, {Lo: hi, Hi: lo}} {
+		if got := listFeatures(t, env.RouteGuide, rect); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("ListFeatures(%v) = %q, want %q", rect, got, want)
+		}
+	}
+
+	empty := &pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{Latitude: 1, Longitude: 1}}
+	if got := listFeatures(t, env.RouteGuide, empty); len(got) != 0 {
+		t.Errorf("ListFeatures(%v) = %q, want none", empty, got)
+	}
+
+	all := &pb.Rectangle{
+		Lo: &pb.Point{Latitude: -900000000, Longitude: -1800000000},
+		Hi: &pb.Point{Latitude: 900000000, Longitude: 1800000000},
+	}
+	if got := listFeatures(t, env.RouteGuide, all); len(got) != len(servicetest.Features()) {
+		t.Errorf("ListFeatures(whole world) returned %d features, want %d", len(got), len(servicetest.Features()))
+	}
+}
+
+// 移到 service 包之前 inRange 总是返回 true，ListFeatures 会返回所有的特征
+func TestListFeaturesExcludesFeaturesOutsideRect(t *testing.T) {
+	env := servicetest.Start(t)
+	for _, f := range servicetest.Features() {
+		rect := &pb.Rectangle{Lo: f.Location, Hi: f.Location}
+		got := listFeatures(t, env.RouteGuide, rect)
+		if len(got) != 1 || got[0] != f.Name {
+			t.Errorf("ListFeatures(%v) = %q, want only %q", rect, got, f.Name)
+		}
+	}
+}
+
+func TestListFeaturesCanceledMidStream(t *testing.T) {
+	features := make([]*pb.Feature, 10000)
+	for i := range features {
+		features[i] = &pb.Feature{Name: fmt.Sprint(i), Location: &pb.Point{Latitude: int32(i)}}
+	}
+	env := servicetest.Start(t, servicetest.WithFeatures(features))
+
+	ctx, cancel := context.WithCancel(testContext(t))
+	stream, err := env.RouteGuide.ListFeatures(ctx, &pb.Rectangle{
+		Lo: &pb.Point{}, Hi: &pb.Point{Latitude: int32(len(features))},
+	})
+	if err != nil {
+		t.Fatalf("ListFeatures() = %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("first Recv() = %v", err)
+	}
+	cancel()
+
+	n := 1
+	for {
+		_, err := stream.Recv()
+		if err == nil {
+			n++
+			continue
+		}
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("Recv() after cancel = %v, want Canceled", err)
+		}
+		break
+	}
+	if n == len(features) {
+		t.Errorf("received all %d features after cancel", n)
+	}
+}
+
+func TestRecordRoute(t *testing.T) {
+	env := servicetest.Start(t)
+	features := servicetest.Features()
+
+	stream, err := env.RouteGuide.RecordRoute(testContext(t))
+	if err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+	points := []*pb.Point{features[0].Location, {Latitude: 408000000, Longitude: -745000000}, features[1].Location}
+	for _, p := range points {
+		if err := stream.Send(p); err != nil {
+			t.Fatalf("Send(%v) = %v", p, err)
+		}
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv() = %v", err)
+	}
+	if summary.PointCount != 3 || summary.FeatureCount != 2 {
+		t.Errorf("summary = %v, want 3 points and 2 features", summary)
+	}
+	// 三个点之间大约相距 20km
+	if summary.Distance < 10000 || summary.Distance > 50000 {
+		t.Errorf("distance = %dm, want about 30km", summary.Distance)
+	}
+	if summary.ElapsedTime < 30 {
+		t.Errorf("elapsed time = %dms, want at least 30ms", summary.ElapsedTime)
+	}
+
+	// 没有发送任何点
+	stream, err = env.RouteGuide.RecordRoute(testContext(t))
+	if err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+	summary, err = stream.CloseAndRecv()
+	if err != nil || summary.PointCount != 0 || summary.Distance != 0 {
+		t.Errorf("empty route = %v, %v, want an empty summary", summary, err)
+	}
+}
+
+func TestRecordRouteCanceled(t *testing.T) {
+	env := servicetest.Start(t)
+	ctx, cancel := context.WithCancel(testContext(t))
+	stream, err := env.RouteGuide.RecordRoute(ctx)
+	if err != nil {
+		t.Fatalf("RecordRoute() = %v", err)
+	}
+	if err := stream.Send(&pb.Point{}); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+	cancel()
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.Canceled {
+		t.Errorf("CloseAndRecv() after cancel = %v, want Canceled", err)
+	}
+}
+
+// TestRouteChatConcurrent 让多个流同时向同一个位置写入，检查 mu 保护的历史记录是否一致
+func TestRouteChatConcurrent(t *testing.T) {
+	const clients, notes = 8, 20
+	env := servicetest.Start(t)
+	location := &pb.Point{Latitude: 1, Longitude: 2}
+
+	// 第一条记录会出现在之后每一次回复的开头
+	first := &pb.RouteNode{Location: location, Message: "first"}
+	chat(t, env.RouteGuide, first)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, clients)
+	for c := 0; c < clients; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			var sent []*pb.RouteNode
+			for i := 0; i < notes; i++ {
+				sent = append(sent, &pb.RouteNode{Location: location, Message: fmt.Sprintf("client %d note %d", c, i)})
+			}
+			got, err := chatErr(env.RouteGuide, sent...)
+			if err != nil {
+				errs <- err
+				return
+			}
+			// 每条消息都会收到一份完整的历史记录，历史记录至少包含这个流自己已经发送的消息
+			var firsts int
+			for _, n := range got {
+				if n.GetMessage() == first.Message {
+					firsts++
+				}
+			}
+			if firsts != notes {
+				errs <- fmt.Errorf("client %d: saw %d histories, want %d", c, firsts, notes)
+				return
+			}
+			if min := 1 + notes*(notes+1)/2; len(got) < min {
+				errs <- fmt.Errorf("client %d: received %d notes, want at least %d", c, len(got), min)
+			}
+		}(c)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	got := chat(t, env.RouteGuide, &pb.RouteNode{Location: location, Message: "last"})
+	if want := 1 + clients*notes + 1; len(got) != want {
+		t.Errorf("history has %d notes, want %d", len(got), want)
+	}
+	if got[0].GetMessage() != first.Message || got[len(got)-1].GetMessage() != "last" {
+		t.Errorf("history = %v ... %v, want first ... last", got[0], got[len(got)-1])
+	}
+
+	// 其他位置的历史记录不受影响
+	other := chat(t, env.RouteGuide, &pb.RouteNode{Location: &pb.Point{Latitude: 3}, Message: "other"})
+	if len(other) != 1 {
+		t.Errorf("history at another location has %d notes, want 1", len(other))
+	}
+}
+
+func chat(t *testing.T, c pb.RouteGuideClient, notes ...*pb.RouteNode) []*pb.RouteNode {
+	t.Helper()
+	got, err := chatErr(c, notes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+// chatErr 发送 notes 之后关闭发送端，返回收到的所有消息
+func chatErr(c pb.RouteGuideClient, notes ...*pb.RouteNode) ([]*pb.RouteNode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := c.RouteChat(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, n := range notes {
+			if err := stream.Send(n); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	var got []*pb.RouteNode
+	for {
+		n, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		got = append(got, n)
+	}
+	return got, <-sendErr
+}
+
+func TestEchoConversations(t *testing.T) {
+	env := servicetest.Start(t)
+	stream, err := env.Echo.Conversations(testContext(t))
+	if err != nil {
+		t.Fatalf("Conversations() = %v", err)
+	}
+	for i, q := range []string{"a", "b", "c"} {
+		if err := stream.Send(&pb.StreamRequest{Question: q}); err != nil {
+			t.Fatalf("Send() = %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() = %v", err)
+		}
+		if want := fmt.Sprintf("Answer: %d, Question: %s", i+1, q); resp.Answer != want {
+			t.Errorf("answer = %q, want %q", resp.Answer, want)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv() after CloseSend = %v, want EOF", err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	env := servicetest.Start(t, servicetest.WithServerOptions(
+		grpc.UnaryInterceptor(validate.UnaryServerInterceptor),
+		grpc.StreamInterceptor(validate.StreamServerInterceptor),
+	))
+	ctx := testContext(t)
+
+	_, err := env.RouteGuide.GetFeature(ctx, &pb.Point{Latitude: 1 << 30})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetFeature(out of range) = %v, want InvalidArgument", err)
+	}
+
+	stream, err := env.RouteGuide.ListFeatures(ctx, &pb.Rectangle{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListFeatures(empty rectangle) = %v, want InvalidArgument", err)
+	}
+
+	_, err = chatErr(env.RouteGuide, &pb.RouteNode{Message: "nowhere"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RouteChat(no location) = %v, want InvalidArgument", err)
+	}
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := env.RouteGuide.GetFeature(expired, &pb.Point{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("GetFeature(expired ctx) = %v, want DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package servicetest 在内存中的 bufconn 上启动真实的 RouteGuide 和 Echo 服务，用于集成测试
+//
+//	env := servicetest.Start(t)
+//	feature, err := env.RouteGuide.GetFeature(ctx, point)
+//
+// 默认的特征数据库是 Features() 返回的测试数据，服务和连接会在测试结束时关闭
+package servicetest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/routeguide/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+//go:embed testdata/features.json
+var featuresJSON []byte
+
+// Features 返回测试用的特征数据库，取自 testdata/route_guide_db.json，最后一个特征没有名字。
+// 每次调用都返回新的副本，修改它不会影响其他测试
+func Features() []*pb.Feature {
+	var features []*pb.Feature
+	if err := json.Unmarshal(featuresJSON, &features); err != nil {
+		panic("servicetest: invalid fixture: " + err.Error())
+	}
+	return features
+}
+
+// Env 是一个运行中的测试服务
+type Env struct {
+	// Conn 是连接到测试服务的客户端连接
+	Conn       *grpc.ClientConn
+	RouteGuide pb.RouteGuideClient
+	Echo       pb.EchoClient
+	// Service 是服务端的 RouteGuide 实例，可以用来替换特征数据库
+	Service *service.RouteGuide
+	// EchoService 是服务端的 Echo 实例，可以用来设置脚本
+	EchoService *service.Echo
+}
+
+type options struct {
+	features      []*pb.Feature
+	serverOptions []grpc.ServerOption
+	dialOptions   []grpc.DialOption
+	services      []registration
+}
+
+type registration struct {
+	desc *grpc.ServiceDesc
+	impl interface{}
+}
+
+// Option 配置 Start 启动的测试服务
+type Option func(*options)
+
+// WithFeatures 使用 features 作为特征数据库
+func WithFeatures(features []*pb.Feature) Option {
+	return func(o *options) {
+		o.features = features
+	}
+}
+
+// WithServerOptions 添加服务端选项，例如 cmd/svc 中使用的拦截器
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *options) {
+		o.serverOptions = append(o.serverOptions, opts...)
+	}
+}
+
+// WithDialOptions 添加客户端选项
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// WithService 在测试服务上额外注册一个服务，例如 FaultInjection
+func WithService(desc *grpc.ServiceDesc, impl interface{}) Option {
+	return func(o *options) {
+		o.services = append(o.services, registration{desc: desc, impl: impl})
+	}
+}
+
+// Start 启动测试服务，测试结束时服务会被立即停止，不等待还没有结束的流
+func Start(t testing.TB, opts ...Option) *Env {
+	t.Helper()
+	o := options{features: Features()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(o.serverOptions...)
+	rg := service.NewRouteGuide()
+	rg.SetFeatures(o.features)
+	pb.RegisterRouteGuideServer(s, rg)
+	echo := service.NewEcho()
+	pb.RegisterEchoServer(s, echo)
+	for _, svc := range o.services {
+		s.RegisterService(svc.desc, svc.impl)
+	}
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, o.dialOptions...)
+	conn, err := grpc.Dial("bufconn", dialOpts...)
+	if err != nil {
+		t.Fatalf("servicetest: grpc.Dial() = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &Env{
+		Conn:        conn,
+		RouteGuide:  pb.NewRouteGuideClient(conn),
+		Echo:        pb.NewEchoClient(conn),
+		Service:     rg,
+		EchoService: echo,
+	}
+}
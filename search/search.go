@@ -0,0 +1,352 @@
+// Package search 是特征名字的全文索引，支持前缀、自动补全和模糊匹配
+//
+//	idx := search.NewIndex(features)
+//	results, completions := idx.Search(search.Query{Text: "new jer", Bias: point})
+//
+// 名字被切分成小写的词，查询中的每个词都必须匹配名字中的某个词：完全相同，或者编辑距离足够小。
+// 查询的最后一个词后面没有空格时，它还可以是名字中的词的前缀，这样在用户输入的同时就可以返回结果。
+// Index 创建之后不会被修改，可以被多个 goroutine 同时使用
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gRPCDemo/geo"
+	"gRPCDemo/pb"
+)
+
+// 查询的默认值和上限
+const (
+	DefaultLimit     = 10
+	MaxLimit         = 100
+	DefaultBiasScale = 10000.0
+	// maxCompletions 是返回的补全的最大数量
+	maxCompletions = 5
+)
+
+// BM25 的参数
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// 不同的匹配方式的权重，完全匹配是 1
+const (
+	prefixWeight = 0.5
+	fuzzyPenalty = 0.3
+)
+
+// Query 是一次查询
+type Query struct {
+	Text string
+	// Bias 不为空时离它越近的特征排名越高
+	Bias *pb.Point
+	// BiasScale 是距离的影响范围，单位是米，为 0 时使用 DefaultBiasScale。
+	// 距离 Bias 为 BiasScale 的特征的分数是同样相关但是就在 Bias 处的特征的 75%
+	BiasScale float64
+	// Limit 是返回结果的最大数量，为 0 时使用 DefaultLimit，超过 MaxLimit 时按 MaxLimit 处理
+	Limit int
+}
+
+// Result 是一个匹配的特征
+type Result struct {
+	Feature *pb.Feature
+	// Score 是相关性和距离综合之后的分数，越大越好
+	Score float64
+	// Distance 是到 Query.Bias 的距离，没有 Bias 时为 0
+	Distance int32
+}
+
+type posting struct {
+	doc int
+	tf  int
+}
+
+// Index 是特征名字的倒排索引
+type Index struct {
+	features []*pb.Feature
+	// lengths 是每个特征的名字中词的数量
+	lengths   []int
+	avgLength float64
+	postings  map[string][]posting
+	// terms 是所有的词，按字典序排列，用来查找前缀
+	terms []string
+}
+
+// NewIndex 为 features 中有名字的特征建立索引
+func NewIndex(features []*pb.Feature) *Index {
+	idx := &Index{
+		features: features,
+		lengths:  make([]int, len(features)),
+		postings: make(map[string][]posting),
+	}
+	total := 0
+	for doc, feature := range features {
+		tokens := Tokenize(feature.GetName())
+		idx.lengths[doc] = len(tokens)
+		total += len(tokens)
+		tf := make(map[string]int)
+		for _, t := range tokens {
+			tf[t]++
+		}
+		for t, n := range tf {
+			idx.postings[t] = append(idx.postings[t], posting{doc: doc, tf: n})
+		}
+	}
+	for t := range idx.postings {
+		idx.terms = append(idx.terms, t)
+	}
+	sort.Strings(idx.terms)
+	if len(features) > 0 {
+		idx.avgLength = float64(total) / float64(len(features))
+	}
+	return idx
+}
+
+// Tokenize 把 s 切分成小写的词，字母和数字之外的字符都是分隔符
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// match 是查询中的一个词匹配到的索引中的词
+type match struct {
+	term   string
+	weight float64
+}
+
+// Search 返回匹配 q 的特征，按分数从高到低排列，以及补全查询中最后一个词得到的查询
+func (idx *Index) Search(q Query) ([]Result, []string) {
+	tokens := Tokenize(q.Text)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	// 最后一个词后面有分隔符时说明用户已经输入完了这个词
+	last := []rune(q.Text)
+	partial := len(last) > 0 && (unicode.IsLetter(last[len(last)-1]) || unicode.IsDigit(last[len(last)-1]))
+
+	scores := make(map[int]float64)
+	for i, token := range tokens {
+		prefix := partial && i == len(tokens)-1
+		best := make(map[int]float64)
+		for _, m := range idx.matches(token, prefix) {
+			idf := idx.idf(m.term)
+			for _, p := range idx.postings[m.term] {
+				s := m.weight * idf * idx.tfNorm(p)
+				if s > best[p.doc] {
+					best[p.doc] = s
+				}
+			}
+		}
+		// 每个词都必须匹配
+		if i == 0 {
+			scores = best
+			continue
+		}
+		for doc, s := range scores {
+			if t, ok := best[doc]; ok {
+				scores[doc] = s + t
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	var completions []string
+	if partial {
+		completions = idx.complete(tokens, scores)
+	}
+
+	scale := q.BiasScale
+	if scale <= 0 {
+		scale = DefaultBiasScale
+	}
+	// 按 doc 的顺序生成结果再稳定排序，分数和名字都相同的特征按照在索引中的顺序返回
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	sort.Ints(docs)
+	results := make([]Result, 0, len(scores))
+	for _, doc := range docs {
+		r := Result{Feature: idx.features[doc], Score: scores[doc]}
+		if q.Bias != nil {
+			r.Distance = geo.Distance(q.Bias, r.Feature.Location)
+			r.Score *= 0.5 + 0.5*scale/(scale+float64(r.Distance))
+		}
+		results = append(results, r)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Feature.GetName() < results[j].Feature.GetName()
+	})
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, completions
+}
+
+// matches 返回 token 可以匹配的索引中的词：完全相同的词，prefix 为 true 时以 token 开头的词，
+// 以及编辑距离在 maxEdits(token) 以内的词
+func (idx *Index) matches(token string, prefix bool) []match {
+	var ms []match
+	if _, ok := idx.postings[token]; ok {
+		ms = append(ms, match{term: token, weight: 1})
+	}
+	if prefix {
+		for _, term := range idx.withPrefix(token) {
+			if term != token {
+				// 补全的部分越短权重越高
+				ms = append(ms, match{term: term, weight: prefixWeight + prefixWeight*float64(len(token))/float64(len(term))})
+			}
+		}
+	}
+	if edits := maxEdits(token); edits > 0 {
+		t := []rune(token)
+		for _, term := range idx.terms {
+			if term == token || (prefix && strings.HasPrefix(term, token)) {
+				continue
+			}
+			if d := editDistance(t, []rune(term), edits); d <= edits {
+				ms = append(ms, match{term: term, weight: 1 - fuzzyPenalty*float64(d)})
+			}
+		}
+	}
+	return ms
+}
+
+// withPrefix 返回所有以 prefix 开头的词
+func (idx *Index) withPrefix(prefix string) []string {
+	i := sort.SearchStrings(idx.terms, prefix)
+	j := i
+	for j < len(idx.terms) && strings.HasPrefix(idx.terms[j], prefix) {
+		j++
+	}
+	return idx.terms[i:j]
+}
+
+// complete 用匹配的特征中出现最多的词补全最后一个词
+func (idx *Index) complete(tokens []string, docs map[int]float64) []string {
+	last := tokens[len(tokens)-1]
+	type completion struct {
+		term string
+		n    int
+	}
+	var cs []completion
+	for _, term := range idx.withPrefix(last) {
+		n := 0
+		for _, p := range idx.postings[term] {
+			if _, ok := docs[p.doc]; ok {
+				n++
+			}
+		}
+		if n > 0 && term != last {
+			cs = append(cs, completion{term, n})
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].n != cs[j].n {
+			return cs[i].n > cs[j].n
+		}
+		return cs[i].term < cs[j].term
+	})
+	if len(cs) > maxCompletions {
+		cs = cs[:maxCompletions]
+	}
+	head := strings.Join(tokens[:len(tokens)-1], " ")
+	completions := make([]string, len(cs))
+	for i, c := range cs {
+		completions[i] = strings.TrimSpace(head + " " + c.term)
+	}
+	return completions
+}
+
+func (idx *Index) idf(term string) float64 {
+	n, df := float64(len(idx.features)), float64(len(idx.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// tfNorm 是 BM25 中词频的部分，名字越长一个词的权重越低
+func (idx *Index) tfNorm(p posting) float64 {
+	tf := float64(p.tf)
+	return tf * (k1 + 1) / (tf + k1*(1-b+b*float64(idx.lengths[p.doc])/idx.avgLength))
+}
+
+// maxEdits 是 token 允许的编辑距离，短的词容易误匹配，不做模糊匹配
+func maxEdits(token string) int {
+	switch n := len([]rune(token)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance 返回 a 和 b 之间的编辑距离，相邻字符的交换算作一次编辑。
+// 距离超过 limit 时返回 limit+1
+func editDistance(a, b []rune, limit int) int {
+	if d := len(a) - len(b); d > limit || -d > limit {
+		return limit + 1
+	}
+	// prev2, prev, cur 是动态规划表中相邻的三行
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	// 交换可以跳过一行，所以相邻的两行都超过 limit 时才能提前结束
+	prevMin := 0
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > limit && prevMin > limit {
+			return limit + 1
+		}
+		prevMin = rowMin
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(b)] > limit {
+		return limit + 1
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
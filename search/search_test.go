@@ -0,0 +1,227 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"gRPCDemo/pb"
+)
+
+func loadIndex(t *testing.T) *Index {
+	t.Helper()
+	data, err := ioutil.ReadFile("../testdata/route_guide_db.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var features []*pb.Feature
+	if err := json.Unmarshal(data, &features); err != nil {
+		t.Fatal(err)
+	}
+	return NewIndex(features)
+}
+
+func names(results []Result) []string {
+	var ns []string
+	for _, r := range results {
+		ns = append(ns, r.Feature.GetName())
+	}
+	return ns
+}
+
+func TestTokenize(t *testing.T) {
+	tests := map[string]string{
+		"101 New Jersey 10, Whippany, NJ 07981, USA": "[101 new jersey 10 whippany nj 07981 usa]",
+		"U.S. 6, Shohola":        "[u s 6 shohola]",
+		"193-199 Wawayanda Road": "[193 199 wawayanda road]",
+		"Café  Zürich":           "[café zürich]",
+		" ,- ":                   "[]",
+	}
+	for in, want := range tests {
+		if got := fmt.Sprint(Tokenize(in)); got != want {
+			t.Errorf("Tokenize(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+// osa 是不提前结束的编辑距离，用来检查 editDistance
+func osa(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"whippany", "whippany", 0},
+		{"whipany", "whippany", 1},
+		{"whippnay", "whippany", 1},
+		{"wihpany", "whippany", 2},
+		{"kingston", "livingston", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b), 2); got != min3(tt.want, 3, 3) {
+			t.Errorf("editDistance(%q, %q, 2) = %d, want %d", tt.a, tt.b, got, min3(tt.want, 3, 3))
+		}
+	}
+
+	// 随机的短字符串，字母表很小，这样容易出现交换和重复
+	word := func(r *rand.Rand) []rune {
+		w := make([]rune, r.Intn(8))
+		for i := range w {
+			w[i] = rune('a' + r.Intn(3))
+		}
+		return w
+	}
+	err := quick.Check(func(seed int64, limit uint8) bool {
+		r := rand.New(rand.NewSource(seed))
+		a, b, l := word(r), word(r), int(limit%4)
+		want := osa(a, b)
+		if want > l {
+			want = l + 1
+		}
+		return editDistance(a, b, l) == want
+	}, &quick.Config{MaxCount: 5000})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	idx := loadIndex(t)
+
+	tests := []struct {
+		query string
+		// want 是第一个结果的名字中包含的字符串
+		want string
+	}{
+		{"whippany", "Whippany"},
+		{"WHIPPANY nj", "Whippany"},
+		{"whipany", "Whippany"},
+		{"wihppany", "Whippany"},
+		{"kingston", "Kingston"},
+		{"berkshire val", "Berkshire Valley"},
+		{"patriots", "Patriots Path"},
+		{"07981", "Whippany"},
+	}
+	for _, tt := range tests {
+		results, _ := idx.Search(Query{Text: tt.query})
+		if len(results) == 0 || !strings.Contains(results[0].Feature.GetName(), tt.want) {
+			t.Errorf("Search(%q) = %q, want %q first", tt.query, names(results), tt.want)
+		}
+	}
+
+	// 每个词都必须匹配
+	if results, _ := idx.Search(Query{Text: "kingston nj"}); len(results) != 0 {
+		t.Errorf("Search(kingston nj) = %q, want nothing", names(results))
+	}
+	// 短的词不做模糊匹配，完整输入的词不做前缀匹配
+	if results, _ := idx.Search(Query{Text: "nk "}); len(results) != 0 {
+		t.Errorf("Search(nk) = %q, want nothing", names(results))
+	}
+	if results, _ := idx.Search(Query{Text: "whipp "}); len(results) != 0 {
+		t.Errorf("Search(whipp ) = %q, want nothing", names(results))
+	}
+	if results, _ := idx.Search(Query{Text: " , "}); results != nil {
+		t.Errorf("Search(no tokens) = %q, want nil", names(results))
+	}
+}
+
+func TestSearchCompletions(t *testing.T) {
+	idx := loadIndex(t)
+
+	results, completions := idx.Search(Query{Text: "new jer"})
+	if len(completions) == 0 || completions[0] != "new jersey" {
+		t.Errorf("Search(new jer) completions = %q, want new jersey first", completions)
+	}
+	for _, r := range results {
+		if !strings.Contains(strings.ToLower(r.Feature.GetName()), "jer") {
+			t.Errorf("Search(new jer) returned %q", r.Feature.GetName())
+		}
+	}
+	// 补全只来自匹配前面的词的特征
+	_, completions = idx.Search(Query{Text: "kingston r"})
+	if fmt.Sprint(completions) != "[kingston road]" {
+		t.Errorf("Search(kingston r) completions = %q, want [kingston road]", completions)
+	}
+	if _, completions := idx.Search(Query{Text: "road "}); completions != nil {
+		t.Errorf("Search(road ) completions = %q, want none", completions)
+	}
+}
+
+func TestSearchTies(t *testing.T) {
+	var features []*pb.Feature
+	for i := 0; i < 8; i++ {
+		features = append(features, &pb.Feature{Name: "Main Street", Location: &pb.Point{Latitude: int32(i)}})
+	}
+	idx := NewIndex(features)
+
+	// 分数和名字都相同时按照在索引中的顺序返回，每次都一样
+	for n := 0; n < 20; n++ {
+		results, _ := idx.Search(Query{Text: "main street"})
+		if len(results) != len(features) {
+			t.Fatalf("Search(main street) = %d results, want %d", len(results), len(features))
+		}
+		for i, r := range results {
+			if r.Feature != features[i] {
+				t.Fatalf("Search(main street) result %d = %v, want %v", i, r.Feature.Location, features[i].Location)
+			}
+		}
+	}
+}
+
+func TestSearchBias(t *testing.T) {
+	idx := loadIndex(t)
+
+	results, _ := idx.Search(Query{Text: "road", Limit: MaxLimit})
+	if len(results) < 2 {
+		t.Fatalf("Search(road) = %q, want many results", names(results))
+	}
+	// 排在最后的结果在 bias 旁边时会排到最前面
+	target := results[len(results)-1].Feature
+	biased, _ := idx.Search(Query{Text: "road", Bias: target.Location, Limit: MaxLimit})
+	if biased[0].Feature != target || biased[0].Distance != 0 {
+		t.Errorf("Search(road, bias %v) = %q, want %q first", target.Location, names(biased[:3]), target.GetName())
+	}
+	for i := 1; i < len(biased); i++ {
+		if biased[i].Score > biased[i-1].Score {
+			t.Fatalf("results are not ordered by score: %v > %v", biased[i].Score, biased[i-1].Score)
+		}
+	}
+	if len(results) != len(biased) {
+		t.Errorf("bias changed the number of results from %d to %d", len(results), len(biased))
+	}
+
+	if results, _ := idx.Search(Query{Text: "road", Limit: 3}); len(results) != 3 {
+		t.Errorf("Search(road, limit 3) returned %d results", len(results))
+	}
+	if results, _ := idx.Search(Query{Text: "road"}); len(results) != DefaultLimit {
+		t.Errorf("Search(road) returned %d results, want %d", len(results), DefaultLimit)
+	}
+}
@@ -0,0 +1,101 @@
+package validate
+
+import "fmt"
+
+type number interface {
+	~int32 | ~int64 | ~uint32 | ~float64
+}
+
+// numberRules 是 Int32Rules, Int64Rules, UInt32Rules 和 DoubleRules 共同的规则，nil 表示没有设置
+type numberRules[T number] struct {
+	konst, lt, lte, gt, gte *T
+	in, notIn               []T
+}
+
+func (r numberRules[T]) check(v T) string {
+	switch {
+	case r.konst != nil && v != *r.konst:
+		return fmt.Sprintf("value must equal %v", *r.konst)
+	case r.lt != nil && !(v < *r.lt):
+		return fmt.Sprintf("value must be less than %v", *r.lt)
+	case r.lte != nil && !(v <= *r.lte):
+		return fmt.Sprintf("value must be less than or equal to %v", *r.lte)
+	case r.gt != nil && !(v > *r.gt):
+		return fmt.Sprintf("value must be greater than %v", *r.gt)
+	case r.gte != nil && !(v >= *r.gte):
+		return fmt.Sprintf("value must be greater than or equal to %v", *r.gte)
+	case len(r.in) > 0 && !contains(r.in, v):
+		return fmt.Sprintf("value must be in list %v", r.in)
+	case contains(r.notIn, v):
+		return fmt.Sprintf("value must not be in list %v", r.notIn)
+	}
+	return ""
+}
+
+func int32Rules(r *Int32Rules) numberRules[int32] {
+	n := numberRules[int32]{konst: r.Const, in: r.In, notIn: r.NotIn}
+	switch b := r.LessThan.(type) {
+	case *Int32Rules_Lt:
+		n.lt = &b.Lt
+	case *Int32Rules_Lte:
+		n.lte = &b.Lte
+	}
+	switch b := r.GreaterThan.(type) {
+	case *Int32Rules_Gt:
+		n.gt = &b.Gt
+	case *Int32Rules_Gte:
+		n.gte = &b.Gte
+	}
+	return n
+}
+
+func int64Rules(r *Int64Rules) numberRules[int64] {
+	n := numberRules[int64]{konst: r.Const, in: r.In, notIn: r.NotIn}
+	switch b := r.LessThan.(type) {
+	case *Int64Rules_Lt:
+		n.lt = &b.Lt
+	case *Int64Rules_Lte:
+		n.lte = &b.Lte
+	}
+	switch b := r.GreaterThan.(type) {
+	case *Int64Rules_Gt:
+		n.gt = &b.Gt
+	case *Int64Rules_Gte:
+		n.gte = &b.Gte
+	}
+	return n
+}
+
+func uint32Rules(r *UInt32Rules) numberRules[uint32] {
+	n := numberRules[uint32]{konst: r.Const, in: r.In, notIn: r.NotIn}
+	switch b := r.LessThan.(type) {
+	case *UInt32Rules_Lt:
+		n.lt = &b.Lt
+	case *UInt32Rules_Lte:
+		n.lte = &b.Lte
+	}
+	switch b := r.GreaterThan.(type) {
+	case *UInt32Rules_Gt:
+		n.gt = &b.Gt
+	case *UInt32Rules_Gte:
+		n.gte = &b.Gte
+	}
+	return n
+}
+
+func doubleRules(r *DoubleRules) numberRules[float64] {
+	n := numberRules[float64]{konst: r.Const, in: r.In, notIn: r.NotIn}
+	switch b := r.LessThan.(type) {
+	case *DoubleRules_Lt:
+		n.lt = &b.Lt
+	case *DoubleRules_Lte:
+		n.lte = &b.Lte
+	}
+	switch b := r.GreaterThan.(type) {
+	case *DoubleRules_Gt:
+		n.gt = &b.Gt
+	case *DoubleRules_Gte:
+		n.gte = &b.Gte
+	}
+	return n
+}
@@ -0,0 +1,208 @@
+// Package validate 检查 proto 消息是否满足 routeguide.validate 注解声明的规则
+//
+// 规则写在 .proto 文件的字段选项中，例如
+//
+//	int32 latitude = 1 [(routeguide.validate.field).int32 = {gte: -900000000, lte: 900000000}];
+//
+// 支持的规则见 validate/validate.proto。消息类型的字段会递归检查，
+// 不满足规则的字段通过 errdetails.BadRequest 返回给客户端。
+package validate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Validate 检查 msg 及其中的所有消息字段，不满足规则时返回 codes.InvalidArgument 错误，
+// 错误中的 BadRequest 包含所有不满足规则的字段
+func Validate(msg proto.Message) error {
+	violations := Violations(msg)
+	if len(violations) == 0 {
+		return nil
+	}
+	descs := make([]string, 0, len(violations))
+	for _, v := range violations {
+		descs = append(descs, v.Field+": "+v.Description)
+	}
+	st := status.Newf(codes.InvalidArgument, "invalid %s: %s",
+		msg.ProtoReflect().Descriptor().Name(), strings.Join(descs, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// Violations 返回 msg 中所有不满足规则的字段，字段路径形如 lo.latitude 或者 points[0].latitude
+func Violations(msg proto.Message) []*errdetails.BadRequest_FieldViolation {
+	var v validator
+	v.message(msg.ProtoReflect(), "")
+	return v.violations
+}
+
+// constraints 缓存每个字段上声明的规则，没有规则的字段对应 nil
+var constraints sync.Map // protoreflect.FieldDescriptor -> *FieldConstraints
+
+func constraintsOf(fd protoreflect.FieldDescriptor) *FieldConstraints {
+	if c, ok := constraints.Load(fd); ok {
+		return c.(*FieldConstraints)
+	}
+	var c *FieldConstraints
+	if opts := fd.Options(); opts != nil && proto.HasExtension(opts, E_Field) {
+		c, _ = proto.GetExtension(opts, E_Field).(*FieldConstraints)
+	}
+	constraints.Store(fd, c)
+	return c
+}
+
+type validator struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{
+		Field:       path,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) message(m protoreflect.Message, prefix string) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		c := constraintsOf(fd)
+
+		if c.GetRequired() && !m.Has(fd) {
+			v.add(path, "value is required")
+			continue
+		}
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			if r := c.GetRepeated(); r != nil {
+				v.repeated(path, r, list.Len())
+			}
+			if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+				for j := 0; j < list.Len(); j++ {
+					v.message(list.Get(j).Message(), fmt.Sprintf("%s[%d].", path, j))
+				}
+			}
+		case fd.IsMap():
+			// map 字段暂时没有规则
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			if m.Has(fd) {
+				v.message(m.Get(fd).Message(), path+".")
+			}
+		case c != nil:
+			v.scalar(path, fd, c, m.Get(fd))
+		}
+	}
+}
+
+func (v *validator) scalar(path string, fd protoreflect.FieldDescriptor, c *FieldConstraints, value protoreflect.Value) {
+	var desc string
+	switch {
+	case c.GetInt32() != nil:
+		desc = int32Rules(c.GetInt32()).check(int32(value.Int()))
+	case c.GetInt64() != nil:
+		desc = int64Rules(c.GetInt64()).check(value.Int())
+	case c.GetUint32() != nil:
+		desc = uint32Rules(c.GetUint32()).check(uint32(value.Uint()))
+	case c.GetDouble() != nil:
+		r := c.GetDouble()
+		f := value.Float()
+		if r.GetFinite() && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			desc = "value must be finite"
+		} else {
+			desc = doubleRules(r).check(f)
+		}
+	case c.GetString_() != nil:
+		desc = checkString(c.GetString_(), value.String())
+	case c.GetEnum() != nil:
+		desc = checkEnum(c.GetEnum(), fd.Enum(), int32(value.Enum()))
+	}
+	if desc != "" {
+		v.add(path, "%s", desc)
+	}
+}
+
+func (v *validator) repeated(path string, r *RepeatedRules, n int) {
+	if r.MinItems != nil && uint64(n) < r.GetMinItems() {
+		v.add(path, "value must contain at least %d item(s)", r.GetMinItems())
+	}
+	if r.MaxItems != nil && uint64(n) > r.GetMaxItems() {
+		v.add(path, "value must contain no more than %d item(s)", r.GetMaxItems())
+	}
+}
+
+// patterns 缓存编译过的正则表达式
+var patterns sync.Map // string -> *regexp.Regexp
+
+func checkString(r *StringRules, s string) string {
+	n := uint64(utf8.RuneCountInString(s))
+	switch {
+	case r.Const != nil && s != r.GetConst():
+		return fmt.Sprintf("value must equal %q", r.GetConst())
+	case r.Len != nil && n != r.GetLen():
+		return fmt.Sprintf("value length must be %d characters", r.GetLen())
+	case r.MinLen != nil && n < r.GetMinLen():
+		return fmt.Sprintf("value length must be at least %d characters", r.GetMinLen())
+	case r.MaxLen != nil && n > r.GetMaxLen():
+		return fmt.Sprintf("value length must be at most %d characters", r.GetMaxLen())
+	case r.Prefix != nil && !strings.HasPrefix(s, r.GetPrefix()):
+		return fmt.Sprintf("value does not have prefix %q", r.GetPrefix())
+	case r.Suffix != nil && !strings.HasSuffix(s, r.GetSuffix()):
+		return fmt.Sprintf("value does not have suffix %q", r.GetSuffix())
+	case len(r.In) > 0 && !contains(r.In, s):
+		return fmt.Sprintf("value must be in list %v", r.In)
+	case contains(r.NotIn, s):
+		return fmt.Sprintf("value must not be in list %v", r.NotIn)
+	}
+	if r.Pattern != nil {
+		re, ok := patterns.Load(r.GetPattern())
+		if !ok {
+			compiled, err := regexp.Compile(r.GetPattern())
+			if err != nil {
+				return fmt.Sprintf("invalid pattern %q: %v", r.GetPattern(), err)
+			}
+			re, _ = patterns.LoadOrStore(r.GetPattern(), compiled)
+		}
+		if !re.(*regexp.Regexp).MatchString(s) {
+			return fmt.Sprintf("value does not match regex pattern %q", r.GetPattern())
+		}
+	}
+	return ""
+}
+
+func checkEnum(r *EnumRules, ed protoreflect.EnumDescriptor, n int32) string {
+	switch {
+	case r.Const != nil && n != r.GetConst():
+		return fmt.Sprintf("value must equal %d", r.GetConst())
+	case r.GetDefinedOnly() && ed.Values().ByNumber(protoreflect.EnumNumber(n)) == nil:
+		return "value must be one of the defined enum values"
+	case len(r.In) > 0 && !contains(r.In, n):
+		return fmt.Sprintf("value must be in list %v", r.In)
+	case contains(r.NotIn, n):
+		return fmt.Sprintf("value must not be in list %v", r.NotIn)
+	}
+	return ""
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package validate_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gRPCDemo/pb"
+	"gRPCDemo/validate"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func fields(violations []*errdetails.BadRequest_FieldViolation) string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Field)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want string
+	}{
+		{"valid point", &pb.Point{Latitude: 407838351, Longitude: -746143763}, ""},
+		{"boundary point", &pb.Point{Latitude: -900000000, Longitude: 1800000000}, ""},
+		{"out of range", &pb.Point{Latitude: 900000001, Longitude: -1800000001}, "latitude,longitude"},
+		{"missing corners", &pb.Rectangle{}, "lo,hi"},
+		{"nested", &pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{Latitude: 1 << 30}}, "hi.latitude"},
+		{"empty note", &pb.RouteNode{Location: &pb.Point{}}, "message"},
+		{"long note", &pb.RouteNode{Location: &pb.Point{}, Message: strings.Repeat("路", 1025)}, "message"},
+		{"max note", &pb.RouteNode{Location: &pb.Point{}, Message: strings.Repeat("路", 1024)}, ""},
+		{"note without location", &pb.RouteNode{Message: "hi"}, "location"},
+		{"empty question", &pb.StreamRequest{}, "question"},
+		{"question", &pb.StreamRequest{Question: "hi"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(validate.Violations(tt.msg)); got != tt.want {
+				t.Errorf("Violations(%v) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReturnsBadRequest(t *testing.T) {
+	err := validate.Validate(&pb.Rectangle{Lo: &pb.Point{Longitude: 2000000000}})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Validate() code = %v, want InvalidArgument", st.Code())
+	}
+	var br *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if d, ok := d.(*errdetails.BadRequest); ok {
+			br = d
+		}
+	}
+	if br == nil {
+		t.Fatalf("Validate() details = %v, want BadRequest", st.Details())
+	}
+	if got := fields(br.FieldViolations); got != "lo.longitude,hi" {
+		t.Errorf("field violations = %q, want %q", got, "lo.longitude,hi")
+	}
+	if validate.Validate(&pb.Rectangle{Lo: &pb.Point{}, Hi: &pb.Point{}}) != nil {
+		t.Error("Validate() of a valid rectangle returned an error")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return req, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/routeguide.RouteGuide/GetFeature"}
+	_, err := validate.UnaryServerInterceptor(context.Background(), &pb.Point{Latitude: -1 << 30}, info, handler)
+	if status.Code(err) != codes.InvalidArgument || called {
+		t.Errorf("interceptor = %v, handler called %v, want InvalidArgument without calling the handler", err, called)
+	}
+	if _, err := validate.UnaryServerInterceptor(context.Background(), &pb.Point{}, info, handler); err != nil || !called {
+		t.Errorf("interceptor = %v, handler called %v, want the handler to be called", err, called)
+	}
+}